@@ -3,20 +3,23 @@ package main
 import (
 	"fmt"
 	"log"
-	"time"
+	"os"
+	"os/exec"
 	"triply-server/internal/config"
+	"triply-server/internal/database"
 	"triply-server/internal/handlers"
 	"triply-server/internal/middleware"
-	"triply-server/internal/models"
+	"triply-server/internal/realtime"
 	"triply-server/internal/repository"
 	"triply-server/internal/service"
+	"triply-server/internal/storage"
+	"triply-server/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 func main() {
@@ -32,19 +35,21 @@ func main() {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	// Auto-migrate models
-	if err := autoMigrate(db); err != nil {
-		log.Fatalf("Failed to migrate database: %v", err)
+	// Subcommands (e.g. `triply-server migrate up`) run and exit without starting the server
+	if len(os.Args) > 1 {
+		if err := runCommand(db, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
-	// Seed demo data
-	if err := seedDemoData(db); err != nil {
-		log.Printf("Failed to seed demo data: %v", err)
+	// Refuse to start against a schema that has not been migrated
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
 	}
-
-	// Seed public trips
-	if err := seedPublicTrips(db); err != nil {
-		log.Printf("Failed to seed public trips: %v", err)
+	if err := migrator.RequireCurrent(); err != nil {
+		log.Fatalf("Database schema is not up to date: %v", err)
 	}
 
 	// Initialize repositories
@@ -53,25 +58,79 @@ func main() {
 	publicTripRepo := repository.NewPublicTripRepository(db)
 	activityRepo := repository.NewActivityRepository(db)
 	tripLikeRepo := repository.NewTripLikeRepository(db)
+	importRepo := repository.NewImportRepository(db)
+	dayPlanRepo := repository.NewDayPlanRepository(db)
+	destinationRepo := repository.NewDestinationRepository(db)
+	tripRevisionRepo := repository.NewTripRevisionRepository(db)
+	calendarTokenRepo := repository.NewCalendarTokenRepository(db)
+	tripMemberRepo := repository.NewTripMemberRepository(db)
+	tripInvitationRepo := repository.NewTripInvitationRepository(db)
+	tripExpenseRepo := repository.NewTripExpenseRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	checklistRepo := repository.NewChecklistRepository(db)
+	tripLegRepo := repository.NewTripLegRepository(db)
+	tripReservationRepo := repository.NewTripReservationRepository(db)
+	tripAttachmentRepo := repository.NewTripAttachmentRepository(db)
+
+	// Live trip change events (in-process; swap for a pub/sub backed hub when running several instances)
+	eventHub := realtime.NewMemoryHub()
+
+	// Uploaded files
+	blobStore, err := newBlobStore(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to open file storage: %v", err)
+	}
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo)
-	tripService := service.NewTripService(tripRepo, publicTripRepo)
-	publicTripService := service.NewPublicTripService(publicTripRepo, tripRepo, tripLikeRepo)
-	activityService := service.NewActivityService(activityRepo)
-	importService := service.NewImportService(publicTripRepo, tripRepo)
+	tripService := service.NewTripService(tripRepo, publicTripRepo, checklistRepo, tripAttachmentRepo, blobStore, eventHub)
+	currencyService := service.NewCurrencyService(exchangeRateRepo)
+	publicTripService := service.NewPublicTripService(publicTripRepo, tripRepo, tripLikeRepo, currencyService)
+	activityService := service.NewActivityService(activityRepo, tripRepo, dayPlanRepo, eventHub)
+	importService := service.NewImportService(publicTripRepo, tripRepo, importRepo, activityRepo, destinationRepo, eventHub)
 	tripLikeService := service.NewTripLikeService(tripLikeRepo)
+	dayPlanService := service.NewDayPlanService(tripRepo, dayPlanRepo, eventHub)
+	tripRevisionService := service.NewTripRevisionService(tripRepo, tripRevisionRepo, eventHub)
+	calendarService := service.NewCalendarService(tripRepo, calendarTokenRepo)
+	exportService := service.NewExportService(tripRepo)
+	archiveService := service.NewArchiveService(tripRepo, activityRepo, destinationRepo, importRepo)
+	tripMemberService := service.NewTripMemberService(tripRepo, tripMemberRepo, tripInvitationRepo, userRepo, eventHub)
+	tripEventService := service.NewTripEventService(tripRepo, eventHub)
+	budgetService := service.NewBudgetService(tripRepo, tripExpenseRepo, currencyService, eventHub)
+	checklistService := service.NewChecklistService(tripRepo, checklistRepo, eventHub)
+	tripLegService := service.NewTripLegService(tripRepo, tripLegRepo, eventHub)
+	tripReservationService := service.NewTripReservationService(tripRepo, tripReservationRepo, eventHub)
+	tripAttachmentService := service.NewTripAttachmentService(tripRepo, tripAttachmentRepo, blobStore, downloadSigningSecret(cfg), cfg.Storage.MaxUploadBytes, eventHub)
+	imageService := service.NewImageService(tripRepo, destinationRepo, blobStore, newImageEncoders(cfg.Storage), cfg.Server.PublicURL, cfg.Storage.MaxUploadBytes, eventHub)
+	itineraryValidator := service.NewItineraryValidator(tripRepo)
+	routeService := service.NewRouteService(tripRepo, service.NewHaversineMatrix(), newRoutingProvider(cfg.Maps))
 
 	// Initialize OAuth config
 	oauthConfig := setupOAuth(cfg)
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService, tripService, oauthConfig, cfg.JWT.Secret, cfg.Server.FrontendOrigin)
-	tripHandler := handlers.NewTripHandler(tripService)
+	tripHandler := handlers.NewTripHandler(tripService, itineraryValidator)
 	publicTripHandler := handlers.NewPublicTripHandler(publicTripService)
 	activityHandler := handlers.NewActivityHandler(activityService)
 	importHandler := handlers.NewImportHandler(importService)
 	tripLikeHandler := handlers.NewTripLikeHandler(tripLikeService)
+	dayPlanHandler := handlers.NewDayPlanHandler(dayPlanService, routeService)
+	tripRevisionHandler := handlers.NewTripRevisionHandler(tripRevisionService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
+	exportHandler := handlers.NewExportHandler(exportService)
+	archiveHandler := handlers.NewArchiveHandler(archiveService)
+	tripMemberHandler := handlers.NewTripMemberHandler(tripMemberService)
+	tripEventHandler := handlers.NewTripEventHandler(tripEventService)
+	budgetHandler := handlers.NewBudgetHandler(budgetService)
+	checklistHandler := handlers.NewChecklistHandler(checklistService)
+	tripLegHandler := handlers.NewTripLegHandler(tripLegService)
+	tripReservationHandler := handlers.NewTripReservationHandler(tripReservationService)
+	tripAttachmentHandler := handlers.NewTripAttachmentHandler(tripAttachmentService)
+	imageHandler := handlers.NewImageHandler(imageService)
+	routeHandler := handlers.NewRouteHandler(routeService)
+	itineraryHandler := handlers.NewItineraryHandler(itineraryValidator)
+	currencyHandler := handlers.NewCurrencyHandler(currencyService)
 	mapsHandler := handlers.NewMapsHandler(cfg.Maps.APIKey)
 
 	// Initialize middleware
@@ -81,6 +140,7 @@ func main() {
 	app := fiber.New(fiber.Config{
 		DisableStartupMessage: false,
 		ErrorHandler:          middleware.ErrorHandler,
+		BodyLimit:             int(cfg.Storage.MaxUploadBytes) + 1<<20, // room for the multipart envelope
 	})
 
 	// Global middleware
@@ -133,12 +193,119 @@ func main() {
 	apiRoutes.Put("/users/:userId/trips/:tripId", authMiddleware.OptionalAuth, tripHandler.UpdateTrip)
 	apiRoutes.Delete("/users/:userId/trips/:tripId", authMiddleware.OptionalAuth, tripHandler.DeleteTrip)
 
+	// Day plan routes (granular itinerary edits, owner, editors or shadow owner)
+	apiRoutes.Post("/trips/:tripId/days", authMiddleware.OptionalAuth, dayPlanHandler.CreateDayPlan)
+	apiRoutes.Patch("/trips/:tripId/days/:dayId", authMiddleware.OptionalAuth, dayPlanHandler.UpdateDayPlan)
+	apiRoutes.Delete("/trips/:tripId/days/:dayId", authMiddleware.OptionalAuth, dayPlanHandler.DeleteDayPlan)
+	apiRoutes.Post("/trips/:tripId/days/:dayId/activities", authMiddleware.OptionalAuth, dayPlanHandler.AddActivity)
+	apiRoutes.Patch("/trips/:tripId/days/:dayId/activities/:activityId", authMiddleware.OptionalAuth, dayPlanHandler.UpdateActivity)
+	apiRoutes.Post("/trips/:tripId/days/:dayId/activities/:activityId/move", authMiddleware.OptionalAuth, dayPlanHandler.MoveActivity)
+	apiRoutes.Delete("/trips/:tripId/days/:dayId/activities/:activityId", authMiddleware.OptionalAuth, dayPlanHandler.RemoveActivity)
+	apiRoutes.Post("/trips/:tripId/days/:dayId/destinations", authMiddleware.OptionalAuth, dayPlanHandler.AddDestination)
+	apiRoutes.Patch("/trips/:tripId/days/:dayId/destinations/:destinationId", authMiddleware.OptionalAuth, dayPlanHandler.UpdateDestination)
+	apiRoutes.Delete("/trips/:tripId/days/:dayId/destinations/:destinationId", authMiddleware.OptionalAuth, dayPlanHandler.RemoveDestination)
+
+	// Itinerary checks (warnings only, for any member)
+	apiRoutes.Get("/trips/:tripId/validation", authMiddleware.OptionalAuth, itineraryHandler.ValidateTrip)
+
+	// Route planning (previews for any member; apply through /api/activities/order)
+	apiRoutes.Get("/trips/:tripId/days/:dayId/route/optimize", authMiddleware.OptionalAuth, routeHandler.OptimizeDay)
+	apiRoutes.Get("/trips/:tripId/days/:dayId/travel", authMiddleware.OptionalAuth, routeHandler.GetDayTravel)
+
+	// Trip revision routes (history and diff for any member, restore for owner and editors)
+	apiRoutes.Get("/trips/:tripId/revisions", authMiddleware.OptionalAuth, tripRevisionHandler.ListRevisions)
+	apiRoutes.Get("/trips/:tripId/revisions/diff", authMiddleware.OptionalAuth, tripRevisionHandler.DiffRevisions)
+	apiRoutes.Get("/trips/:tripId/revisions/:revisionId", authMiddleware.OptionalAuth, tripRevisionHandler.GetRevision)
+	apiRoutes.Post("/trips/:tripId/revisions/:revisionId/restore", authMiddleware.OptionalAuth, tripRevisionHandler.RestoreRevision)
+
+	// Budget routes (expense ledger for editors, rollups for any member)
+	apiRoutes.Get("/trips/:tripId/expenses", authMiddleware.OptionalAuth, budgetHandler.ListExpenses)
+	apiRoutes.Post("/trips/:tripId/expenses", authMiddleware.OptionalAuth, budgetHandler.CreateExpense)
+	apiRoutes.Patch("/trips/:tripId/expenses/:expenseId", authMiddleware.OptionalAuth, budgetHandler.UpdateExpense)
+	apiRoutes.Delete("/trips/:tripId/expenses/:expenseId", authMiddleware.OptionalAuth, budgetHandler.DeleteExpense)
+	apiRoutes.Get("/trips/:tripId/budget", authMiddleware.OptionalAuth, budgetHandler.GetSummary)
+
+	// Checklist routes (packing lists and to-dos; editing for owner and editors)
+	apiRoutes.Get("/trips/:tripId/checklists", authMiddleware.OptionalAuth, checklistHandler.ListChecklists)
+	apiRoutes.Get("/trips/:tripId/checklists/template", authMiddleware.OptionalAuth, checklistHandler.GetTemplate)
+	apiRoutes.Post("/trips/:tripId/checklists", authMiddleware.OptionalAuth, checklistHandler.CreateChecklist)
+	apiRoutes.Patch("/trips/:tripId/checklists/:checklistId", authMiddleware.OptionalAuth, checklistHandler.UpdateChecklist)
+	apiRoutes.Delete("/trips/:tripId/checklists/:checklistId", authMiddleware.OptionalAuth, checklistHandler.DeleteChecklist)
+	apiRoutes.Post("/trips/:tripId/checklists/:checklistId/items", authMiddleware.OptionalAuth, checklistHandler.AddItem)
+	apiRoutes.Patch("/trips/:tripId/checklists/:checklistId/items/:itemId", authMiddleware.OptionalAuth, checklistHandler.UpdateItem)
+	apiRoutes.Delete("/trips/:tripId/checklists/:checklistId/items/:itemId", authMiddleware.OptionalAuth, checklistHandler.DeleteItem)
+
+	// Trip leg routes (transport between consecutive destinations; editing for owner and editors)
+	apiRoutes.Get("/trips/:tripId/legs", authMiddleware.OptionalAuth, tripLegHandler.ListLegs)
+	apiRoutes.Post("/trips/:tripId/legs", authMiddleware.OptionalAuth, tripLegHandler.CreateLeg)
+	apiRoutes.Patch("/trips/:tripId/legs/:legId", authMiddleware.OptionalAuth, tripLegHandler.UpdateLeg)
+	apiRoutes.Delete("/trips/:tripId/legs/:legId", authMiddleware.OptionalAuth, tripLegHandler.DeleteLeg)
+
+	// Reservation routes (bookings of activities and legs, never public; editing for owner and editors)
+	apiRoutes.Get("/trips/:tripId/reservations", authMiddleware.OptionalAuth, tripReservationHandler.ListReservations)
+	apiRoutes.Post("/trips/:tripId/reservations", authMiddleware.OptionalAuth, tripReservationHandler.CreateReservation)
+	apiRoutes.Patch("/trips/:tripId/reservations/:reservationId", authMiddleware.OptionalAuth, tripReservationHandler.UpdateReservation)
+	apiRoutes.Delete("/trips/:tripId/reservations/:reservationId", authMiddleware.OptionalAuth, tripReservationHandler.DeleteReservation)
+
+	// Attachment routes (files of a trip and its activities; downloads through signed, expiring links)
+	apiRoutes.Get("/trips/:tripId/attachments", authMiddleware.OptionalAuth, tripAttachmentHandler.ListAttachments)
+	apiRoutes.Post("/trips/:tripId/attachments", authMiddleware.OptionalAuth, tripAttachmentHandler.UploadAttachment)
+	apiRoutes.Get("/trips/:tripId/attachments/:attachmentId", authMiddleware.OptionalAuth, tripAttachmentHandler.GetAttachment)
+	apiRoutes.Delete("/trips/:tripId/attachments/:attachmentId", authMiddleware.OptionalAuth, tripAttachmentHandler.DeleteAttachment)
+	apiRoutes.Get("/trips/:tripId/attachments/:attachmentId/download", tripAttachmentHandler.Download)
+
+	// Image routes (resized cover and hero images; served publicly under immutable URLs)
+	apiRoutes.Post("/trips/:tripId/cover-image", authMiddleware.OptionalAuth, imageHandler.UploadTripCover)
+	apiRoutes.Post("/admin/destinations/:destinationId/hero-image", middleware.RequireAdminToken(cfg.Admin.APIToken), imageHandler.UploadDestinationHero)
+	apiRoutes.Get("/images/:imageId/:file", imageHandler.GetImage)
+
+	// Exchange rate routes (public lookup, admin-token protected loading)
+	apiRoutes.Get("/exchange-rates", currencyHandler.ListRates)
+	apiRoutes.Post("/admin/exchange-rates", middleware.RequireAdminToken(cfg.Admin.APIToken), currencyHandler.LoadRates)
+
+	// Live trip changes (Server-Sent Events)
+	apiRoutes.Get("/trips/:tripId/events", authMiddleware.OptionalAuth, tripEventHandler.Stream)
+
+	// Calendar routes (.ics export, plus a token-protected feed URL for calendar subscriptions)
+	apiRoutes.Get("/trips/:tripId/calendar.ics", authMiddleware.OptionalAuth, calendarHandler.ExportTrip)
+	apiRoutes.Get("/trips/:tripId/calendar/feed", authMiddleware.OptionalAuth, calendarHandler.GetFeed)
+	apiRoutes.Post("/trips/:tripId/calendar/feed", authMiddleware.OptionalAuth, calendarHandler.CreateFeed)
+	apiRoutes.Delete("/trips/:tripId/calendar/feed", authMiddleware.OptionalAuth, calendarHandler.RevokeFeed)
+	apiRoutes.Get("/calendar/:token.ics", calendarHandler.ExportFeed)
+
+	// Route export (GPX/KML for offline navigation apps)
+	apiRoutes.Get("/trips/:tripId/route.gpx", authMiddleware.OptionalAuth, exportHandler.ExportGPX)
+	apiRoutes.Get("/trips/:tripId/route.kml", authMiddleware.OptionalAuth, exportHandler.ExportKML)
+
+	// Printable itinerary export
+	apiRoutes.Get("/trips/:tripId/itinerary.html", authMiddleware.OptionalAuth, exportHandler.ExportHTML)
+	apiRoutes.Get("/trips/:tripId/itinerary.md", authMiddleware.OptionalAuth, exportHandler.ExportMarkdown)
+
+	// Archive routes (portable JSON backup of one or all trips, and re-import with fresh IDs)
+	apiRoutes.Get("/trips/:tripId/archive", authMiddleware.OptionalAuth, archiveHandler.ExportTrip)
+	apiRoutes.Get("/archive", authMiddleware.OptionalAuth, archiveHandler.ExportAll)
+	apiRoutes.Post("/archive", authMiddleware.OptionalAuth, archiveHandler.ImportArchive)
+
+	// Collaboration routes (members with owner/editor/viewer roles, invitations by email or link)
+	apiRoutes.Get("/trips/:tripId/members", authMiddleware.RequireAuth, tripMemberHandler.ListMembers)
+	apiRoutes.Patch("/trips/:tripId/members/:userId", authMiddleware.RequireAuth, tripMemberHandler.UpdateMember)
+	apiRoutes.Delete("/trips/:tripId/members/:userId", authMiddleware.RequireAuth, tripMemberHandler.RemoveMember)
+	apiRoutes.Get("/trips/:tripId/invitations", authMiddleware.RequireAuth, tripMemberHandler.ListInvitations)
+	apiRoutes.Post("/trips/:tripId/invitations", authMiddleware.RequireAuth, tripMemberHandler.CreateInvitation)
+	apiRoutes.Delete("/trips/:tripId/invitations/:invitationId", authMiddleware.RequireAuth, tripMemberHandler.RevokeInvitation)
+	apiRoutes.Get("/invitations", authMiddleware.RequireAuth, tripMemberHandler.ListMyInvitations)
+	apiRoutes.Get("/invitations/:token", tripMemberHandler.GetInvitation)
+	apiRoutes.Post("/invitations/:token/accept", authMiddleware.RequireAuth, tripMemberHandler.AcceptInvitation)
+	apiRoutes.Post("/invitations/:token/decline", authMiddleware.RequireAuth, tripMemberHandler.DeclineInvitation)
+
 	// Activity routes
-	apiRoutes.Post("/activities/order", activityHandler.UpdateActivityOrder)
+	apiRoutes.Post("/activities/order", authMiddleware.OptionalAuth, activityHandler.UpdateActivityOrder)
 
 	// Public trips routes
 	apiRoutes.Get("/public-trips", authMiddleware.OptionalAuth, publicTripHandler.ListPublicTrips)
 	apiRoutes.Get("/public-trips/:tripId", authMiddleware.OptionalAuth, publicTripHandler.GetPublicTripDetail)
+	apiRoutes.Get("/public-trips/:tripId/itinerary.html", publicTripHandler.ExportHTML)
+	apiRoutes.Get("/public-trips/:tripId/itinerary.md", publicTripHandler.ExportMarkdown)
 	apiRoutes.Post("/public-trips/:tripId/visibility", authMiddleware.OptionalAuth, publicTripHandler.ToggleVisibility)
 	apiRoutes.Post("/public-trips/:tripId/like", authMiddleware.RequireAuth, tripLikeHandler.ToggleLike)
 
@@ -147,6 +314,7 @@ func main() {
 
 	// Import routes (protected)
 	apiRoutes.Post("/import-trip", authMiddleware.OptionalAuth, importHandler.ImportTripParts)
+	apiRoutes.Post("/import-calendar", authMiddleware.OptionalAuth, importHandler.ImportCalendar)
 
 	// Maps API routes (public - protected by HTTP referrer restrictions in Google Cloud Console)
 	apiRoutes.Get("/maps/config", mapsHandler.GetMapConfig)
@@ -164,50 +332,16 @@ func openDB(cfg *config.Config) (*gorm.DB, error) {
 	return gorm.Open(postgres.Open(cfg.Database.URL), &gorm.Config{})
 }
 
-func autoMigrate(db *gorm.DB) error {
-	// Drop all tables if they exist (both old and new names)
-	log.Println("🗑️  Dropping old tables...")
-	tablesToDrop := []string{
-		"activity_imports", "day_plan_activities", "day_plan_destinations",
-		"day_plans", "trip_destinations", "activities", "destinations",
-		"trips", "users", "public_trips", "daily_plans", "trip_likes",
-	}
-	for _, table := range tablesToDrop {
-		if db.Migrator().HasTable(table) {
-			if err := db.Migrator().DropTable(table); err != nil {
-				log.Printf("Warning: failed to drop table %s: %v", table, err)
-			}
-		}
+// runCommand dispatches a CLI subcommand
+func runCommand(db *gorm.DB, name string, args []string) error {
+	switch name {
+	case "migrate":
+		return runMigrate(db, args)
+	case "seed":
+		return runSeed(db, args)
+	default:
+		return fmt.Errorf("unknown command %q (available: migrate, seed)", name)
 	}
-
-	log.Println("📦 Migrating new schema...")
-	// Migrate in order to respect foreign key dependencies
-	err := db.AutoMigrate(
-		&models.User{},
-		&models.Trip{},
-		&models.Destination{},
-		&models.Activity{},
-		&models.DayPlan{},
-		&models.TripDestination{},
-		&models.DayPlanDestination{},
-		&models.DayPlanActivity{},
-		&models.ActivityImport{},
-		&models.TripLike{},
-	)
-	if err != nil {
-		return err
-	}
-
-	// Add unique constraint for user-trip likes
-	err = db.Exec(`
-		CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_user_trip_like 
-		ON trip_likes (user_id, trip_id)
-	`).Error
-	if err != nil {
-		log.Printf("Warning: Failed to create unique index: %v", err)
-	}
-
-	return nil
 }
 
 func setupOAuth(cfg *config.Config) *oauth2.Config {
@@ -228,691 +362,40 @@ func setupOAuth(cfg *config.Config) *oauth2.Config {
 	}
 }
 
-func seedDemoData(db *gorm.DB) error {
-	// Create demo user
-	now := time.Now().UTC()
-	demoUser := models.User{
-		ID:        "user-sarah",
-		Name:      "Sarah Levi",
-		Email:     "sarah.levi@example.com",
-		Locale:    "en",
-		CreatedAt: now,
-		UpdatedAt: now,
+// downloadSigningSecret is the key for attachment download links, kept apart from the session key
+func downloadSigningSecret(cfg *config.Config) string {
+	if cfg.Storage.SigningSecret != "" {
+		return cfg.Storage.SigningSecret
 	}
-
-	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&demoUser).Error; err != nil {
-		return err
-	}
-
-	// Check if user already has trips
-	var count int64
-	if err := db.Model(&models.Trip{}).Where("user_id = ?", demoUser.ID).Count(&count).Error; err != nil {
-		return err
-	}
-
-	if count > 0 {
-		return nil // Already seeded
-	}
-
-	// Create destinations
-	tokyoDest := models.Destination{
-		ID:              "dest-tokyo",
-		City:            "Tokyo",
-		Region:          ptr("Kanto"),
-		Country:         "Japan",
-		Latitude:        ptr(35.6762),
-		Longitude:       ptr(139.6503),
-		Timezone:        ptr("Asia/Tokyo"),
-		HeroImage:       ptr("https://images.unsplash.com/photo-1540959733332-eab4deabeeaf?auto=format&fit=crop&w=1400&q=80"),
-		TripCount:       0,
-		PopularityScore: 0,
-		CreatedAt:       now,
-		UpdatedAt:       now,
-	}
-	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&tokyoDest).Error; err != nil {
-		return err
-	}
-
-	// Create activities
-	activities := []models.Activity{
-		{
-			ID:              "act-narita-express",
-			Title:           "Narita Express to Tokyo Station",
-			Type:            "transportation",
-			Location:        ptr("Narita Airport"),
-			PlaceID:         ptr("ChIJN5X73rWMImARPA6C8I-g2NA"),
-			DurationMinutes: ptr(90),
-			UsageCount:      0,
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:         "act-hotel-checkin",
-			Title:      "Check-in at Nihonbashi boutique hotel",
-			Type:       "accommodation",
-			Location:   ptr("Chiyoda"),
-			Latitude:   ptr(35.6995),
-			Longitude:  ptr(139.7537),
-			UsageCount: 0,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		},
-		{
-			ID:         "act-tsukiji-lunch",
-			Title:      "Lunch at Tsukiji Outer Market",
-			Type:       "meal",
-			Location:   ptr("Tsukiji"),
-			Latitude:   ptr(35.6655),
-			Longitude:  ptr(139.7708),
-			UsageCount: 0,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		},
-		{
-			ID:         "act-asakusa-stroll",
-			Title:      "Evening stroll in Asakusa and Senso-ji",
-			Type:       "culture",
-			Location:   ptr("Asakusa"),
-			Latitude:   ptr(35.7148),
-			Longitude:  ptr(139.7967),
-			UsageCount: 0,
-			CreatedAt:  now,
-			UpdatedAt:  now,
-		},
-	}
-
-	for _, act := range activities {
-		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&act).Error; err != nil {
-			return err
-		}
-	}
-
-	// Create demo trip
-	cover := "https://images.unsplash.com/photo-1549692520-acc6669e2f0c?auto=format&fit=crop&w=1400&q=80"
-
-	trip := models.Trip{
-		ID:            "trip-001",
-		UserID:        demoUser.ID,
-		Name:          "הטיול שלי ליפן",
-		TravelerCount: 2,
-		StartDate:     "2025-03-28",
-		EndDate:       "2025-04-10",
-		CoverImage:    cover,
-		Visibility:    "private",
-		Status:        "active",
-		CreatedAt:     now,
-		UpdatedAt:     now,
-	}
-
-	if err := db.Create(&trip).Error; err != nil {
-		return err
-	}
-
-	// Link trip to destination
-	tripDest := models.TripDestination{
-		ID:            "td-1",
-		TripID:        trip.ID,
-		DestinationID: tokyoDest.ID,
-		OrderIndex:    0,
-		StartDate:     ptr("2025-03-28"),
-		EndDate:       ptr("2025-04-02"),
-		CreatedAt:     now,
-	}
-	if err := db.Create(&tripDest).Error; err != nil {
-		return err
-	}
-
-	// Create day plan
-	dayPlan := models.DayPlan{
-		ID:        "day-tokyo-1",
-		TripID:    trip.ID,
-		Date:      "2025-03-28",
-		DayNumber: 1,
-		Notes:     ptr("Arrival day with light activities to adjust to the time zone."),
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
-	if err := db.Create(&dayPlan).Error; err != nil {
-		return err
-	}
-
-	// Link destination to day plan
-	dayPlanDest := models.DayPlanDestination{
-		ID:            "dpd-1",
-		DayPlanID:     dayPlan.ID,
-		DestinationID: tokyoDest.ID,
-		OrderIndex:    0,
-		PartOfDay:     ptr("all-day"),
-		CreatedAt:     now,
-	}
-	if err := db.Create(&dayPlanDest).Error; err != nil {
-		return err
-	}
-
-	// Link activities to day plan
-	dayPlanActivities := []models.DayPlanActivity{
-		{
-			ID:              "dpa-1",
-			DayPlanID:       dayPlan.ID,
-			ActivityID:      "act-narita-express",
-			TimeOfDay:       "start",
-			OrderWithinTime: 0,
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "dpa-2",
-			DayPlanID:       dayPlan.ID,
-			ActivityID:      "act-hotel-checkin",
-			TimeOfDay:       "start",
-			OrderWithinTime: 1,
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "dpa-3",
-			DayPlanID:       dayPlan.ID,
-			ActivityID:      "act-tsukiji-lunch",
-			TimeOfDay:       "mid",
-			OrderWithinTime: 0,
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "dpa-4",
-			DayPlanID:       dayPlan.ID,
-			ActivityID:      "act-asakusa-stroll",
-			TimeOfDay:       "end",
-			OrderWithinTime: 0,
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-	}
-
-	for _, dpa := range dayPlanActivities {
-		if err := db.Create(&dpa).Error; err != nil {
-			return err
-		}
-	}
-
-	log.Println("✅ Seeded demo data successfully")
-	return nil
+	return utils.DeriveKey(cfg.JWT.Secret, "attachment-downloads")
 }
 
-func seedPublicTrips(db *gorm.DB) error {
-	// Check if we already have public trips
-	var count int64
-	if err := db.Model(&models.Trip{}).Where("visibility = ?", "public").Count(&count).Error; err != nil {
-		return err
+// newBlobStore opens the store for uploaded files
+func newBlobStore(cfg config.StorageConfig) (storage.BlobStore, error) {
+	if cfg.Backend != "local" {
+		log.Printf("STORAGE_BACKEND=%s is not supported; using the local filesystem", cfg.Backend)
 	}
-
-	if count > 0 {
-		return nil // Already seeded
-	}
-
-	// Get or create a demo author user for public trips
-	now := time.Now().UTC()
-	authorUser := models.User{
-		ID:        "user-public-author",
-		Name:      "אוצר טיולים",
-		Email:     "curator@triply.com",
-		Locale:    "en",
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
-	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&authorUser).Error; err != nil {
-		return err
-	}
-
-	// Get destinations (or create if they don't exist)
-	destinations := map[string]models.Destination{
-		"Tokyo": {
-			ID:        "dest-tokyo-public",
-			City:      "Tokyo",
-			Region:    ptr("Kanto"),
-			Country:   "Japan",
-			Latitude:  ptr(35.6762),
-			Longitude: ptr(139.6503),
-			Timezone:  ptr("Asia/Tokyo"),
-			HeroImage: ptr("https://images.unsplash.com/photo-1540959733332-eab4deabeeaf?auto=format&fit=crop&w=1400&q=80"),
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-		"Kyoto": {
-			ID:        "dest-kyoto-public",
-			City:      "Kyoto",
-			Region:    ptr("Kansai"),
-			Country:   "Japan",
-			Latitude:  ptr(35.0116),
-			Longitude: ptr(135.7681),
-			Timezone:  ptr("Asia/Tokyo"),
-			HeroImage: ptr("https://images.unsplash.com/photo-1493976040374-85c8e12f0c0e?auto=format&fit=crop&w=1400&q=80"),
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-		"Osaka": {
-			ID:        "dest-osaka-public",
-			City:      "Osaka",
-			Region:    ptr("Kansai"),
-			Country:   "Japan",
-			Latitude:  ptr(34.6937),
-			Longitude: ptr(135.5023),
-			Timezone:  ptr("Asia/Tokyo"),
-			HeroImage: ptr("https://images.unsplash.com/photo-1528360983277-13d401cdc186?auto=format&fit=crop&w=1400&q=80"),
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-		"Nagoya": {
-			ID:        "dest-nagoya-public",
-			City:      "Nagoya",
-			Region:    ptr("Chubu"),
-			Country:   "Japan",
-			Latitude:  ptr(35.1815),
-			Longitude: ptr(136.9066),
-			Timezone:  ptr("Asia/Tokyo"),
-			HeroImage: ptr("https://images.unsplash.com/photo-1624993590528-4ee743c9896e?auto=format&fit=crop&w=1400&q=80"),
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-		"Hiroshima": {
-			ID:        "dest-hiroshima-public",
-			City:      "Hiroshima",
-			Region:    ptr("Chugoku"),
-			Country:   "Japan",
-			Latitude:  ptr(34.3853),
-			Longitude: ptr(132.4553),
-			Timezone:  ptr("Asia/Tokyo"),
-			HeroImage: ptr("https://images.unsplash.com/photo-1590559899731-a382839e5549?auto=format&fit=crop&w=1400&q=80"),
-			CreatedAt: now,
-			UpdatedAt: now,
-		},
-	}
-
-	for _, dest := range destinations {
-		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&dest).Error; err != nil {
-			return err
-		}
-	}
-
-	// Create public trips
-	publicTrips := []struct {
-		trip         models.Trip
-		destinations []string
-	}{
-		{
-			trip: models.Trip{
-				ID:            "pt-tokyo-week-discovery",
-				UserID:        authorUser.ID,
-				Name:          "גילוי טוקיו בשבוע",
-				Slug:          ptr("tokyo-week-discovery"),
-				TravelerCount: 2,
-				StartDate:     "2025-05-15",
-				EndDate:       "2025-05-21",
-				CoverImage:    "https://images.unsplash.com/photo-1540959733332-eab4deabeeaf?auto=format&fit=crop&w=1200&q=80",
-				Visibility:    "public",
-				Status:        "completed",
-				Summary:       ptr("חוו את השילוב המושלם של טוקיו בין מסורת עתיקה למודרניות חדשנית בשבוע מרגש."),
-				TravelerType:  string(models.TravelerTypeCouple),
-				Likes:         312,
-				CreatedAt:     now.AddDate(0, -1, -25),
-				UpdatedAt:     now.AddDate(0, 0, -20),
-			},
-			destinations: []string{"Tokyo"},
-		},
-		{
-			trip: models.Trip{
-				ID:            "pt-tokyo-kyoto-10days",
-				UserID:        authorUser.ID,
-				Name:          "מסע תרבותי בטוקיו וקיוטו",
-				Slug:          ptr("tokyo-kyoto-10-day-cultural-journey"),
-				TravelerCount: 2,
-				StartDate:     "2025-10-10",
-				EndDate:       "2025-10-19",
-				CoverImage:    "https://images.unsplash.com/photo-1493976040374-85c8e12f0c0e?auto=format&fit=crop&w=1200&q=80",
-				Visibility:    "public",
-				Status:        "completed",
-				Summary:       ptr("מסע מושלם של 10 ימים המשלב את האנרגיה המודרנית של טוקיו עם היופי והמסורת הנצחית של קיוטו."),
-				TravelerType:  string(models.TravelerTypeFriends),
-				Likes:         428,
-				CreatedAt:     now.AddDate(0, -1, -13),
-				UpdatedAt:     now.AddDate(0, 0, -17),
-			},
-			destinations: []string{"Tokyo", "Kyoto"},
-		},
-		{
-			trip: models.Trip{
-				ID:            "pt-kansai-grand-tour-14days",
-				UserID:        authorUser.ID,
-				Name:          "סיור קנסאי הגדול האולטימטיבי",
-				Slug:          ptr("ultimate-kansai-tokyo-kyoto-osaka-14-days"),
-				TravelerCount: 2,
-				StartDate:     "2025-03-20",
-				EndDate:       "2025-04-02",
-				CoverImage:    "https://images.unsplash.com/photo-1528360983277-13d401cdc186?auto=format&fit=crop&w=1200&q=80",
-				Visibility:    "public",
-				Status:        "completed",
-				Summary:       ptr("הרפתקת יפן האולטימטיבית של שבועיים המשלבת את האנרגיה של טוקיו, התרבות של קיוטו והקולינריה של אוסקה."),
-				TravelerType:  string(models.TravelerTypeFamily),
-				Likes:         567,
-				CreatedAt:     now.AddDate(0, 0, -34),
-				UpdatedAt:     now.AddDate(0, 0, -15),
-			},
-			destinations: []string{"Tokyo", "Kyoto", "Osaka"},
-		},
-		{
-			trip: models.Trip{
-				ID:            "pt-japan-grand-adventure-28days",
-				UserID:        authorUser.ID,
-				Name:          "הרפתקה גדולה ביפן - חודש מלא",
-				Slug:          ptr("japan-grand-adventure-28-days-tokyo-kyoto-osaka-nagoya-hiroshima"),
-				TravelerCount: 1,
-				StartDate:     "2025-04-05",
-				EndDate:       "2025-05-02",
-				CoverImage:    "https://images.unsplash.com/photo-1590559899731-a382839e5549?auto=format&fit=crop&w=1200&q=80",
-				Visibility:    "public",
-				Status:        "completed",
-				Summary:       ptr("מסע אפי של 28 ימים לחקור את יפן מטוקיו להירושימה, לחוות את המגוון המלא של התרבות, המטבח והנופים היפניים."),
-				TravelerType:  string(models.TravelerTypeSolo),
-				Likes:         892,
-				CreatedAt:     now.AddDate(0, 0, -45),
-				UpdatedAt:     now.AddDate(0, 0, -3),
-			},
-			destinations: []string{"Tokyo", "Kyoto", "Osaka", "Nagoya", "Hiroshima"},
-		},
-	}
-
-	for _, pt := range publicTrips {
-		if err := db.Create(&pt.trip).Error; err != nil {
-			return err
-		}
-
-		// Link destinations to trip
-		for idx, destName := range pt.destinations {
-			dest := destinations[destName]
-			tripDest := models.TripDestination{
-				ID:            "td-" + pt.trip.ID + "-" + dest.ID,
-				TripID:        pt.trip.ID,
-				DestinationID: dest.ID,
-				OrderIndex:    idx,
-				CreatedAt:     now,
-			}
-			if err := db.Create(&tripDest).Error; err != nil {
-				return err
-			}
-		}
-
-		// Create sample day plans and activities for each trip
-		if err := seedTripItinerary(db, &pt.trip, destinations, now); err != nil {
-			return err
-		}
-	}
-
-	log.Printf("✅ Seeded %d public trips with itineraries", len(publicTrips))
-	return nil
+	return storage.NewLocalStore(cfg.LocalDir)
 }
 
-func seedTripItinerary(db *gorm.DB, trip *models.Trip, destinations map[string]models.Destination, now time.Time) error {
-	// Create sample activities (these will be reused across trips)
-	sampleActivities := []models.Activity{
-		{
-			ID:              "act-senso-ji",
-			Title:           "Visit Senso-ji Temple",
-			Type:            "culture",
-			Location:        ptr("Asakusa"),
-			Latitude:        ptr(35.7148),
-			Longitude:       ptr(139.7967),
-			Description:     ptr("Tokyo's oldest and most famous temple"),
-			DurationMinutes: ptr(90),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-tsukiji-market",
-			Title:           "Tsukiji Outer Market Food Tour",
-			Type:            "meal",
-			Location:        ptr("Tsukiji"),
-			Latitude:        ptr(35.6655),
-			Longitude:       ptr(139.7708),
-			Description:     ptr("Fresh sushi and street food experience"),
-			DurationMinutes: ptr(120),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-shibuya-crossing",
-			Title:           "Shibuya Crossing Experience",
-			Type:            "experience",
-			Location:        ptr("Shibuya"),
-			Latitude:        ptr(35.6595),
-			Longitude:       ptr(139.7004),
-			Description:     ptr("World's busiest pedestrian crossing"),
-			DurationMinutes: ptr(60),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-fushimi-inari",
-			Title:           "Fushimi Inari Shrine",
-			Type:            "culture",
-			Location:        ptr("Fushimi"),
-			Latitude:        ptr(34.9671),
-			Longitude:       ptr(135.7727),
-			Description:     ptr("Famous shrine with thousands of red torii gates"),
-			DurationMinutes: ptr(120),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-arashiyama-bamboo",
-			Title:           "Arashiyama Bamboo Grove",
-			Type:            "culture",
-			Location:        ptr("Arashiyama"),
-			Latitude:        ptr(35.0094),
-			Longitude:       ptr(135.6686),
-			Description:     ptr("Stunning bamboo forest path"),
-			DurationMinutes: ptr(90),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-osaka-castle",
-			Title:           "Osaka Castle Visit",
-			Type:            "culture",
-			Location:        ptr("Chuo Ward"),
-			Latitude:        ptr(34.6873),
-			Longitude:       ptr(135.5262),
-			Description:     ptr("Historic castle with panoramic city views"),
-			DurationMinutes: ptr(120),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-dotonbori",
-			Title:           "Dotonbori Food Street",
-			Type:            "meal",
-			Location:        ptr("Namba"),
-			Latitude:        ptr(34.6686),
-			Longitude:       ptr(135.5006),
-			Description:     ptr("Osaka's famous entertainment and food district"),
-			DurationMinutes: ptr(150),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-nagoya-castle",
-			Title:           "Nagoya Castle",
-			Type:            "culture",
-			Location:        ptr("Nagoya"),
-			Latitude:        ptr(35.1856),
-			Longitude:       ptr(136.8998),
-			Description:     ptr("Historic castle with golden shachihoko"),
-			DurationMinutes: ptr(120),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-atsuta-shrine",
-			Title:           "Atsuta Shrine",
-			Type:            "culture",
-			Location:        ptr("Nagoya"),
-			Latitude:        ptr(35.1280),
-			Longitude:       ptr(136.9083),
-			Description:     ptr("One of Japan's most important Shinto shrines"),
-			DurationMinutes: ptr(90),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-peace-memorial",
-			Title:           "Hiroshima Peace Memorial Park",
-			Type:            "culture",
-			Location:        ptr("Hiroshima"),
-			Latitude:        ptr(34.3955),
-			Longitude:       ptr(132.4536),
-			Description:     ptr("Memorial dedicated to peace and the atomic bombing victims"),
-			DurationMinutes: ptr(120),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-		{
-			ID:              "act-miyajima",
-			Title:           "Miyajima Island & Itsukushima Shrine",
-			Type:            "culture",
-			Location:        ptr("Miyajima"),
-			Latitude:        ptr(34.2959),
-			Longitude:       ptr(132.3197),
-			Description:     ptr("Famous floating torii gate and sacred island"),
-			DurationMinutes: ptr(240),
-			CreatedAt:       now,
-			UpdatedAt:       now,
-		},
-	}
-
-	// Create activities if they don't exist
-	for _, act := range sampleActivities {
-		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&act).Error; err != nil {
-			return err
-		}
+// newImageEncoders picks the formats uploaded images are stored in: JPEG always, WebP when cwebp is installed
+func newImageEncoders(cfg config.StorageConfig) []service.ImageEncoder {
+	encoders := []service.ImageEncoder{service.NewJPEGEncoder(82)}
+	path, err := exec.LookPath(cfg.CWebPPath)
+	if err != nil {
+		log.Printf("%s not found; images are stored as JPEG only", cfg.CWebPPath)
+		return encoders
 	}
+	return append(encoders, service.NewCWebPEncoder(path, 80))
+}
 
-	// Parse trip dates
-	startDate, _ := time.Parse("2006-01-02", trip.StartDate)
-	endDate, _ := time.Parse("2006-01-02", trip.EndDate)
-
-	// Calculate number of days
-	durationDays := int(endDate.Sub(startDate).Hours()/24) + 1
-
-	// Create day plans based on trip duration
-	for i := 0; i < durationDays; i++ {
-		currentDate := startDate.AddDate(0, 0, i)
-		dayPlan := models.DayPlan{
-			ID:        fmt.Sprintf("%s-day-%d", trip.ID, i+1),
-			TripID:    trip.ID,
-			Date:      currentDate.Format("2006-01-02"),
-			DayNumber: i + 1,
-			Notes:     ptr(fmt.Sprintf("Day %d of the journey", i+1)),
-			CreatedAt: now,
-			UpdatedAt: now,
-		}
-
-		if err := db.Create(&dayPlan).Error; err != nil {
-			return err
-		}
-
-		// Determine which destination for this day
-		var destID string
-		if trip.ID == "pt-tokyo-week-discovery" {
-			destID = destinations["Tokyo"].ID
-		} else if trip.ID == "pt-tokyo-kyoto-10days" {
-			if i < 5 {
-				destID = destinations["Tokyo"].ID
-			} else {
-				destID = destinations["Kyoto"].ID
-			}
-		} else if trip.ID == "pt-kansai-grand-tour-14days" {
-			if i < 5 {
-				destID = destinations["Tokyo"].ID
-			} else if i < 10 {
-				destID = destinations["Kyoto"].ID
-			} else {
-				destID = destinations["Osaka"].ID
-			}
-		} else if trip.ID == "pt-japan-grand-adventure-28days" {
-			// 28 days across 5 cities: Tokyo (6 days), Kyoto (7 days), Osaka (5 days), Nagoya (5 days), Hiroshima (5 days)
-			if i < 6 {
-				destID = destinations["Tokyo"].ID
-			} else if i < 13 {
-				destID = destinations["Kyoto"].ID
-			} else if i < 18 {
-				destID = destinations["Osaka"].ID
-			} else if i < 23 {
-				destID = destinations["Nagoya"].ID
-			} else {
-				destID = destinations["Hiroshima"].ID
-			}
-		}
-
-		// Link destination to day
-		dayPlanDest := models.DayPlanDestination{
-			ID:            fmt.Sprintf("dpd-%s-%d", trip.ID, i+1),
-			DayPlanID:     dayPlan.ID,
-			DestinationID: destID,
-			OrderIndex:    0,
-			PartOfDay:     ptr("all-day"),
-			CreatedAt:     now,
-		}
-		if err := db.Create(&dayPlanDest).Error; err != nil {
-			return err
-		}
-
-		// Add 2-3 activities per day
-		activityCount := 2 + (i % 2) // Alternates between 2 and 3 activities
-		for j := 0; j < activityCount; j++ {
-			var activityID string
-			timeOfDay := "start"
-
-			// Select appropriate activity based on destination and time
-			if destID == destinations["Tokyo"].ID {
-				activityID = []string{"act-senso-ji", "act-tsukiji-market", "act-shibuya-crossing"}[j%3]
-			} else if destID == destinations["Kyoto"].ID {
-				activityID = []string{"act-fushimi-inari", "act-arashiyama-bamboo"}[j%2]
-			} else if destID == destinations["Osaka"].ID {
-				activityID = []string{"act-osaka-castle", "act-dotonbori"}[j%2]
-			} else if destID == destinations["Nagoya"].ID {
-				activityID = []string{"act-nagoya-castle", "act-atsuta-shrine"}[j%2]
-			} else if destID == destinations["Hiroshima"].ID {
-				activityID = []string{"act-peace-memorial", "act-miyajima"}[j%2]
-			} else {
-				// Fallback for any other destination
-				activityID = []string{"act-senso-ji", "act-tsukiji-market"}[j%2]
-			}
-
-			if j == 0 {
-				timeOfDay = "start"
-			} else if j == 1 {
-				timeOfDay = "mid"
-			} else {
-				timeOfDay = "end"
-			}
-
-			dayPlanActivity := models.DayPlanActivity{
-				ID:              fmt.Sprintf("dpa-%s-%d-%d", trip.ID, i+1, j+1),
-				DayPlanID:       dayPlan.ID,
-				ActivityID:      activityID,
-				TimeOfDay:       timeOfDay,
-				OrderWithinTime: 0,
-				CreatedAt:       now,
-				UpdatedAt:       now,
-			}
-			if err := db.Create(&dayPlanActivity).Error; err != nil {
-				return err
-			}
+// newRoutingProvider picks the travel estimate provider; Google needs a server-usable Maps API key
+func newRoutingProvider(cfg config.MapsConfig) service.RoutingProvider {
+	if cfg.Routing == "google" {
+		if cfg.APIKey != "" {
+			return service.NewGoogleRouting(cfg.APIKey)
 		}
+		log.Println("ROUTING_PROVIDER=google needs GOOGLE_MAPS_API_KEY; using straight-line estimates")
 	}
-
-	return nil
-}
-
-func ptr[T any](v T) *T {
-	return &v
+	return service.NewStraightLineRouting()
 }
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"triply-server/internal/database"
+	"triply-server/internal/seed"
+
+	"gorm.io/gorm"
+)
+
+// runSeed handles the `seed` subcommand: triply-server seed [-dir fixtures] [set ...]
+// Sets are applied in the order given; the default is "demo".
+func runSeed(db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	dir := flags.String("dir", "fixtures", "directory containing one sub-directory per fixture set")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	sets := flags.Args()
+	if len(sets) == 0 {
+		sets = []string{"demo"}
+	}
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	if err := migrator.RequireCurrent(); err != nil {
+		return err
+	}
+
+	seeder := seed.NewSeeder(db)
+	for _, name := range sets {
+		set, err := seed.LoadSet(*dir, name)
+		if err != nil {
+			return err
+		}
+		result, err := seeder.Apply(context.Background(), set)
+		if err != nil {
+			return err
+		}
+		log.Printf("✅ Seeded %q: %d users, %d destinations, %d activities, %d trips (%d days)",
+			name, result.Users, result.Destinations, result.Activities, result.Trips, result.Days)
+	}
+	return nil
+}
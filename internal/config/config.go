@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -14,12 +16,15 @@ type Config struct {
 	Auth     AuthConfig
 	JWT      JWTConfig
 	Maps     MapsConfig
+	Admin    AdminConfig
+	Storage  StorageConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
 	Port           string
 	FrontendOrigin string
+	PublicURL      string // external base URL of the API, used in stored image URLs
 }
 
 // DatabaseConfig holds database configuration
@@ -41,7 +46,22 @@ type JWTConfig struct {
 
 // MapsConfig holds Google Maps configuration
 type MapsConfig struct {
-	APIKey string
+	APIKey  string
+	Routing string // travel estimates between activities: straight-line (default) or google
+}
+
+// AdminConfig holds configuration for operator-only endpoints
+type AdminConfig struct {
+	APIToken string // disables the admin endpoints when empty
+}
+
+// StorageConfig holds configuration for uploaded files
+type StorageConfig struct {
+	Backend        string // local (default)
+	LocalDir       string
+	MaxUploadBytes int64
+	SigningSecret  string // signs attachment download links; derived from the JWT secret when empty
+	CWebPPath      string // cwebp executable for WebP image variants; JPEG only when missing
 }
 
 // Load loads configuration from environment variables
@@ -49,10 +69,12 @@ func Load() (*Config, error) {
 	// Try to load .env file (ignore error if not found)
 	_ = godotenv.Load()
 
+	port := getEnv("PORT", "8080")
 	cfg := &Config{
 		Server: ServerConfig{
-			Port:           getEnv("PORT", "8080"),
+			Port:           port,
 			FrontendOrigin: getEnv("FRONTEND_ORIGIN", "http://localhost:5173"),
+			PublicURL:      strings.TrimRight(getEnv("PUBLIC_URL", "http://localhost:"+port), "/"),
 		},
 		Database: DatabaseConfig{
 			URL: os.Getenv("DATABASE_URL"),
@@ -66,7 +88,18 @@ func Load() (*Config, error) {
 			Secret: getEnv("JWT_SECRET", "dev-secret-change-me"),
 		},
 		Maps: MapsConfig{
-			APIKey: os.Getenv("GOOGLE_MAPS_API_KEY"),
+			APIKey:  os.Getenv("GOOGLE_MAPS_API_KEY"),
+			Routing: getEnv("ROUTING_PROVIDER", "straight-line"),
+		},
+		Admin: AdminConfig{
+			APIToken: os.Getenv("ADMIN_API_TOKEN"),
+		},
+		Storage: StorageConfig{
+			Backend:        getEnv("STORAGE_BACKEND", "local"),
+			LocalDir:       getEnv("STORAGE_LOCAL_DIR", "./uploads"),
+			MaxUploadBytes: int64(getEnvInt("MAX_UPLOAD_MB", 10)) << 20,
+			SigningSecret:  os.Getenv("DOWNLOAD_SIGNING_SECRET"),
+			CWebPPath:      getEnv("CWEBP_PATH", "cwebp"),
 		},
 	}
 
@@ -88,3 +121,10 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
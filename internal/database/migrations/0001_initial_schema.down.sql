@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS trip_likes;
+DROP TABLE IF EXISTS activity_imports;
+DROP TABLE IF EXISTS day_plan_activities;
+DROP TABLE IF EXISTS day_plan_destinations;
+DROP TABLE IF EXISTS trip_destinations;
+DROP TABLE IF EXISTS day_plans;
+DROP TABLE IF EXISTS activities;
+DROP TABLE IF EXISTS destinations;
+DROP TABLE IF EXISTS trips;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,174 @@
+-- Baseline schema. Uses IF NOT EXISTS so databases created by the old
+-- AutoMigrate-on-boot setup can be adopted without losing data.
+
+CREATE TABLE IF NOT EXISTS users (
+    id           varchar(64) PRIMARY KEY,
+    google_id    varchar(100),
+    name         varchar(255) NOT NULL,
+    email        varchar(255) NOT NULL,
+    display_name varchar(100),
+    avatar_url   text,
+    locale       varchar(10) DEFAULT 'en',
+    created_at   timestamptz,
+    updated_at   timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_google_id ON users (google_id);
+
+CREATE TABLE IF NOT EXISTS trips (
+    id             varchar(64) PRIMARY KEY,
+    user_id        varchar(64) NOT NULL,
+    name           varchar(255) NOT NULL,
+    slug           varchar(255),
+    traveler_count bigint DEFAULT 1,
+    adults         bigint DEFAULT 2,
+    children_ages  text,
+    start_date     date NOT NULL,
+    end_date       date NOT NULL,
+    cover_image    text NOT NULL,
+    visibility     varchar(20) DEFAULT 'public',
+    status         varchar(20) DEFAULT 'active',
+    summary        text,
+    traveler_type  varchar(50) NOT NULL DEFAULT '',
+    likes          bigint DEFAULT 0,
+    clone_count    bigint DEFAULT 0,
+    created_at     timestamptz,
+    updated_at     timestamptz,
+    CONSTRAINT fk_trips_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_trips_user_id ON trips (user_id);
+CREATE INDEX IF NOT EXISTS idx_trips_visibility ON trips (visibility);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trips_slug ON trips (slug);
+
+CREATE TABLE IF NOT EXISTS destinations (
+    id               varchar(64) PRIMARY KEY,
+    city             varchar(100) NOT NULL,
+    region           varchar(100),
+    country          varchar(100) NOT NULL,
+    latitude         decimal(10,8),
+    longitude        decimal(11,8),
+    timezone         varchar(50),
+    hero_image       text,
+    images           text,
+    description      text,
+    place_id         varchar(255),
+    trip_count       bigint DEFAULT 0,
+    popularity_score decimal(5,2) DEFAULT 0,
+    created_at       timestamptz,
+    updated_at       timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_popularity ON destinations (popularity_score DESC);
+
+CREATE TABLE IF NOT EXISTS activities (
+    id                      varchar(64) PRIMARY KEY,
+    title                   varchar(255) NOT NULL,
+    description             text,
+    type                    varchar(50) NOT NULL,
+    location                varchar(255),
+    address                 text,
+    latitude                decimal(10,8),
+    longitude               decimal(11,8),
+    place_id                varchar(255),
+    duration_minutes        bigint,
+    estimated_cost_amount   bigint,
+    estimated_cost_currency varchar(3),
+    image_url               text,
+    images                  text,
+    url                     text,
+    usage_count             bigint DEFAULT 0,
+    average_rating          decimal(3,2) DEFAULT 0,
+    created_by_user_id      varchar(64),
+    is_verified             boolean DEFAULT false,
+    created_at              timestamptz,
+    updated_at              timestamptz,
+    CONSTRAINT fk_activities_created_by_user FOREIGN KEY (created_by_user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_activities_type ON activities (type);
+CREATE INDEX IF NOT EXISTS idx_usage ON activities (usage_count DESC);
+
+CREATE TABLE IF NOT EXISTS day_plans (
+    id         varchar(64) PRIMARY KEY,
+    trip_id    varchar(64) NOT NULL,
+    date       date NOT NULL,
+    day_number bigint NOT NULL,
+    notes      text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_trips_day_plans FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_day_plans_trip_id ON day_plans (trip_id);
+
+CREATE TABLE IF NOT EXISTS trip_destinations (
+    id                varchar(64) PRIMARY KEY,
+    trip_id           varchar(64) NOT NULL,
+    destination_id    varchar(64) NOT NULL,
+    order_index       bigint NOT NULL,
+    start_date        date,
+    end_date          date,
+    custom_notes      text,
+    custom_hero_image text,
+    created_at        timestamptz,
+    CONSTRAINT fk_trips_trip_destinations FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE,
+    CONSTRAINT fk_destinations_trip_destinations FOREIGN KEY (destination_id) REFERENCES destinations (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trip_dest ON trip_destinations (trip_id, destination_id);
+CREATE INDEX IF NOT EXISTS idx_order ON trip_destinations (order_index);
+
+CREATE TABLE IF NOT EXISTS day_plan_destinations (
+    id             varchar(64) PRIMARY KEY,
+    day_plan_id    varchar(64) NOT NULL,
+    destination_id varchar(64) NOT NULL,
+    order_index    bigint NOT NULL,
+    part_of_day    varchar(20),
+    created_at     timestamptz,
+    CONSTRAINT fk_day_plans_day_plan_destinations FOREIGN KEY (day_plan_id) REFERENCES day_plans (id) ON DELETE CASCADE,
+    CONSTRAINT fk_destinations_day_plan_destinations FOREIGN KEY (destination_id) REFERENCES destinations (id)
+);
+CREATE INDEX IF NOT EXISTS idx_day_plan_destinations_day_plan_id ON day_plan_destinations (day_plan_id);
+
+CREATE TABLE IF NOT EXISTS day_plan_activities (
+    id                varchar(64) PRIMARY KEY,
+    day_plan_id       varchar(64) NOT NULL,
+    activity_id       varchar(64) NOT NULL,
+    time_of_day       varchar(20) NOT NULL,
+    order_within_time bigint NOT NULL,
+    custom_title      varchar(255),
+    custom_notes      text,
+    custom_time       timestamptz,
+    completed         boolean DEFAULT false,
+    skipped           boolean DEFAULT false,
+    created_at        timestamptz,
+    updated_at        timestamptz,
+    CONSTRAINT fk_day_plans_day_plan_activities FOREIGN KEY (day_plan_id) REFERENCES day_plans (id) ON DELETE CASCADE,
+    CONSTRAINT fk_activities_day_plan_activities FOREIGN KEY (activity_id) REFERENCES activities (id)
+);
+CREATE INDEX IF NOT EXISTS idx_day_time_order ON day_plan_activities (day_plan_id, time_of_day, order_within_time);
+
+CREATE TABLE IF NOT EXISTS activity_imports (
+    id                  varchar(64) PRIMARY KEY,
+    source_trip_id      varchar(64),
+    target_trip_id      varchar(64) NOT NULL,
+    activity_id         varchar(64) NOT NULL,
+    imported_by_user_id varchar(64) NOT NULL,
+    imported_at         timestamptz,
+    CONSTRAINT fk_activity_imports_source_trip FOREIGN KEY (source_trip_id) REFERENCES trips (id),
+    CONSTRAINT fk_activity_imports_target_trip FOREIGN KEY (target_trip_id) REFERENCES trips (id),
+    CONSTRAINT fk_activities_activity_imports FOREIGN KEY (activity_id) REFERENCES activities (id),
+    CONSTRAINT fk_activity_imports_imported_by_user FOREIGN KEY (imported_by_user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_activity_imports_source_trip_id ON activity_imports (source_trip_id);
+CREATE INDEX IF NOT EXISTS idx_activity_imports_target_trip_id ON activity_imports (target_trip_id);
+CREATE INDEX IF NOT EXISTS idx_activity_imports_activity_id ON activity_imports (activity_id);
+
+CREATE TABLE IF NOT EXISTS trip_likes (
+    id         varchar(64) PRIMARY KEY,
+    user_id    varchar(64) NOT NULL,
+    trip_id    varchar(64) NOT NULL,
+    created_at timestamptz,
+    CONSTRAINT fk_trip_likes_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_trip_likes_trip FOREIGN KEY (trip_id) REFERENCES trips (id)
+);
+CREATE INDEX IF NOT EXISTS idx_trip_likes_user ON trip_likes (user_id);
+CREATE INDEX IF NOT EXISTS idx_trip_likes_trip ON trip_likes (trip_id);
+CREATE INDEX IF NOT EXISTS idx_trip_likes_created ON trip_likes (created_at DESC);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_unique_user_trip_like ON trip_likes (user_id, trip_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_activity_imports_import_id;
+ALTER TABLE activity_imports DROP COLUMN IF EXISTS import_id;
@@ -0,0 +1,3 @@
+-- Groups activity_imports rows written by the same import request
+ALTER TABLE activity_imports ADD COLUMN IF NOT EXISTS import_id varchar(64);
+CREATE INDEX IF NOT EXISTS idx_activity_imports_import_id ON activity_imports (import_id);
@@ -0,0 +1,2 @@
+-- Optimistic concurrency control for trip updates
+ALTER TABLE trips ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
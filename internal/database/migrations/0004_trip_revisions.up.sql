@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS trip_revisions (
+    id                 varchar(64) PRIMARY KEY,
+    trip_id            varchar(64) NOT NULL,
+    version            bigint NOT NULL,
+    snapshot           text NOT NULL,
+    created_by_user_id varchar(64) NOT NULL,
+    created_at         timestamptz,
+    CONSTRAINT fk_trip_revisions_trip FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_trip_revisions_trip ON trip_revisions (trip_id, created_at);
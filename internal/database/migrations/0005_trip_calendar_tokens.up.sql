@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS trip_calendar_tokens (
+    id                 varchar(64) PRIMARY KEY,
+    trip_id            varchar(64) NOT NULL,
+    token              varchar(64) NOT NULL,
+    created_by_user_id varchar(64) NOT NULL,
+    created_at         timestamptz,
+    CONSTRAINT fk_trip_calendar_tokens_trip FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trip_calendar_tokens_trip_id ON trip_calendar_tokens (trip_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trip_calendar_tokens_token ON trip_calendar_tokens (token);
@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS trip_members (
+    id                 varchar(64) PRIMARY KEY,
+    trip_id            varchar(64) NOT NULL,
+    user_id            varchar(64) NOT NULL,
+    role               varchar(20) NOT NULL,
+    invited_by_user_id varchar(64),
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    CONSTRAINT fk_trip_members_trip FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE,
+    CONSTRAINT fk_trip_members_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trip_member ON trip_members (trip_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_trip_members_user_id ON trip_members (user_id);
+
+CREATE TABLE IF NOT EXISTS trip_invitations (
+    id                   varchar(64) PRIMARY KEY,
+    trip_id              varchar(64) NOT NULL,
+    email                varchar(255),
+    token                varchar(64) NOT NULL,
+    role                 varchar(20) NOT NULL,
+    status               varchar(20) NOT NULL DEFAULT 'pending',
+    invited_by_user_id   varchar(64) NOT NULL,
+    responded_by_user_id varchar(64),
+    expires_at           timestamptz,
+    responded_at         timestamptz,
+    created_at           timestamptz,
+    CONSTRAINT fk_trip_invitations_trip FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_trip_invitations_trip_id ON trip_invitations (trip_id);
+CREATE INDEX IF NOT EXISTS idx_trip_invitations_email ON trip_invitations (email);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_trip_invitations_token ON trip_invitations (token);
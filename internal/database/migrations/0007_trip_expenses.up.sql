@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS trip_expenses (
+    id                   varchar(64) PRIMARY KEY,
+    trip_id              varchar(64) NOT NULL,
+    day_plan_activity_id varchar(64),
+    day_plan_id          varchar(64),
+    destination_id       varchar(64),
+    title                varchar(255) NOT NULL,
+    category             varchar(30) NOT NULL,
+    notes                text,
+    planned_amount       bigint,
+    actual_amount        bigint,
+    currency             varchar(3) NOT NULL,
+    spent_on             date,
+    created_by_user_id   varchar(64) NOT NULL,
+    created_at           timestamptz,
+    updated_at           timestamptz,
+    CONSTRAINT fk_trip_expenses_trip FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE,
+    CONSTRAINT fk_trip_expenses_day_plan_activity FOREIGN KEY (day_plan_activity_id) REFERENCES day_plan_activities (id) ON DELETE SET NULL,
+    CONSTRAINT fk_trip_expenses_day_plan FOREIGN KEY (day_plan_id) REFERENCES day_plans (id) ON DELETE SET NULL,
+    CONSTRAINT fk_trip_expenses_destination FOREIGN KEY (destination_id) REFERENCES destinations (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_trip_expenses_trip_id ON trip_expenses (trip_id);
+CREATE INDEX IF NOT EXISTS idx_trip_expenses_day_plan_activity_id ON trip_expenses (day_plan_activity_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id             varchar(64) PRIMARY KEY,
+    base_currency  varchar(3) NOT NULL,
+    quote_currency varchar(3) NOT NULL,
+    effective_date date NOT NULL,
+    rate           decimal(20,10) NOT NULL,
+    source         varchar(100),
+    created_at     timestamptz,
+    updated_at     timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exchange_rate ON exchange_rates (base_currency, quote_currency, effective_date);
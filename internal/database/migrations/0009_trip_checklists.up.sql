@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS trip_checklists (
+    id                 varchar(64) PRIMARY KEY,
+    trip_id            varchar(64) NOT NULL,
+    title              varchar(255) NOT NULL,
+    kind               varchar(20) NOT NULL DEFAULT 'packing',
+    order_index        bigint NOT NULL DEFAULT 0,
+    created_by_user_id varchar(64) NOT NULL,
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    CONSTRAINT fk_trip_checklists_trip FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_trip_checklists_trip_id ON trip_checklists (trip_id);
+
+CREATE TABLE IF NOT EXISTS checklist_items (
+    id               varchar(64) PRIMARY KEY,
+    checklist_id     varchar(64) NOT NULL,
+    title            varchar(255) NOT NULL,
+    category         varchar(50),
+    quantity         bigint NOT NULL DEFAULT 1,
+    notes            text,
+    order_index      bigint NOT NULL DEFAULT 0,
+    assignee_user_id varchar(64),
+    assignee         varchar(100),
+    packed           boolean NOT NULL DEFAULT false,
+    packed_at        timestamptz,
+    created_at       timestamptz,
+    updated_at       timestamptz,
+    CONSTRAINT fk_trip_checklists_items FOREIGN KEY (checklist_id) REFERENCES trip_checklists (id) ON DELETE CASCADE,
+    CONSTRAINT fk_checklist_items_assignee FOREIGN KEY (assignee_user_id) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_checklist_items_checklist_id ON checklist_items (checklist_id);
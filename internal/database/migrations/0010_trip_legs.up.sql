@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS trip_legs (
+    id                       varchar(64) PRIMARY KEY,
+    trip_id                  varchar(64) NOT NULL,
+    from_destination_id      varchar(64) NOT NULL,
+    to_destination_id        varchar(64) NOT NULL,
+    mode                     varchar(20) NOT NULL,
+    carrier                  varchar(100),
+    booking_reference        varchar(100),
+    departure_time           timestamptz,
+    departure_timezone       varchar(64),
+    arrival_time             timestamptz,
+    arrival_timezone         varchar(64),
+    cost_amount              bigint,
+    cost_currency            varchar(3),
+    notes                    text,
+    created_at               timestamptz,
+    updated_at               timestamptz,
+    CONSTRAINT fk_trip_legs_trip FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE,
+    CONSTRAINT fk_trip_legs_from_destination FOREIGN KEY (from_destination_id) REFERENCES destinations (id) ON DELETE CASCADE,
+    CONSTRAINT fk_trip_legs_to_destination FOREIGN KEY (to_destination_id) REFERENCES destinations (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_trip_legs_trip_id ON trip_legs (trip_id);
@@ -0,0 +1,26 @@
+-- day_plan_activity_id has no foreign key: a full trip save recreates the day plan
+-- activities under their existing IDs, and the reservation must stay linked.
+CREATE TABLE IF NOT EXISTS trip_reservations (
+    id                    varchar(64) PRIMARY KEY,
+    trip_id               varchar(64) NOT NULL,
+    day_plan_activity_id  varchar(64),
+    trip_leg_id           varchar(64),
+    type                  varchar(20) NOT NULL,
+    provider              varchar(255),
+    confirmation_number   varchar(100),
+    start_time            timestamptz,
+    start_timezone        varchar(64),
+    end_time              timestamptz,
+    end_timezone          varchar(64),
+    cancellation_deadline timestamptz,
+    attachments           text NOT NULL DEFAULT '[]',
+    notes                 text,
+    created_by_user_id    varchar(64) NOT NULL,
+    created_at            timestamptz,
+    updated_at            timestamptz,
+    CONSTRAINT fk_trip_reservations_trip FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE,
+    CONSTRAINT fk_trip_reservations_trip_leg FOREIGN KEY (trip_leg_id) REFERENCES trip_legs (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_trip_reservations_trip_id ON trip_reservations (trip_id);
+CREATE INDEX IF NOT EXISTS idx_trip_reservations_day_plan_activity_id ON trip_reservations (day_plan_activity_id);
+CREATE INDEX IF NOT EXISTS idx_trip_reservations_trip_leg_id ON trip_reservations (trip_leg_id);
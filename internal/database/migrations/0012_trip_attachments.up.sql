@@ -0,0 +1,16 @@
+-- day_plan_activity_id has no foreign key, like trip_reservations: full trip saves
+-- recreate the day plan activities under their existing IDs.
+CREATE TABLE IF NOT EXISTS trip_attachments (
+    id                   varchar(64) PRIMARY KEY,
+    trip_id              varchar(64) NOT NULL,
+    day_plan_activity_id varchar(64),
+    file_name            varchar(255) NOT NULL,
+    content_type         varchar(100) NOT NULL,
+    size_bytes           bigint NOT NULL,
+    storage_key          varchar(255) NOT NULL,
+    uploaded_by_user_id  varchar(64) NOT NULL,
+    created_at           timestamptz,
+    CONSTRAINT fk_trip_attachments_trip FOREIGN KEY (trip_id) REFERENCES trips (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_trip_attachments_trip_id ON trip_attachments (trip_id);
+CREATE INDEX IF NOT EXISTS idx_trip_attachments_day_plan_activity_id ON trip_attachments (day_plan_activity_id);
@@ -0,0 +1,8 @@
+UPDATE trip_expenses SET day_plan_activity_id = NULL
+    WHERE day_plan_activity_id IS NOT NULL
+      AND NOT EXISTS (SELECT 1 FROM day_plan_activities dpa WHERE dpa.id = trip_expenses.day_plan_activity_id);
+UPDATE trip_expenses SET day_plan_id = NULL
+    WHERE day_plan_id IS NOT NULL
+      AND NOT EXISTS (SELECT 1 FROM day_plans dp WHERE dp.id = trip_expenses.day_plan_id);
+ALTER TABLE trip_expenses ADD CONSTRAINT fk_trip_expenses_day_plan_activity FOREIGN KEY (day_plan_activity_id) REFERENCES day_plan_activities (id) ON DELETE SET NULL;
+ALTER TABLE trip_expenses ADD CONSTRAINT fk_trip_expenses_day_plan FOREIGN KEY (day_plan_id) REFERENCES day_plans (id) ON DELETE SET NULL;
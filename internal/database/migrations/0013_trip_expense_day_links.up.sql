@@ -0,0 +1,5 @@
+-- day_plan_activity_id and day_plan_id keep no foreign keys: a full trip save recreates the
+-- day plans and their activities under their existing IDs, and the expense must stay linked.
+-- The budget rolls up links to activities or days that are gone as unscheduled.
+ALTER TABLE trip_expenses DROP CONSTRAINT IF EXISTS fk_trip_expenses_day_plan_activity;
+ALTER TABLE trip_expenses DROP CONSTRAINT IF EXISTS fk_trip_expenses_day_plan;
@@ -1,17 +1,25 @@
 package dto
 
-import "triply-server/internal/models"
-
-// ActivityOrderRequest represents a request to reorder activities
+// ActivityOrderRequest sets the complete activity layout of one day. It must list every
+// activity currently on the day, and may list activities from the trip's other days to
+// move them here. Positions are renumbered densely on the server.
 type ActivityOrderRequest struct {
-	TripID     string                   `json:"tripId"`
-	DayID      string                   `json:"dayId"`
-	Activities []models.DayPlanActivity `json:"activities"`
+	TripID     string              `json:"tripId"`
+	DayID      string              `json:"dayId"`
+	Activities []ActivityOrderItem `json:"activities"`
+}
+
+// ActivityOrderItem places a day plan activity in a time bucket
+type ActivityOrderItem struct {
+	ID              string `json:"id"`              // day plan activity ID
+	TimeOfDay       string `json:"timeOfDay"`       // defaults to the activity's current bucket
+	OrderWithinTime int    `json:"orderWithinTime"` // requested position; ties keep list order
 }
 
-// ActivityOrderPayload represents the response for activity ordering
+// ActivityOrderPayload represents an activity's canonical position after a reorder
 type ActivityOrderPayload struct {
 	ID              string `json:"id"`
+	DayID           string `json:"dayId"`
 	OrderWithinTime int    `json:"orderWithinTime"`
 	TimeOfDay       string `json:"timeOfDay"`
 }
@@ -0,0 +1,128 @@
+package dto
+
+import "time"
+
+// Trip archive format identifiers. See docs/ARCHIVE_FORMAT.md.
+const (
+	ArchiveFormat        = "triply.trip-archive"
+	ArchiveFormatVersion = 1
+)
+
+// TripArchive is a portable, self-contained backup of one or more trips.
+// IDs inside an archive are only references between its own entries; imports assign fresh IDs.
+type TripArchive struct {
+	Format     string    `json:"format"`  // always ArchiveFormat
+	Version    int       `json:"version"` // ArchiveFormatVersion at export time
+	ExportedAt time.Time `json:"exportedAt"`
+
+	Trips        []ArchiveTrip        `json:"trips"`
+	Destinations []ArchiveDestination `json:"destinations"` // library destinations referenced by the trips
+	Activities   []ArchiveActivity    `json:"activities"`   // library activities referenced by the trips
+}
+
+// ArchiveTrip is a trip and its itinerary
+type ArchiveTrip struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	StartDate     string  `json:"startDate"` // YYYY-MM-DD
+	EndDate       string  `json:"endDate"`   // YYYY-MM-DD
+	CoverImage    string  `json:"coverImage"`
+	TravelerCount int     `json:"travelerCount"`
+	Adults        int     `json:"adults"`
+	ChildrenAges  string  `json:"childrenAges"`
+	Status        string  `json:"status"`
+	Summary       *string `json:"summary,omitempty"`
+	TravelerType  string  `json:"travelerType"`
+
+	Destinations []ArchiveTripDestination `json:"destinations"`
+	Days         []ArchiveDay             `json:"days"`
+	Legs         []ArchiveLeg             `json:"legs,omitempty"`
+}
+
+// ArchiveTripDestination links a trip to a library destination
+type ArchiveTripDestination struct {
+	DestinationID   string  `json:"destinationId"`
+	OrderIndex      int     `json:"orderIndex"`
+	StartDate       *string `json:"startDate,omitempty"`
+	EndDate         *string `json:"endDate,omitempty"`
+	CustomNotes     *string `json:"customNotes,omitempty"`
+	CustomHeroImage *string `json:"customHeroImage,omitempty"`
+}
+
+// ArchiveLeg is the transport from one of a trip's destinations to the next
+type ArchiveLeg struct {
+	FromDestinationID string     `json:"fromDestinationId"`
+	ToDestinationID   string     `json:"toDestinationId"`
+	Mode              string     `json:"mode"`
+	Carrier           *string    `json:"carrier,omitempty"`
+	BookingReference  *string    `json:"bookingReference,omitempty"`
+	DepartureTime     *time.Time `json:"departureTime,omitempty"`
+	DepartureTimezone *string    `json:"departureTimezone,omitempty"`
+	ArrivalTime       *time.Time `json:"arrivalTime,omitempty"`
+	ArrivalTimezone   *string    `json:"arrivalTimezone,omitempty"`
+	CostAmount        *int       `json:"costAmount,omitempty"`
+	CostCurrency      *string    `json:"costCurrency,omitempty"`
+	Notes             *string    `json:"notes,omitempty"`
+}
+
+// ArchiveDay is a single day of a trip
+type ArchiveDay struct {
+	Date         string                  `json:"date"` // YYYY-MM-DD
+	DayNumber    int                     `json:"dayNumber"`
+	Notes        *string                 `json:"notes,omitempty"`
+	Destinations []ArchiveDayDestination `json:"destinations"`
+	Activities   []ArchiveDayActivity    `json:"activities"`
+}
+
+// ArchiveDayDestination places a library destination on a day
+type ArchiveDayDestination struct {
+	DestinationID string  `json:"destinationId"`
+	OrderIndex    int     `json:"orderIndex"`
+	PartOfDay     *string `json:"partOfDay,omitempty"`
+}
+
+// ArchiveDayActivity places a library activity on a day
+type ArchiveDayActivity struct {
+	ActivityID      string     `json:"activityId"`
+	TimeOfDay       string     `json:"timeOfDay"`
+	OrderWithinTime int        `json:"orderWithinTime"`
+	CustomTitle     *string    `json:"customTitle,omitempty"`
+	CustomNotes     *string    `json:"customNotes,omitempty"`
+	CustomTime      *time.Time `json:"customTime,omitempty"`
+	Completed       bool       `json:"completed"`
+	Skipped         bool       `json:"skipped"`
+}
+
+// ArchiveDestination is a library destination
+type ArchiveDestination struct {
+	ID          string   `json:"id"`
+	City        string   `json:"city"`
+	Region      *string  `json:"region,omitempty"`
+	Country     string   `json:"country"`
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	Timezone    *string  `json:"timezone,omitempty"`
+	HeroImage   *string  `json:"heroImage,omitempty"`
+	Images      []string `json:"images,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	PlaceID     *string  `json:"placeId,omitempty"`
+}
+
+// ArchiveActivity is a library activity
+type ArchiveActivity struct {
+	ID                    string   `json:"id"`
+	Title                 string   `json:"title"`
+	Description           *string  `json:"description,omitempty"`
+	Type                  string   `json:"type"`
+	Location              *string  `json:"location,omitempty"`
+	Address               *string  `json:"address,omitempty"`
+	Latitude              *float64 `json:"latitude,omitempty"`
+	Longitude             *float64 `json:"longitude,omitempty"`
+	PlaceID               *string  `json:"placeId,omitempty"`
+	DurationMinutes       *int     `json:"durationMinutes,omitempty"`
+	EstimatedCostAmount   *int     `json:"estimatedCostAmount,omitempty"`
+	EstimatedCostCurrency *string  `json:"estimatedCostCurrency,omitempty"`
+	ImageURL              *string  `json:"imageUrl,omitempty"`
+	Images                []string `json:"images,omitempty"`
+	URL                   *string  `json:"url,omitempty"`
+}
@@ -0,0 +1,116 @@
+package dto
+
+import "triply-server/internal/models"
+
+// CreateExpenseRequest represents a request to add an entry to a trip's expense ledger.
+// With a dayPlanActivityId the entry records that activity's cost; title, category and
+// currency then default from the activity.
+type CreateExpenseRequest struct {
+	DayPlanActivityID *string `json:"dayPlanActivityId"`
+	DayPlanID         *string `json:"dayPlanId"` // ad-hoc expenses only
+	DestinationID     *string `json:"destinationId"`
+	Title             string  `json:"title"`
+	Category          string  `json:"category"`
+	Notes             *string `json:"notes"`
+	PlannedAmount     *int    `json:"plannedAmount"`
+	ActualAmount      *int    `json:"actualAmount"`
+	Currency          string  `json:"currency"` // ISO 4217 code
+	SpentOn           *string `json:"spentOn"`  // YYYY-MM-DD
+}
+
+// UpdateExpenseRequest represents a partial update of an expense.
+// An empty string clears dayPlanActivityId, dayPlanId, destinationId and spentOn.
+type UpdateExpenseRequest struct {
+	DayPlanActivityID *string `json:"dayPlanActivityId"`
+	DayPlanID         *string `json:"dayPlanId"`
+	DestinationID     *string `json:"destinationId"`
+	Title             *string `json:"title"`
+	Category          *string `json:"category"`
+	Notes             *string `json:"notes"`
+	PlannedAmount     *int    `json:"plannedAmount"`
+	ActualAmount      *int    `json:"actualAmount"`
+	Currency          *string `json:"currency"`
+	SpentOn           *string `json:"spentOn"`
+}
+
+// CostTotal sums planned and actual costs in one currency
+type CostTotal struct {
+	Currency string `json:"currency"` // empty for estimates without a currency
+	Planned  int    `json:"planned"`
+	Actual   int    `json:"actual"`
+}
+
+// CostShare is one traveler's share of a trip total
+type CostShare struct {
+	Currency string  `json:"currency"`
+	Planned  float64 `json:"planned"`
+	Actual   float64 `json:"actual"`
+}
+
+// ActivityBudget is the cost of one scheduled activity: its estimate, unless a linked
+// expense sets a planned amount, plus the actual amounts of its expenses
+type ActivityBudget struct {
+	DayPlanActivityID string      `json:"dayPlanActivityId"`
+	DayID             string      `json:"dayId"`
+	Title             string      `json:"title"`
+	Totals            []CostTotal `json:"totals"`
+	ExpenseIDs        []string    `json:"expenseIds"`
+}
+
+// DayBudget rolls up the costs of one day
+type DayBudget struct {
+	DayID     string      `json:"dayId"`
+	DayNumber int         `json:"dayNumber"`
+	Date      string      `json:"date"`
+	Totals    []CostTotal `json:"totals"`
+}
+
+// DestinationBudget rolls up the costs attributed to one destination
+type DestinationBudget struct {
+	DestinationID string      `json:"destinationId"`
+	City          string      `json:"city"`
+	Country       string      `json:"country"`
+	Totals        []CostTotal `json:"totals"`
+}
+
+// CategoryBudget rolls up the costs of one expense category
+type CategoryBudget struct {
+	Category string      `json:"category"`
+	Totals   []CostTotal `json:"totals"`
+}
+
+// TravelerSplit divides the trip totals evenly between its travelers
+type TravelerSplit struct {
+	Adults      int         `json:"adults"`
+	Children    int         `json:"children"`
+	Travelers   int         `json:"travelers"`
+	PerTraveler []CostShare `json:"perTraveler"`
+}
+
+// BudgetSummaryQuery represents the options of a budget summary
+type BudgetSummaryQuery struct {
+	Travelers int    // overrides the split's default of Adults plus ChildrenAges when positive
+	Currency  string // converts every total into this currency
+	Date      string // YYYY-MM-DD of the exchange rates to use, defaults to the latest
+}
+
+// BudgetSummaryResponse represents a trip's costs rolled up per activity, day, destination,
+// category and trip. Totals are kept per currency unless a currency was requested.
+type BudgetSummaryResponse struct {
+	TripID                string              `json:"tripId"`
+	Currency              string              `json:"currency,omitempty"`              // the requested display currency
+	RatesDate             string              `json:"ratesDate,omitempty"`             // the exchange rate date, empty for the latest
+	UnconvertedCurrencies []string            `json:"unconvertedCurrencies,omitempty"` // currencies without a rate, left unconverted
+	Totals                []CostTotal         `json:"totals"`
+	Categories            []CategoryBudget    `json:"categories"`
+	Days                  []DayBudget         `json:"days"`
+	Destinations          []DestinationBudget `json:"destinations"`
+	Unscheduled           []CostTotal         `json:"unscheduled"` // ad-hoc expenses not tied to a day
+	Activities            []ActivityBudget    `json:"activities"`
+	Split                 TravelerSplit       `json:"split"`
+}
+
+// ExpenseListResponse represents the response for listing a trip's expenses
+type ExpenseListResponse struct {
+	Expenses []models.TripExpense `json:"expenses"`
+}
@@ -0,0 +1,8 @@
+package dto
+
+// CalendarFeedResponse represents a trip's subscribable calendar feed
+type CalendarFeedResponse struct {
+	URL       string `json:"url"`       // https URL of the .ics feed
+	WebcalURL string `json:"webcalUrl"` // same feed with the webcal:// scheme, opens calendar apps directly
+	CreatedAt string `json:"createdAt"`
+}
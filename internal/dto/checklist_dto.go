@@ -0,0 +1,55 @@
+package dto
+
+import "triply-server/internal/models"
+
+// CreateChecklistRequest represents a request to add a checklist to a trip. With
+// fromTemplate a packing list is generated from the trip and appended to items.
+type CreateChecklistRequest struct {
+	Title        string                       `json:"title"`
+	Kind         string                       `json:"kind"` // packing (default), todo
+	FromTemplate bool                         `json:"fromTemplate"`
+	Items        []CreateChecklistItemRequest `json:"items"`
+}
+
+// UpdateChecklistRequest represents a partial update of a checklist
+type UpdateChecklistRequest struct {
+	Title      *string `json:"title"`
+	OrderIndex *int    `json:"orderIndex"`
+}
+
+// CreateChecklistItemRequest represents a request to add an item to a checklist
+type CreateChecklistItemRequest struct {
+	Title          string  `json:"title"`
+	Category       *string `json:"category"`
+	Quantity       *int    `json:"quantity"` // defaults to 1
+	Notes          *string `json:"notes"`
+	AssigneeUserID *string `json:"assigneeUserId"` // must be the trip owner or a member
+	Assignee       *string `json:"assignee"`       // free-text traveler, e.g. "Kids"
+	OrderIndex     *int    `json:"orderIndex"`     // defaults to the end of the list
+}
+
+// UpdateChecklistItemRequest represents a partial update of a checklist item.
+// An empty string clears category, assigneeUserId and assignee.
+type UpdateChecklistItemRequest struct {
+	Title          *string `json:"title"`
+	Category       *string `json:"category"`
+	Quantity       *int    `json:"quantity"`
+	Notes          *string `json:"notes"`
+	AssigneeUserID *string `json:"assigneeUserId"`
+	Assignee       *string `json:"assignee"`
+	Packed         *bool   `json:"packed"`
+	OrderIndex     *int    `json:"orderIndex"`
+}
+
+// ChecklistListResponse represents the response for listing a trip's checklists
+type ChecklistListResponse struct {
+	Checklists []models.TripChecklist `json:"checklists"`
+}
+
+// ChecklistTemplateResponse is a packing list suggested for a trip, ready to be posted
+// as the items of a new checklist
+type ChecklistTemplateResponse struct {
+	Title string                       `json:"title"`
+	Kind  string                       `json:"kind"`
+	Items []CreateChecklistItemRequest `json:"items"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+// ExchangeRateTable is a set of rates against one base currency, in the format most
+// exchange-rate feeds publish: one unit of base buys rates[quote] units of each quote currency
+type ExchangeRateTable struct {
+	Base   string             `json:"base"`
+	Date   string             `json:"date"` // YYYY-MM-DD the rates take effect, defaults to today
+	Source *string            `json:"source,omitempty"`
+	Rates  map[string]float64 `json:"rates"`
+}
+
+// LoadExchangeRatesRequest loads one rate table, or several (e.g. a history) via tables
+type LoadExchangeRatesRequest struct {
+	ExchangeRateTable
+	Tables []ExchangeRateTable `json:"tables"`
+}
+
+// LoadExchangeRatesResponse reports how many rates were stored
+type LoadExchangeRatesResponse struct {
+	Loaded int `json:"loaded"`
+}
+
+// ExchangeRateResponse is one currency pair's rate in effect on the requested date
+type ExchangeRateResponse struct {
+	Base          string  `json:"base"`
+	Quote         string  `json:"quote"`
+	Rate          float64 `json:"rate"`
+	EffectiveDate string  `json:"effectiveDate"`
+	Source        *string `json:"source,omitempty"`
+}
+
+// ExchangeRateListResponse represents the rates in effect on a date
+type ExchangeRateListResponse struct {
+	Date  string                 `json:"date,omitempty"` // empty for the latest rates
+	Rates []ExchangeRateResponse `json:"rates"`
+}
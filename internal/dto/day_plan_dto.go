@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+	"triply-server/internal/models"
+)
+
+// CreateDayPlanRequest represents a request to add a day to a trip
+type CreateDayPlanRequest struct {
+	Date      string  `json:"date"`      // YYYY-MM-DD, defaults to the day after the previous day
+	DayNumber *int    `json:"dayNumber"` // position in the trip, defaults to the end
+	Notes     *string `json:"notes"`
+}
+
+// UpdateDayPlanRequest represents a partial update of a day
+type UpdateDayPlanRequest struct {
+	Date      *string `json:"date"`
+	DayNumber *int    `json:"dayNumber"` // moves the day, renumbering the others
+	Notes     *string `json:"notes"`
+}
+
+// CreateDayPlanActivityRequest represents a request to add an activity to a day
+type CreateDayPlanActivityRequest struct {
+	ActivityID      string     `json:"activityId"`
+	TimeOfDay       string     `json:"timeOfDay"`
+	OrderWithinTime *int       `json:"orderWithinTime"` // defaults to the end of the time bucket
+	CustomTitle     *string    `json:"customTitle"`
+	CustomNotes     *string    `json:"customNotes"`
+	CustomTime      *time.Time `json:"customTime"`
+	LocalTime       *string    `json:"localTime"` // HH:MM in the day's timezone, overrides customTime
+}
+
+// UpdateDayPlanActivityRequest represents a partial update of a day's activity
+type UpdateDayPlanActivityRequest struct {
+	CustomTitle *string    `json:"customTitle"`
+	CustomNotes *string    `json:"customNotes"`
+	CustomTime  *time.Time `json:"customTime"`
+	LocalTime   *string    `json:"localTime"` // HH:MM in the day's timezone, overrides customTime; "" clears the time
+	Completed   *bool      `json:"completed"`
+	Skipped     *bool      `json:"skipped"`
+}
+
+// MoveDayPlanActivityRequest represents a request to move an activity within or across days
+type MoveDayPlanActivityRequest struct {
+	DayID           string `json:"dayId"` // target day, defaults to the current day
+	TimeOfDay       string `json:"timeOfDay"`
+	OrderWithinTime int    `json:"orderWithinTime"`
+}
+
+// CreateDayPlanDestinationRequest represents a request to add a destination to a day
+type CreateDayPlanDestinationRequest struct {
+	DestinationID string  `json:"destinationId"`
+	OrderIndex    *int    `json:"orderIndex"` // defaults to the end
+	PartOfDay     *string `json:"partOfDay"`
+}
+
+// UpdateDayPlanDestinationRequest represents a partial update of a day's destination
+type UpdateDayPlanDestinationRequest struct {
+	OrderIndex *int    `json:"orderIndex"`
+	PartOfDay  *string `json:"partOfDay"`
+}
+
+// DayPlanResponse represents the response for a single day
+type DayPlanResponse struct {
+	DayPlan models.DayPlan `json:"dayPlan"`
+	Travel  *DayTravel     `json:"travel,omitempty"` // legs between the day's activities
+}
+
+// DayPlanListResponse represents the response for several days
+type DayPlanListResponse struct {
+	DayPlans []models.DayPlan `json:"dayPlans"`
+	Travel   []DayTravel      `json:"travel,omitempty"` // one entry per day, in the same order
+}
@@ -0,0 +1,16 @@
+package dto
+
+// UploadedImage represents an uploaded cover or hero image and its resized variants
+type UploadedImage struct {
+	ID       string         `json:"id"`
+	URL      string         `json:"url"` // the variant stored on the trip or destination
+	Variants []ImageVariant `json:"variants"`
+}
+
+// ImageVariant is one stored size and format of an uploaded image
+type ImageVariant struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"`
+	URL    string `json:"url"`
+}
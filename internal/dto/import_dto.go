@@ -6,11 +6,12 @@ import "triply-server/internal/models"
 type ImportSelection struct {
 	DayIDs      []string `json:"dayIds,omitempty"`
 	LegIDs      []string `json:"legIds,omitempty"`
-	ActivityIDs []string `json:"activityIds,omitempty"`
+	ActivityIDs []string `json:"activityIds,omitempty"` // day plan activity IDs in the source trip
 }
 
 // ImportTarget represents where to insert imported content
 type ImportTarget struct {
+	TripID        string  `json:"tripId"` // empty = import into a new private trip
 	DestinationID string  `json:"destinationId"`
 	DayID         *string `json:"dayId,omitempty"`
 	LegID         *string `json:"legId,omitempty"`
@@ -29,3 +30,9 @@ type ImportTripResponse struct {
 	ImportID    string      `json:"importId"`
 	UpdatedTrip models.Trip `json:"updatedTrip"`
 }
+
+// ImportCalendarRequest represents the form fields sent with an uploaded .ics file
+type ImportCalendarRequest struct {
+	TripID   string `json:"tripId" form:"tripId"`     // empty = import into a new private trip
+	TripName string `json:"tripName" form:"tripName"` // name of the new trip (defaults to the calendar name)
+}
@@ -0,0 +1,32 @@
+package dto
+
+// Itinerary warning codes
+const (
+	WarningOverlappingTimes         = "overlapping_times"
+	WarningFarFromDestination       = "far_from_destination"
+	WarningDayOutsideTripDates      = "day_outside_trip_dates"
+	WarningDuplicateDayNumber       = "duplicate_day_number"
+	WarningDayNumberGap             = "day_number_gap"
+	WarningDateGap                  = "date_gap"
+	WarningDaysOutOfOrder           = "days_out_of_order"
+	WarningDestinationRangeInvalid  = "destination_range_invalid"
+	WarningDestinationOutsideTrip   = "destination_outside_trip_dates"
+	WarningDestinationRangesOverlap = "destination_ranges_overlap"
+	WarningDestinationRangeGap      = "destination_range_gap"
+)
+
+// ItineraryWarning is a possible problem in a trip's itinerary. Warnings never block a save.
+type ItineraryWarning struct {
+	Code               string   `json:"code"`
+	Message            string   `json:"message"`
+	DayID              string   `json:"dayId,omitempty"`
+	DayPlanActivityIDs []string `json:"dayPlanActivityIds,omitempty"`
+	TripDestinationIDs []string `json:"tripDestinationIds,omitempty"`
+}
+
+// ItineraryValidationResponse represents the result of checking a trip's itinerary
+type ItineraryValidationResponse struct {
+	TripID   string             `json:"tripId"`
+	Valid    bool               `json:"valid"` // no warnings
+	Warnings []ItineraryWarning `json:"warnings"`
+}
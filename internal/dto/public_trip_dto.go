@@ -39,10 +39,33 @@ type PublicTripDetail struct {
 	PublicTripSummary
 	Highlights []string         `json:"highlights,omitempty"`
 	Itinerary  []models.DayPlan `json:"itinerary"`
+	Legs       []models.TripLeg `json:"legs"` // without booking references
+	Costs      *PublicTripCosts `json:"costs,omitempty"`
 	Author     Author           `json:"author"`
 	Metadata   Metadata         `json:"metadata"`
 }
 
+// CostAmount is an amount in one currency
+type CostAmount struct {
+	Currency string `json:"currency"`
+	Amount   int    `json:"amount"`
+}
+
+// PublicTripCosts sums the estimated costs of a public trip's activities (skipped ones excluded)
+type PublicTripCosts struct {
+	Currency              string          `json:"currency,omitempty"`              // the requested display currency
+	UnconvertedCurrencies []string        `json:"unconvertedCurrencies,omitempty"` // currencies without a rate, left unconverted
+	Total                 []CostAmount    `json:"total"`
+	Days                  []PublicDayCost `json:"days"`
+}
+
+// PublicDayCost is the estimated cost of one day of a public trip
+type PublicDayCost struct {
+	DayID     string       `json:"dayId"`
+	DayNumber int          `json:"dayNumber"`
+	Total     []CostAmount `json:"total"`
+}
+
 // DurationRange represents a duration filter range
 type DurationRange struct {
 	MinDays *int `json:"minDays"`
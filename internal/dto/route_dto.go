@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// RouteStop is one activity of a day in the proposed order
+type RouteStop struct {
+	ID                         string     `json:"id"` // day plan activity ID
+	ActivityID                 string     `json:"activityId"`
+	Title                      string     `json:"title"`
+	TimeOfDay                  string     `json:"timeOfDay"`
+	OrderWithinTime            int        `json:"orderWithinTime"`
+	CustomTime                 *time.Time `json:"customTime,omitempty"`
+	Fixed                      bool       `json:"fixed"` // has a custom time, so keeps its chronological place
+	Latitude                   *float64   `json:"latitude"`
+	Longitude                  *float64   `json:"longitude"`
+	DistanceFromPreviousMeters *int       `json:"distanceFromPreviousMeters"` // from the previous stop with coordinates
+}
+
+// RouteOptimizationResponse is a proposed order of a day's activities that shortens the
+// distance travelled. Nothing is saved: post order to /api/activities/order to apply it.
+type RouteOptimizationResponse struct {
+	DayID                   string               `json:"dayId"`
+	Stops                   []RouteStop          `json:"stops"`
+	CurrentDistanceMeters   int                  `json:"currentDistanceMeters"`
+	OptimizedDistanceMeters int                  `json:"optimizedDistanceMeters"`
+	SavedMeters             int                  `json:"savedMeters"`
+	Unlocated               []string             `json:"unlocated"` // activities without coordinates; unless fixed, they go to the end of their bucket
+	Order                   ActivityOrderRequest `json:"order"`
+}
+
+// TravelEstimate is the distance and duration of a trip in one travel mode
+type TravelEstimate struct {
+	Mode            string `json:"mode"` // walk, transit, drive
+	DistanceMeters  int    `json:"distanceMeters"`
+	DurationMinutes int    `json:"durationMinutes"`
+}
+
+// TravelLeg is the trip between two consecutive activities of a day
+type TravelLeg struct {
+	FromID                 string           `json:"fromId"` // day plan activity IDs
+	ToID                   string           `json:"toId"`
+	StraightDistanceMeters int              `json:"straightDistanceMeters"`
+	Estimates              []TravelEstimate `json:"estimates"`
+	SuggestedMode          string           `json:"suggestedMode"` // walk for short hops, otherwise transit or drive
+	Provider               string           `json:"provider"`      // the routing provider that made the estimates
+}
+
+// DayTravel is the travel between a day's activities and whether the day fits its hours
+type DayTravel struct {
+	DayID            string      `json:"dayId"`
+	Legs             []TravelLeg `json:"legs"`
+	ActivityMinutes  int         `json:"activityMinutes"` // sum of the activities' durationMinutes
+	TravelMinutes    int         `json:"travelMinutes"`   // sum of the legs in their suggested mode
+	AvailableMinutes int         `json:"availableMinutes"`
+	OverCapacity     bool        `json:"overCapacity"`
+	Warning          string      `json:"warning,omitempty"`
+}
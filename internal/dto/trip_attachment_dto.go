@@ -0,0 +1,8 @@
+package dto
+
+import "triply-server/internal/models"
+
+// TripAttachmentListResponse represents the response for listing a trip's attachments
+type TripAttachmentListResponse struct {
+	Attachments []models.TripAttachment `json:"attachments"`
+}
@@ -9,7 +9,8 @@ type TripListResponse struct {
 
 // TripDetailResponse represents the response for a single trip
 type TripDetailResponse struct {
-	Trip models.Trip `json:"trip"`
+	Trip     models.Trip        `json:"trip"`
+	Warnings []ItineraryWarning `json:"warnings,omitempty"` // only when saving with ?validate=true
 }
 
 // CreateTripRequest represents the request to create a trip
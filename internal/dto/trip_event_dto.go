@@ -0,0 +1,48 @@
+package dto
+
+import "triply-server/internal/models"
+
+// DayChangeEvent is the data of day, activity and destination events
+type DayChangeEvent struct {
+	DayID      string           `json:"dayId"`
+	ItemID     string           `json:"itemId,omitempty"`     // the day plan activity or destination that changed
+	Day        *models.DayPlan  `json:"day,omitempty"`        // the day after the change (absent when deleted)
+	Days       []models.DayPlan `json:"days,omitempty"`       // every affected day, for moves across days
+	DayNumbers map[string]int   `json:"dayNumbers,omitempty"` // other days renumbered by the change
+}
+
+// ActivitiesReorderedEvent is the data of an activities.reordered event
+type ActivitiesReorderedEvent struct {
+	DayID      string                 `json:"dayId"`
+	Activities []ActivityOrderPayload `json:"activities"`
+}
+
+// ExpenseChangeEvent is the data of expense events
+type ExpenseChangeEvent struct {
+	ExpenseID string              `json:"expenseId"`
+	Expense   *models.TripExpense `json:"expense,omitempty"` // absent when removed
+}
+
+// ChecklistChangeEvent is the data of checklist events, including changes to its items
+type ChecklistChangeEvent struct {
+	ChecklistID string                `json:"checklistId"`
+	Checklist   *models.TripChecklist `json:"checklist,omitempty"` // absent when removed
+}
+
+// LegChangeEvent is the data of trip leg events
+type LegChangeEvent struct {
+	LegID string          `json:"legId"`
+	Leg   *models.TripLeg `json:"leg,omitempty"` // absent when removed
+}
+
+// ReservationChangeEvent is the data of reservation events
+type ReservationChangeEvent struct {
+	ReservationID string                  `json:"reservationId"`
+	Reservation   *models.TripReservation `json:"reservation,omitempty"` // absent when removed
+}
+
+// AttachmentChangeEvent is the data of attachment events
+type AttachmentChangeEvent struct {
+	AttachmentID string                 `json:"attachmentId"`
+	Attachment   *models.TripAttachment `json:"attachment,omitempty"` // absent when removed
+}
@@ -0,0 +1,44 @@
+package dto
+
+import "triply-server/internal/models"
+
+// CreateTripLegRequest represents a request to add a leg between two consecutive trip destinations,
+// given by their destination IDs.
+// Times are local to their zone ("2025-04-02T09:30"); an RFC 3339 time with an offset is also
+// accepted. The zones default to the timezones of the departure and arrival destinations.
+type CreateTripLegRequest struct {
+	FromDestinationID string  `json:"fromDestinationId"`
+	ToDestinationID   string  `json:"toDestinationId"`
+	Mode              string  `json:"mode"` // flight, train, bus, car, ferry
+	Carrier           *string `json:"carrier"`
+	BookingReference  *string `json:"bookingReference"`
+	DepartureTime     *string `json:"departureTime"`
+	DepartureTimezone *string `json:"departureTimezone"`
+	ArrivalTime       *string `json:"arrivalTime"`
+	ArrivalTimezone   *string `json:"arrivalTimezone"`
+	CostAmount        *int    `json:"costAmount"`
+	CostCurrency      *string `json:"costCurrency"`
+	Notes             *string `json:"notes"`
+}
+
+// UpdateTripLegRequest represents a partial update of a trip leg. An empty string clears
+// carrier, bookingReference, the times and zones, costCurrency and notes.
+type UpdateTripLegRequest struct {
+	FromDestinationID *string `json:"fromDestinationId"`
+	ToDestinationID   *string `json:"toDestinationId"`
+	Mode              *string `json:"mode"`
+	Carrier           *string `json:"carrier"`
+	BookingReference  *string `json:"bookingReference"`
+	DepartureTime     *string `json:"departureTime"`
+	DepartureTimezone *string `json:"departureTimezone"`
+	ArrivalTime       *string `json:"arrivalTime"`
+	ArrivalTimezone   *string `json:"arrivalTimezone"`
+	CostAmount        *int    `json:"costAmount"`
+	CostCurrency      *string `json:"costCurrency"`
+	Notes             *string `json:"notes"`
+}
+
+// TripLegListResponse represents the response for listing a trip's legs
+type TripLegListResponse struct {
+	Legs []models.TripLeg `json:"legs"`
+}
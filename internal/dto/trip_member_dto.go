@@ -0,0 +1,49 @@
+package dto
+
+// TripMemberResponse represents a person with access to a trip. The owner is listed
+// alongside the invited members with the role "owner".
+type TripMemberResponse struct {
+	UserID      string  `json:"userId"`
+	Role        string  `json:"role"`
+	Name        string  `json:"name,omitempty"`
+	DisplayName *string `json:"displayName,omitempty"`
+	Email       string  `json:"email,omitempty"`
+	AvatarURL   *string `json:"avatarUrl,omitempty"`
+	JoinedAt    string  `json:"joinedAt,omitempty"`
+}
+
+// TripMemberListResponse represents the response for listing a trip's members
+type TripMemberListResponse struct {
+	Members []TripMemberResponse `json:"members"`
+}
+
+// UpdateTripMemberRequest represents the request to change a member's role
+type UpdateTripMemberRequest struct {
+	Role string `json:"role"` // editor, viewer
+}
+
+// CreateTripInvitationRequest represents the request to invite someone to a trip.
+// Without an email the invitation is a shareable link anyone can accept.
+type CreateTripInvitationRequest struct {
+	Email *string `json:"email"`
+	Role  string  `json:"role"` // editor, viewer
+}
+
+// TripInvitationResponse represents an invitation to join a trip
+type TripInvitationResponse struct {
+	ID        string  `json:"id"`
+	TripID    string  `json:"tripId"`
+	TripName  string  `json:"tripName,omitempty"`
+	Email     *string `json:"email"`
+	Role      string  `json:"role"`
+	Status    string  `json:"status"`
+	Token     string  `json:"token"`
+	URL       string  `json:"url"` // API URL of the invitation; share it or build an app link from the token
+	ExpiresAt string  `json:"expiresAt"`
+	CreatedAt string  `json:"createdAt"`
+}
+
+// TripInvitationListResponse represents the response for listing invitations
+type TripInvitationListResponse struct {
+	Invitations []TripInvitationResponse `json:"invitations"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "triply-server/internal/models"
+
+// CreateTripReservationRequest represents a request to record a reservation for one of the
+// trip's scheduled activities (dayPlanActivityId) or legs (tripLegId).
+// Times are local to their zone ("2025-04-02T15:00"); an RFC 3339 time with an offset is also
+// accepted. The zones default to the activity's day or to the leg's departure and arrival zones.
+// The cancellation deadline is local to the start zone.
+type CreateTripReservationRequest struct {
+	DayPlanActivityID    *string                        `json:"dayPlanActivityId"`
+	TripLegID            *string                        `json:"tripLegId"`
+	Type                 string                         `json:"type"` // hotel, flight, restaurant, ticket
+	Provider             *string                        `json:"provider"`
+	ConfirmationNumber   *string                        `json:"confirmationNumber"`
+	StartTime            *string                        `json:"startTime"`
+	StartTimezone        *string                        `json:"startTimezone"`
+	EndTime              *string                        `json:"endTime"`
+	EndTimezone          *string                        `json:"endTimezone"`
+	CancellationDeadline *string                        `json:"cancellationDeadline"`
+	Attachments          []models.ReservationAttachment `json:"attachments"`
+	Notes                *string                        `json:"notes"`
+}
+
+// UpdateTripReservationRequest represents a partial update of a reservation. Setting
+// dayPlanActivityId or tripLegId moves the reservation to that activity or leg. An empty
+// string clears provider, confirmationNumber, the times and zones, cancellationDeadline and
+// notes; attachments, when present, replace the stored list.
+type UpdateTripReservationRequest struct {
+	DayPlanActivityID    *string                         `json:"dayPlanActivityId"`
+	TripLegID            *string                         `json:"tripLegId"`
+	Type                 *string                         `json:"type"`
+	Provider             *string                         `json:"provider"`
+	ConfirmationNumber   *string                         `json:"confirmationNumber"`
+	StartTime            *string                         `json:"startTime"`
+	StartTimezone        *string                         `json:"startTimezone"`
+	EndTime              *string                         `json:"endTime"`
+	EndTimezone          *string                         `json:"endTimezone"`
+	CancellationDeadline *string                         `json:"cancellationDeadline"`
+	Attachments          *[]models.ReservationAttachment `json:"attachments"`
+	Notes                *string                         `json:"notes"`
+}
+
+// TripReservationListResponse represents the response for listing a trip's reservations
+type TripReservationListResponse struct {
+	Reservations []models.TripReservation `json:"reservations"`
+}
@@ -0,0 +1,77 @@
+package dto
+
+import "triply-server/internal/models"
+
+// TripRevisionSummary represents a revision in the history list (without its snapshot)
+type TripRevisionSummary struct {
+	ID              string `json:"id"`
+	Version         int    `json:"version"`
+	CreatedByUserID string `json:"createdByUserId"`
+	CreatedAt       string `json:"createdAt"`
+}
+
+// TripRevisionListResponse represents the response for listing a trip's revisions
+type TripRevisionListResponse struct {
+	Revisions []TripRevisionSummary `json:"revisions"`
+}
+
+// TripRevisionResponse represents the response for a single revision with its snapshot
+type TripRevisionResponse struct {
+	Revision models.TripRevision `json:"revision"`
+}
+
+// FieldChange represents a single changed field between two revisions
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// DayChange represents a day that was added, removed or changed
+type DayChange struct {
+	DayID     string        `json:"dayId"`
+	DayNumber int           `json:"dayNumber"`
+	Date      string        `json:"date"`
+	Changes   []FieldChange `json:"changes,omitempty"`
+}
+
+// ActivityChange represents a day plan activity that was added, removed or changed
+type ActivityChange struct {
+	DayPlanActivityID string        `json:"dayPlanActivityId"`
+	DayID             string        `json:"dayId"`
+	ActivityID        string        `json:"activityId"`
+	Title             string        `json:"title"`
+	Changes           []FieldChange `json:"changes,omitempty"`
+}
+
+// DestinationChange represents a trip destination that was added or removed
+type DestinationChange struct {
+	DestinationID string `json:"destinationId"`
+	City          string `json:"city,omitempty"`
+}
+
+// TripRevisionDiff represents the differences between two states of a trip
+type TripRevisionDiff struct {
+	From        string `json:"from"` // revision ID or "current"
+	To          string `json:"to"`
+	FromVersion int    `json:"fromVersion"`
+	ToVersion   int    `json:"toVersion"`
+
+	TripChanges []FieldChange `json:"tripChanges"`
+
+	DaysAdded   []DayChange `json:"daysAdded"`
+	DaysRemoved []DayChange `json:"daysRemoved"`
+	DaysChanged []DayChange `json:"daysChanged"`
+
+	ActivitiesAdded   []ActivityChange `json:"activitiesAdded"`
+	ActivitiesRemoved []ActivityChange `json:"activitiesRemoved"`
+	ActivitiesChanged []ActivityChange `json:"activitiesChanged"`
+
+	DestinationsAdded   []DestinationChange `json:"destinationsAdded"`
+	DestinationsRemoved []DestinationChange `json:"destinationsRemoved"`
+}
+
+// TripRevisionDiffResponse represents the response for diffing two revisions
+type TripRevisionDiffResponse struct {
+	Diff TripRevisionDiff `json:"diff"`
+}
@@ -18,30 +18,23 @@ func NewActivityHandler(activityService service.ActivityService) *ActivityHandle
 }
 
 // UpdateActivityOrder handles POST /api/activities/order
+// The body lists the full layout of one day; activities listed from the trip's other days are moved onto it.
 func (h *ActivityHandler) UpdateActivityOrder(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
 	var req dto.ActivityOrderRequest
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
 	}
 
-	if req.DayID == "" {
-		return fiber.NewError(fiber.StatusBadRequest, "dayId is required")
-	}
-
-	// Update activity orders in database
-	if err := h.activityService.UpdateActivityOrders(c.Context(), req.DayID, req.Activities); err != nil {
+	// Return the normalized orders of every affected day
+	order, err := h.activityService.UpdateActivityOrders(c.Context(), ownerID, &req)
+	if err != nil {
 		return err
 	}
 
-	// Return normalized orders
-	response := make([]dto.ActivityOrderPayload, 0, len(req.Activities))
-	for _, dpa := range req.Activities {
-		response = append(response, dto.ActivityOrderPayload{
-			ID:              dpa.ID,
-			OrderWithinTime: dpa.OrderWithinTime,
-			TimeOfDay:       dpa.TimeOfDay,
-		})
-	}
-
-	return c.JSON(response)
+	return c.JSON(order)
 }
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"triply-server/internal/dto"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ArchiveHandler handles trip archive (backup) HTTP requests
+type ArchiveHandler struct {
+	archiveService service.ArchiveService
+}
+
+// NewArchiveHandler creates a new archive handler instance
+func NewArchiveHandler(archiveService service.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{archiveService: archiveService}
+}
+
+// ExportTrip handles GET /api/trips/:tripId/archive
+func (h *ArchiveHandler) ExportTrip(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	tripID := c.Params("tripId")
+	archive, err := h.archiveService.ExportTrip(c.Context(), ownerID, tripID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+tripID+`.triply.json"`)
+	return c.JSON(archive)
+}
+
+// ExportAll handles GET /api/archive
+func (h *ArchiveHandler) ExportAll(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	archive, err := h.archiveService.ExportAll(c.Context(), ownerID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="triply-archive-`+archive.ExportedAt.Format("20060102")+`.json"`)
+	return c.JSON(archive)
+}
+
+// ImportArchive handles POST /api/archive
+// Accepts a multipart upload (field "file") or a raw JSON body.
+func (h *ArchiveHandler) ImportArchive(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid file upload")
+		}
+		defer file.Close()
+
+		data, err = io.ReadAll(file)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid file upload")
+		}
+	} else {
+		data = c.Body()
+	}
+
+	var archive dto.TripArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid archive file")
+	}
+
+	trips, err := h.archiveService.ImportArchive(c.Context(), ownerID, &archive)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.TripListResponse{Trips: trips})
+}
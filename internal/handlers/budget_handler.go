@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"triply-server/internal/dto"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BudgetHandler handles trip expense and budget HTTP requests
+type BudgetHandler struct {
+	budgetService service.BudgetService
+}
+
+// NewBudgetHandler creates a new budget handler instance
+func NewBudgetHandler(budgetService service.BudgetService) *BudgetHandler {
+	return &BudgetHandler{budgetService: budgetService}
+}
+
+// ListExpenses handles GET /api/trips/:tripId/expenses
+func (h *BudgetHandler) ListExpenses(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	expenses, err := h.budgetService.ListExpenses(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.ExpenseListResponse{Expenses: expenses})
+}
+
+// CreateExpense handles POST /api/trips/:tripId/expenses
+func (h *BudgetHandler) CreateExpense(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	expense, err := h.budgetService.CreateExpense(c.Context(), ownerID, c.Params("tripId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(expense)
+}
+
+// UpdateExpense handles PATCH /api/trips/:tripId/expenses/:expenseId
+func (h *BudgetHandler) UpdateExpense(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	expense, err := h.budgetService.UpdateExpense(c.Context(), ownerID, c.Params("tripId"), c.Params("expenseId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(expense)
+}
+
+// DeleteExpense handles DELETE /api/trips/:tripId/expenses/:expenseId
+func (h *BudgetHandler) DeleteExpense(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.budgetService.DeleteExpense(c.Context(), ownerID, c.Params("tripId"), c.Params("expenseId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
+
+// GetSummary handles GET /api/trips/:tripId/budget
+// Optional query parameters: travelers overrides the split's head count, currency converts
+// the totals, and date picks historic exchange rates.
+func (h *BudgetHandler) GetSummary(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	summary, err := h.budgetService.GetSummary(c.Context(), ownerID, c.Params("tripId"), &dto.BudgetSummaryQuery{
+		Travelers: c.QueryInt("travelers"),
+		Currency:  c.Query("currency"),
+		Date:      c.Query("date"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(summary)
+}
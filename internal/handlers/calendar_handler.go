@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CalendarHandler handles iCalendar export HTTP requests
+type CalendarHandler struct {
+	calendarService service.CalendarService
+}
+
+// NewCalendarHandler creates a new calendar handler instance
+func NewCalendarHandler(calendarService service.CalendarService) *CalendarHandler {
+	return &CalendarHandler{calendarService: calendarService}
+}
+
+// ExportTrip handles GET /api/trips/:tripId/calendar.ics
+func (h *CalendarHandler) ExportTrip(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	calendar, err := h.calendarService.ExportTrip(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="trip.ics"`)
+	return c.SendString(calendar)
+}
+
+// ExportFeed handles GET /api/calendar/:token.ics (no auth - the token grants read access)
+func (h *CalendarHandler) ExportFeed(c *fiber.Ctx) error {
+	calendar, err := h.calendarService.ExportFeed(c.Context(), c.Params("token"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.SendString(calendar)
+}
+
+// GetFeed handles GET /api/trips/:tripId/calendar/feed
+func (h *CalendarHandler) GetFeed(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	token, err := h.calendarService.GetFeedToken(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(calendarFeedResponse(c, token))
+}
+
+// CreateFeed handles POST /api/trips/:tripId/calendar/feed (creates or rotates the feed URL)
+func (h *CalendarHandler) CreateFeed(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	token, err := h.calendarService.CreateFeedToken(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(calendarFeedResponse(c, token))
+}
+
+// RevokeFeed handles DELETE /api/trips/:tripId/calendar/feed
+func (h *CalendarHandler) RevokeFeed(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.calendarService.RevokeFeedToken(c.Context(), ownerID, c.Params("tripId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
+
+func calendarFeedResponse(c *fiber.Ctx, token *models.TripCalendarToken) dto.CalendarFeedResponse {
+	url := c.BaseURL() + "/api/calendar/" + token.Token + ".ics"
+	return dto.CalendarFeedResponse{
+		URL:       url,
+		WebcalURL: "webcal://" + strings.TrimPrefix(strings.TrimPrefix(url, "https://"), "http://"),
+		CreatedAt: token.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"triply-server/internal/dto"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ChecklistHandler handles trip packing list and checklist HTTP requests
+type ChecklistHandler struct {
+	checklistService service.ChecklistService
+}
+
+// NewChecklistHandler creates a new checklist handler instance
+func NewChecklistHandler(checklistService service.ChecklistService) *ChecklistHandler {
+	return &ChecklistHandler{checklistService: checklistService}
+}
+
+// ListChecklists handles GET /api/trips/:tripId/checklists
+func (h *ChecklistHandler) ListChecklists(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	checklists, err := h.checklistService.ListChecklists(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.ChecklistListResponse{Checklists: checklists})
+}
+
+// GetTemplate handles GET /api/trips/:tripId/checklists/template
+// It previews a packing list generated from the trip without saving it.
+func (h *ChecklistHandler) GetTemplate(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	template, err := h.checklistService.GetTemplate(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(template)
+}
+
+// CreateChecklist handles POST /api/trips/:tripId/checklists
+func (h *ChecklistHandler) CreateChecklist(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateChecklistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	checklist, err := h.checklistService.CreateChecklist(c.Context(), ownerID, c.Params("tripId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(checklist)
+}
+
+// UpdateChecklist handles PATCH /api/trips/:tripId/checklists/:checklistId
+func (h *ChecklistHandler) UpdateChecklist(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateChecklistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	checklist, err := h.checklistService.UpdateChecklist(c.Context(), ownerID, c.Params("tripId"), c.Params("checklistId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(checklist)
+}
+
+// DeleteChecklist handles DELETE /api/trips/:tripId/checklists/:checklistId
+func (h *ChecklistHandler) DeleteChecklist(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.checklistService.DeleteChecklist(c.Context(), ownerID, c.Params("tripId"), c.Params("checklistId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
+
+// AddItem handles POST /api/trips/:tripId/checklists/:checklistId/items
+func (h *ChecklistHandler) AddItem(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateChecklistItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	item, err := h.checklistService.AddItem(c.Context(), ownerID, c.Params("tripId"), c.Params("checklistId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(item)
+}
+
+// UpdateItem handles PATCH /api/trips/:tripId/checklists/:checklistId/items/:itemId
+func (h *ChecklistHandler) UpdateItem(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateChecklistItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	item, err := h.checklistService.UpdateItem(c.Context(), ownerID, c.Params("tripId"), c.Params("checklistId"), c.Params("itemId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(item)
+}
+
+// DeleteItem handles DELETE /api/trips/:tripId/checklists/:checklistId/items/:itemId
+func (h *ChecklistHandler) DeleteItem(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.checklistService.DeleteItem(c.Context(), ownerID, c.Params("tripId"), c.Params("checklistId"), c.Params("itemId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
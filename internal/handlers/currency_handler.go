@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"triply-server/internal/dto"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CurrencyHandler handles exchange rate HTTP requests
+type CurrencyHandler struct {
+	currencyService service.CurrencyService
+}
+
+// NewCurrencyHandler creates a new currency handler instance
+func NewCurrencyHandler(currencyService service.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{currencyService: currencyService}
+}
+
+// ListRates handles GET /api/exchange-rates?date=YYYY-MM-DD (latest rates without a date)
+func (h *CurrencyHandler) ListRates(c *fiber.Ctx) error {
+	rates, err := h.currencyService.ListRates(c.Context(), c.Query("date"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(rates)
+}
+
+// LoadRates handles POST /api/admin/exchange-rates
+func (h *CurrencyHandler) LoadRates(c *fiber.Ctx) error {
+	var req dto.LoadExchangeRatesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	result, err := h.currencyService.LoadRates(c.Context(), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DayPlanHandler handles granular day plan HTTP requests
+type DayPlanHandler struct {
+	dayPlanService service.DayPlanService
+	routeService   service.RouteService
+}
+
+// NewDayPlanHandler creates a new day plan handler instance
+func NewDayPlanHandler(dayPlanService service.DayPlanService, routeService service.RouteService) *DayPlanHandler {
+	return &DayPlanHandler{dayPlanService: dayPlanService, routeService: routeService}
+}
+
+// CreateDayPlan handles POST /api/trips/:tripId/days
+func (h *DayPlanHandler) CreateDayPlan(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateDayPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	dayPlan, err := h.dayPlanService.CreateDayPlan(c.Context(), ownerID, c.Params("tripId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.dayPlanResponse(c, dayPlan))
+}
+
+// UpdateDayPlan handles PATCH /api/trips/:tripId/days/:dayId
+func (h *DayPlanHandler) UpdateDayPlan(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateDayPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	dayPlan, err := h.dayPlanService.UpdateDayPlan(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(h.dayPlanResponse(c, dayPlan))
+}
+
+// DeleteDayPlan handles DELETE /api/trips/:tripId/days/:dayId
+func (h *DayPlanHandler) DeleteDayPlan(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.dayPlanService.DeleteDayPlan(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
+
+// AddActivity handles POST /api/trips/:tripId/days/:dayId/activities
+func (h *DayPlanHandler) AddActivity(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateDayPlanActivityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	dayPlan, err := h.dayPlanService.AddActivity(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.dayPlanResponse(c, dayPlan))
+}
+
+// UpdateActivity handles PATCH /api/trips/:tripId/days/:dayId/activities/:activityId
+func (h *DayPlanHandler) UpdateActivity(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateDayPlanActivityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	dayPlan, err := h.dayPlanService.UpdateActivity(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"), c.Params("activityId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(h.dayPlanResponse(c, dayPlan))
+}
+
+// MoveActivity handles POST /api/trips/:tripId/days/:dayId/activities/:activityId/move
+func (h *DayPlanHandler) MoveActivity(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.MoveDayPlanActivityRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	dayPlans, err := h.dayPlanService.MoveActivity(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"), c.Params("activityId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(h.dayPlanListResponse(c, dayPlans))
+}
+
+// RemoveActivity handles DELETE /api/trips/:tripId/days/:dayId/activities/:activityId
+func (h *DayPlanHandler) RemoveActivity(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	dayPlan, err := h.dayPlanService.RemoveActivity(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"), c.Params("activityId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(h.dayPlanResponse(c, dayPlan))
+}
+
+// AddDestination handles POST /api/trips/:tripId/days/:dayId/destinations
+func (h *DayPlanHandler) AddDestination(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateDayPlanDestinationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	dayPlan, err := h.dayPlanService.AddDestination(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(h.dayPlanResponse(c, dayPlan))
+}
+
+// UpdateDestination handles PATCH /api/trips/:tripId/days/:dayId/destinations/:destinationId
+func (h *DayPlanHandler) UpdateDestination(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateDayPlanDestinationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	dayPlan, err := h.dayPlanService.UpdateDestination(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"), c.Params("destinationId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(h.dayPlanResponse(c, dayPlan))
+}
+
+// RemoveDestination handles DELETE /api/trips/:tripId/days/:dayId/destinations/:destinationId
+func (h *DayPlanHandler) RemoveDestination(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	dayPlan, err := h.dayPlanService.RemoveDestination(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"), c.Params("destinationId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(h.dayPlanResponse(c, dayPlan))
+}
+
+// dayPlanResponse adds the travel legs between the day's activities
+func (h *DayPlanHandler) dayPlanResponse(c *fiber.Ctx, dayPlan *models.DayPlan) dto.DayPlanResponse {
+	return dto.DayPlanResponse{
+		DayPlan: *dayPlan,
+		Travel:  h.routeService.DayTravel(c.Context(), dayPlan),
+	}
+}
+
+func (h *DayPlanHandler) dayPlanListResponse(c *fiber.Ctx, dayPlans []models.DayPlan) dto.DayPlanListResponse {
+	return dto.DayPlanListResponse{
+		DayPlans: dayPlans,
+		Travel:   h.routeService.DaysTravel(c.Context(), dayPlans),
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportHandler handles trip export HTTP requests
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+// NewExportHandler creates a new export handler instance
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// ExportGPX handles GET /api/trips/:tripId/route.gpx
+func (h *ExportHandler) ExportGPX(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	tripID := c.Params("tripId")
+	gpx, err := h.exportService.ExportGPX(c.Context(), ownerID, tripID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "application/gpx+xml; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+tripID+`.gpx"`)
+	return c.SendString(gpx)
+}
+
+// ExportKML handles GET /api/trips/:tripId/route.kml
+func (h *ExportHandler) ExportKML(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	tripID := c.Params("tripId")
+	kml, err := h.exportService.ExportKML(c.Context(), ownerID, tripID)
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "application/vnd.google-earth.kml+xml; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+tripID+`.kml"`)
+	return c.SendString(kml)
+}
+
+// ExportHTML handles GET /api/trips/:tripId/itinerary.html
+func (h *ExportHandler) ExportHTML(c *fiber.Ctx) error {
+	return h.exportDocument(c, service.DocumentFormatHTML)
+}
+
+// ExportMarkdown handles GET /api/trips/:tripId/itinerary.md
+func (h *ExportHandler) ExportMarkdown(c *fiber.Ctx) error {
+	return h.exportDocument(c, service.DocumentFormatMarkdown)
+}
+
+func (h *ExportHandler) exportDocument(c *fiber.Ctx, format string) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	document, err := h.exportService.ExportDocument(c.Context(), ownerID, c.Params("tripId"), format)
+	if err != nil {
+		return err
+	}
+
+	return sendDocument(c, document, format)
+}
+
+// sendDocument writes a rendered itinerary with the content type of its format
+func sendDocument(c *fiber.Ctx, document, format string) error {
+	if format == service.DocumentFormatMarkdown {
+		c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+	} else {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	}
+	return c.SendString(document)
+}
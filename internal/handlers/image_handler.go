@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImageHandler handles HTTP requests for trip cover and destination hero images
+type ImageHandler struct {
+	imageService service.ImageService
+}
+
+// NewImageHandler creates a new image handler instance
+func NewImageHandler(imageService service.ImageService) *ImageHandler {
+	return &ImageHandler{imageService: imageService}
+}
+
+// UploadTripCover handles POST /api/trips/:tripId/cover-image
+// Expects a multipart upload (field "file").
+func (h *ImageHandler) UploadTripCover(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	upload, closeUpload, err := formImage(c)
+	if err != nil {
+		return err
+	}
+	defer closeUpload()
+
+	image, err := h.imageService.UploadTripCover(c.Context(), ownerID, c.Params("tripId"), upload)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(image)
+}
+
+// UploadDestinationHero handles POST /api/admin/destinations/:destinationId/hero-image
+// Expects a multipart upload (field "file").
+func (h *ImageHandler) UploadDestinationHero(c *fiber.Ctx) error {
+	upload, closeUpload, err := formImage(c)
+	if err != nil {
+		return err
+	}
+	defer closeUpload()
+
+	image, err := h.imageService.UploadDestinationHero(c.Context(), c.Params("destinationId"), upload)
+	if err != nil {
+		return err
+	}
+	return c.Status(fiber.StatusCreated).JSON(image)
+}
+
+// GetImage handles GET /api/images/:imageId/:file
+// Needs no session: images are served to anyone with the URL, and never change once stored.
+func (h *ImageHandler) GetImage(c *fiber.Ctx) error {
+	content, contentType, err := h.imageService.OpenImage(c.Context(), c.Params("imageId"), c.Params("file"))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header.Del("Pragma")
+	c.Response().Header.Del("Expires")
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set("X-Content-Type-Options", "nosniff")
+	return c.SendStream(content)
+}
+
+// formImage opens the multipart "file" field; the caller closes it
+func formImage(c *fiber.Ctx) (*service.Upload, func() error, error) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "a multipart file field named \"file\" is required")
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusBadRequest, "invalid file upload")
+	}
+	return &service.Upload{
+		FileName: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		Content:  file,
+	}, file.Close, nil
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"io"
 	"triply-server/internal/dto"
 	"triply-server/internal/middleware"
 	"triply-server/internal/service"
@@ -37,3 +38,51 @@ func (h *ImportHandler) ImportTripParts(c *fiber.Ctx) error {
 
 	return c.JSON(result)
 }
+
+// ImportCalendar handles POST /api/import-calendar
+// Accepts a multipart upload (field "file") or a raw text/calendar body.
+func (h *ImportHandler) ImportCalendar(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.ImportCalendarRequest
+	if err := c.QueryParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid query parameters")
+	}
+
+	var data []byte
+	if fileHeader, err := c.FormFile("file"); err == nil {
+		if req.TripID == "" {
+			req.TripID = c.FormValue("tripId")
+		}
+		if req.TripName == "" {
+			req.TripName = c.FormValue("tripName")
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid file upload")
+		}
+		defer file.Close()
+
+		data, err = io.ReadAll(file)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid file upload")
+		}
+	} else {
+		data = c.Body()
+	}
+
+	if len(data) == 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "calendar file is required")
+	}
+
+	trip, err := h.importService.ImportCalendar(c.Context(), ownerID, &req, data)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.TripDetailResponse{Trip: *trip})
+}
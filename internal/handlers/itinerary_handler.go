@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ItineraryHandler handles itinerary validation HTTP requests
+type ItineraryHandler struct {
+	itineraryValidator service.ItineraryValidator
+}
+
+// NewItineraryHandler creates a new itinerary handler instance
+func NewItineraryHandler(itineraryValidator service.ItineraryValidator) *ItineraryHandler {
+	return &ItineraryHandler{itineraryValidator: itineraryValidator}
+}
+
+// ValidateTrip handles GET /api/trips/:tripId/validation
+func (h *ItineraryHandler) ValidateTrip(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	result, err := h.itineraryValidator.ValidateTrip(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(result)
+}
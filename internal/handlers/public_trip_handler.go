@@ -60,7 +60,7 @@ func (h *PublicTripHandler) ListPublicTrips(c *fiber.Ctx) error {
 	return c.JSON(resp)
 }
 
-// GetPublicTripDetail handles GET /api/public-trips/:tripId
+// GetPublicTripDetail handles GET /api/public-trips/:tripId (?currency= converts the cost estimates)
 func (h *PublicTripHandler) GetPublicTripDetail(c *fiber.Ctx) error {
 	tripID := c.Params("tripId")
 	if tripID == "" {
@@ -73,7 +73,7 @@ func (h *PublicTripHandler) GetPublicTripDetail(c *fiber.Ctx) error {
 		userID = &uid
 	}
 
-	trip, err := h.publicTripService.GetPublicTrip(c.Context(), tripID, userID)
+	trip, err := h.publicTripService.GetPublicTrip(c.Context(), tripID, userID, c.Query("currency"))
 	if err != nil {
 		return err
 	}
@@ -81,6 +81,25 @@ func (h *PublicTripHandler) GetPublicTripDetail(c *fiber.Ctx) error {
 	return c.JSON(dto.PublicTripDetailResponse{Trip: *trip})
 }
 
+// ExportHTML handles GET /api/public-trips/:tripId/itinerary.html
+func (h *PublicTripHandler) ExportHTML(c *fiber.Ctx) error {
+	return h.exportDocument(c, service.DocumentFormatHTML)
+}
+
+// ExportMarkdown handles GET /api/public-trips/:tripId/itinerary.md
+func (h *PublicTripHandler) ExportMarkdown(c *fiber.Ctx) error {
+	return h.exportDocument(c, service.DocumentFormatMarkdown)
+}
+
+func (h *PublicTripHandler) exportDocument(c *fiber.Ctx, format string) error {
+	document, err := h.publicTripService.ExportPublicTrip(c.Context(), c.Params("tripId"), format, c.Query("currency"))
+	if err != nil {
+		return err
+	}
+
+	return sendDocument(c, document, format)
+}
+
 // ToggleVisibility handles POST /api/public-trips/:tripId/visibility
 func (h *PublicTripHandler) ToggleVisibility(c *fiber.Ctx) error {
 	userID := middleware.GetUserID(c)
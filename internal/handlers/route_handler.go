@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RouteHandler handles route planning HTTP requests
+type RouteHandler struct {
+	routeService service.RouteService
+}
+
+// NewRouteHandler creates a new route handler instance
+func NewRouteHandler(routeService service.RouteService) *RouteHandler {
+	return &RouteHandler{routeService: routeService}
+}
+
+// OptimizeDay handles GET /api/trips/:tripId/days/:dayId/route/optimize
+// It previews a shorter order of the day's activities without saving it.
+func (h *RouteHandler) OptimizeDay(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	route, err := h.routeService.OptimizeDay(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(route)
+}
+
+// GetDayTravel handles GET /api/trips/:tripId/days/:dayId/travel
+func (h *RouteHandler) GetDayTravel(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	travel, err := h.routeService.GetDayTravel(c.Context(), ownerID, c.Params("tripId"), c.Params("dayId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(travel)
+}
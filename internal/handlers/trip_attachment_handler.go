@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"mime"
+	"strconv"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TripAttachmentHandler handles HTTP requests for the files attached to trips and activities
+type TripAttachmentHandler struct {
+	attachmentService service.TripAttachmentService
+}
+
+// NewTripAttachmentHandler creates a new trip attachment handler instance
+func NewTripAttachmentHandler(attachmentService service.TripAttachmentService) *TripAttachmentHandler {
+	return &TripAttachmentHandler{attachmentService: attachmentService}
+}
+
+// ListAttachments handles GET /api/trips/:tripId/attachments
+// Optional query parameter: dayPlanActivityId.
+func (h *TripAttachmentHandler) ListAttachments(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	activityID := c.Query("dayPlanActivityId")
+	attachments, err := h.attachmentService.ListAttachments(c.Context(), ownerID, c.Params("tripId"), &activityID)
+	if err != nil {
+		return err
+	}
+
+	for i := range attachments {
+		absoluteDownloadURL(c, &attachments[i])
+	}
+	return c.JSON(dto.TripAttachmentListResponse{Attachments: attachments})
+}
+
+// GetAttachment handles GET /api/trips/:tripId/attachments/:attachmentId
+func (h *TripAttachmentHandler) GetAttachment(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := h.attachmentService.GetAttachment(c.Context(), ownerID, c.Params("tripId"), c.Params("attachmentId"))
+	if err != nil {
+		return err
+	}
+
+	absoluteDownloadURL(c, attachment)
+	return c.JSON(attachment)
+}
+
+// UploadAttachment handles POST /api/trips/:tripId/attachments
+// Expects a multipart upload (field "file") with an optional "dayPlanActivityId" field.
+func (h *TripAttachmentHandler) UploadAttachment(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "a multipart file field named \"file\" is required")
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid file upload")
+	}
+	defer file.Close()
+
+	activityID := c.FormValue("dayPlanActivityId")
+	attachment, err := h.attachmentService.UploadAttachment(c.Context(), ownerID, c.Params("tripId"), &activityID, &service.Upload{
+		FileName: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		Content:  file,
+	})
+	if err != nil {
+		return err
+	}
+
+	absoluteDownloadURL(c, attachment)
+	return c.Status(fiber.StatusCreated).JSON(attachment)
+}
+
+// DeleteAttachment handles DELETE /api/trips/:tripId/attachments/:attachmentId
+func (h *TripAttachmentHandler) DeleteAttachment(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.attachmentService.DeleteAttachment(c.Context(), ownerID, c.Params("tripId"), c.Params("attachmentId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
+
+// Download handles GET /api/trips/:tripId/attachments/:attachmentId/download
+// Needs no session: the signed user, expires and signature query parameters grant access
+// while that user can view the trip.
+func (h *TripAttachmentHandler) Download(c *fiber.Ctx) error {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid download link")
+	}
+
+	attachment, content, err := h.attachmentService.OpenDownload(c.Context(), c.Params("tripId"), c.Params("attachmentId"), c.Query("user"), expires, c.Query("signature"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, attachment.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": attachment.FileName}))
+	c.Set("X-Content-Type-Options", "nosniff")
+	return c.SendStream(content, int(attachment.SizeBytes))
+}
+
+// absoluteDownloadURL prefixes the attachment's signed download path with the server's base URL
+func absoluteDownloadURL(c *fiber.Ctx, attachment *models.TripAttachment) {
+	if attachment.DownloadURL != "" {
+		attachment.DownloadURL = c.BaseURL() + attachment.DownloadURL
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// eventStreamHeartbeat is how often an idle stream sends a comment line, so proxies keep
+// the connection open and disconnected clients are noticed
+const eventStreamHeartbeat = 25 * time.Second
+
+// TripEventHandler streams live trip changes as Server-Sent Events
+type TripEventHandler struct {
+	eventService service.TripEventService
+}
+
+// NewTripEventHandler creates a new trip event handler instance
+func NewTripEventHandler(eventService service.TripEventService) *TripEventHandler {
+	return &TripEventHandler{eventService: eventService}
+}
+
+// Stream handles GET /api/trips/:tripId/events
+// The response stays open and carries one SSE message per change (event: <type>, data: <JSON event>).
+// Events are not replayed, so clients should refetch the trip whenever they (re)connect.
+func (h *TripEventHandler) Stream(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	sub, err := h.eventService.Subscribe(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // disable nginx response buffering
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		heartbeat := time.NewTicker(eventStreamHeartbeat)
+		defer heartbeat.Stop()
+
+		fmt.Fprint(w, "retry: 3000\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": ping\n\n")
+			}
+			// A failed flush means the client has gone away
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"log"
+	"strconv"
+	"strings"
 	"time"
 	"triply-server/internal/dto"
 	"triply-server/internal/middleware"
@@ -13,12 +16,13 @@ import (
 
 // TripHandler handles trip-related HTTP requests
 type TripHandler struct {
-	tripService service.TripService
+	tripService        service.TripService
+	itineraryValidator service.ItineraryValidator
 }
 
 // NewTripHandler creates a new trip handler instance
-func NewTripHandler(tripService service.TripService) *TripHandler {
-	return &TripHandler{tripService: tripService}
+func NewTripHandler(tripService service.TripService, itineraryValidator service.ItineraryValidator) *TripHandler {
+	return &TripHandler{tripService: tripService, itineraryValidator: itineraryValidator}
 }
 
 // ListTrips handles GET /api/users/:userId/trips
@@ -77,7 +81,8 @@ func (h *TripHandler) CreateTrip(c *fiber.Ctx) error {
 		return err
 	}
 
-	return c.JSON(dto.TripDetailResponse{Trip: *created})
+	c.Set(fiber.HeaderETag, tripETag(created))
+	return c.JSON(h.savedTripResponse(c, created))
 }
 
 // UpdateTrip handles PUT /api/users/:userId/trips/:tripId
@@ -106,6 +111,15 @@ func (h *TripHandler) UpdateTrip(c *fiber.Ctx) error {
 	}
 	req.Trip.UpdatedAt = time.Now()
 
+	// An If-Match header takes precedence over the version in the body
+	if ifMatch := c.Get(fiber.HeaderIfMatch); ifMatch != "" && ifMatch != "*" {
+		version, err := parseTripETag(ifMatch)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "invalid If-Match header")
+		}
+		req.Trip.Version = version
+	}
+
 	var updated *models.Trip
 	var err error
 
@@ -121,7 +135,8 @@ func (h *TripHandler) UpdateTrip(c *fiber.Ctx) error {
 		return err
 	}
 
-	return c.JSON(dto.TripDetailResponse{Trip: *updated})
+	c.Set(fiber.HeaderETag, tripETag(updated))
+	return c.JSON(h.savedTripResponse(c, updated))
 }
 
 // DeleteTrip handles DELETE /api/users/:userId/trips/:tripId
@@ -136,7 +151,7 @@ func (h *TripHandler) DeleteTrip(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "tripId is required")
 	}
 
-	if err := h.tripService.DeleteTrip(c.Context(), userID, tripID); err != nil {
+	if err := h.tripService.DeleteTrip(c.Context(), tripID, userID); err != nil {
 		return err
 	}
 
@@ -158,7 +173,8 @@ func (h *TripHandler) CloneTrip(c *fiber.Ctx) error {
 
 	// Parse request body
 	var req struct {
-		TripName string `json:"tripName" validate:"required"`
+		TripName         string `json:"tripName" validate:"required"`
+		IncludeChecklist bool   `json:"includeChecklist"` // copy the author's packing lists and checklists
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -170,10 +186,54 @@ func (h *TripHandler) CloneTrip(c *fiber.Ctx) error {
 	}
 
 	// Clone the trip
-	clonedTrip, err := h.tripService.ClonePublicTrip(c.Context(), tripID, userID, req.TripName)
+	clonedTrip, err := h.tripService.ClonePublicTrip(c.Context(), tripID, userID, req.TripName, req.IncludeChecklist)
 	if err != nil {
 		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(clonedTrip)
 }
+
+// savedTripResponse adds itinerary warnings to a saved trip when the request asks for
+// them with ?validate=true. The save has already succeeded, so a failed check only logs.
+func (h *TripHandler) savedTripResponse(c *fiber.Ctx, trip *models.Trip) dto.TripDetailResponse {
+	response := dto.TripDetailResponse{Trip: *trip}
+	if !c.QueryBool("validate") {
+		return response
+	}
+
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return response
+	}
+	result, err := h.itineraryValidator.ValidateTrip(c.Context(), ownerID, trip.ID)
+	if err != nil {
+		log.Printf("Failed to validate trip %s after save: %v", trip.ID, err)
+		return response
+	}
+	response.Warnings = result.Warnings
+	return response
+}
+
+// getOwnerID returns the authenticated user ID, falling back to the shadow user ID
+// (trips created before login are owned by the shadow user)
+func getOwnerID(c *fiber.Ctx) (string, error) {
+	if userID := middleware.GetUserID(c); userID != "" {
+		return userID, nil
+	}
+	if shadowUserID := middleware.GetShadowUserID(c); shadowUserID != "" {
+		return shadowUserID, nil
+	}
+	return "", utils.NewUnauthorizedError()
+}
+
+// tripETag formats a trip's version as a strong ETag
+func tripETag(trip *models.Trip) string {
+	return `"` + strconv.Itoa(trip.Version) + `"`
+}
+
+// parseTripETag extracts the trip version from an If-Match value such as "3" or W/"3"
+func parseTripETag(value string) (int, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	return strconv.Atoi(strings.Trim(value, `"`))
+}
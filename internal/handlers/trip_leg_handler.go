@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"triply-server/internal/dto"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TripLegHandler handles HTTP requests for the transport legs between a trip's destinations
+type TripLegHandler struct {
+	legService service.TripLegService
+}
+
+// NewTripLegHandler creates a new trip leg handler instance
+func NewTripLegHandler(legService service.TripLegService) *TripLegHandler {
+	return &TripLegHandler{legService: legService}
+}
+
+// ListLegs handles GET /api/trips/:tripId/legs
+func (h *TripLegHandler) ListLegs(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	legs, err := h.legService.ListLegs(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.TripLegListResponse{Legs: legs})
+}
+
+// CreateLeg handles POST /api/trips/:tripId/legs
+func (h *TripLegHandler) CreateLeg(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateTripLegRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	leg, err := h.legService.CreateLeg(c.Context(), ownerID, c.Params("tripId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(leg)
+}
+
+// UpdateLeg handles PATCH /api/trips/:tripId/legs/:legId
+func (h *TripLegHandler) UpdateLeg(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateTripLegRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	leg, err := h.legService.UpdateLeg(c.Context(), ownerID, c.Params("tripId"), c.Params("legId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(leg)
+}
+
+// DeleteLeg handles DELETE /api/trips/:tripId/legs/:legId
+func (h *TripLegHandler) DeleteLeg(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.legService.DeleteLeg(c.Context(), ownerID, c.Params("tripId"), c.Params("legId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/middleware"
+	"triply-server/internal/models"
+	"triply-server/internal/service"
+	"triply-server/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TripMemberHandler handles trip collaboration HTTP requests (members and invitations)
+type TripMemberHandler struct {
+	memberService service.TripMemberService
+}
+
+// NewTripMemberHandler creates a new trip member handler instance
+func NewTripMemberHandler(memberService service.TripMemberService) *TripMemberHandler {
+	return &TripMemberHandler{memberService: memberService}
+}
+
+// ListMembers handles GET /api/trips/:tripId/members
+func (h *TripMemberHandler) ListMembers(c *fiber.Ctx) error {
+	members, err := h.memberService.ListMembers(c.Context(), middleware.GetUserID(c), c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.TripMemberListResponse{Members: members})
+}
+
+// UpdateMember handles PATCH /api/trips/:tripId/members/:userId
+func (h *TripMemberHandler) UpdateMember(c *fiber.Ctx) error {
+	var req dto.UpdateTripMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	member, err := h.memberService.UpdateMemberRole(c.Context(), middleware.GetUserID(c), c.Params("tripId"), c.Params("userId"), req.Role)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(member)
+}
+
+// RemoveMember handles DELETE /api/trips/:tripId/members/:userId (members may remove themselves to leave)
+func (h *TripMemberHandler) RemoveMember(c *fiber.Ctx) error {
+	if err := h.memberService.RemoveMember(c.Context(), middleware.GetUserID(c), c.Params("tripId"), c.Params("userId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
+
+// CreateInvitation handles POST /api/trips/:tripId/invitations
+func (h *TripMemberHandler) CreateInvitation(c *fiber.Ctx) error {
+	var req dto.CreateTripInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	invitation, err := h.memberService.CreateInvitation(c.Context(), middleware.GetUserID(c), c.Params("tripId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(tripInvitationResponse(c, invitation))
+}
+
+// ListInvitations handles GET /api/trips/:tripId/invitations
+func (h *TripMemberHandler) ListInvitations(c *fiber.Ctx) error {
+	invitations, err := h.memberService.ListInvitations(c.Context(), middleware.GetUserID(c), c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tripInvitationListResponse(c, invitations))
+}
+
+// RevokeInvitation handles DELETE /api/trips/:tripId/invitations/:invitationId
+func (h *TripMemberHandler) RevokeInvitation(c *fiber.Ctx) error {
+	if err := h.memberService.RevokeInvitation(c.Context(), middleware.GetUserID(c), c.Params("tripId"), c.Params("invitationId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
+
+// ListMyInvitations handles GET /api/invitations (pending invitations addressed to the user's email)
+func (h *TripMemberHandler) ListMyInvitations(c *fiber.Ctx) error {
+	invitations, err := h.memberService.ListMyInvitations(c.Context(), middleware.GetUserID(c))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tripInvitationListResponse(c, invitations))
+}
+
+// GetInvitation handles GET /api/invitations/:token (no auth - the token identifies the invitation)
+func (h *TripMemberHandler) GetInvitation(c *fiber.Ctx) error {
+	invitation, err := h.memberService.GetInvitation(c.Context(), c.Params("token"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(tripInvitationResponse(c, invitation))
+}
+
+// AcceptInvitation handles POST /api/invitations/:token/accept
+func (h *TripMemberHandler) AcceptInvitation(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return utils.NewUnauthorizedError()
+	}
+
+	trip, err := h.memberService.AcceptInvitation(c.Context(), userID, c.Params("token"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.TripDetailResponse{Trip: *trip})
+}
+
+// DeclineInvitation handles POST /api/invitations/:token/decline
+func (h *TripMemberHandler) DeclineInvitation(c *fiber.Ctx) error {
+	userID := middleware.GetUserID(c)
+	if userID == "" {
+		return utils.NewUnauthorizedError()
+	}
+
+	if err := h.memberService.DeclineInvitation(c.Context(), userID, c.Params("token")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
+
+func tripInvitationResponse(c *fiber.Ctx, invitation *models.TripInvitation) dto.TripInvitationResponse {
+	response := dto.TripInvitationResponse{
+		ID:        invitation.ID,
+		TripID:    invitation.TripID,
+		Email:     invitation.Email,
+		Role:      invitation.Role,
+		Status:    invitation.Status,
+		Token:     invitation.Token,
+		URL:       c.BaseURL() + "/api/invitations/" + invitation.Token,
+		ExpiresAt: invitation.ExpiresAt.Format(time.RFC3339),
+		CreatedAt: invitation.CreatedAt.Format(time.RFC3339),
+	}
+	if invitation.Trip != nil {
+		response.TripName = invitation.Trip.Name
+	}
+	return response
+}
+
+func tripInvitationListResponse(c *fiber.Ctx, invitations []models.TripInvitation) dto.TripInvitationListResponse {
+	response := dto.TripInvitationListResponse{Invitations: make([]dto.TripInvitationResponse, len(invitations))}
+	for i := range invitations {
+		response.Invitations[i] = tripInvitationResponse(c, &invitations[i])
+	}
+	return response
+}
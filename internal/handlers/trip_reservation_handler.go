@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"triply-server/internal/dto"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TripReservationHandler handles HTTP requests for the bookings of a trip's activities and legs
+type TripReservationHandler struct {
+	reservationService service.TripReservationService
+}
+
+// NewTripReservationHandler creates a new trip reservation handler instance
+func NewTripReservationHandler(reservationService service.TripReservationService) *TripReservationHandler {
+	return &TripReservationHandler{reservationService: reservationService}
+}
+
+// ListReservations handles GET /api/trips/:tripId/reservations
+func (h *TripReservationHandler) ListReservations(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	reservations, err := h.reservationService.ListReservations(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.TripReservationListResponse{Reservations: reservations})
+}
+
+// CreateReservation handles POST /api/trips/:tripId/reservations
+func (h *TripReservationHandler) CreateReservation(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.CreateTripReservationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	reservation, err := h.reservationService.CreateReservation(c.Context(), ownerID, c.Params("tripId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(reservation)
+}
+
+// UpdateReservation handles PATCH /api/trips/:tripId/reservations/:reservationId
+func (h *TripReservationHandler) UpdateReservation(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	var req dto.UpdateTripReservationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "invalid request body")
+	}
+
+	reservation, err := h.reservationService.UpdateReservation(c.Context(), ownerID, c.Params("tripId"), c.Params("reservationId"), &req)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(reservation)
+}
+
+// DeleteReservation handles DELETE /api/trips/:tripId/reservations/:reservationId
+func (h *TripReservationHandler) DeleteReservation(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.reservationService.DeleteReservation(c.Context(), ownerID, c.Params("tripId"), c.Params("reservationId")); err != nil {
+		return err
+	}
+
+	return c.JSON(dto.DeleteResponse{Success: true})
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"triply-server/internal/dto"
+	"triply-server/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TripRevisionHandler handles trip revision history HTTP requests
+type TripRevisionHandler struct {
+	revisionService service.TripRevisionService
+}
+
+// NewTripRevisionHandler creates a new trip revision handler instance
+func NewTripRevisionHandler(revisionService service.TripRevisionService) *TripRevisionHandler {
+	return &TripRevisionHandler{revisionService: revisionService}
+}
+
+// ListRevisions handles GET /api/trips/:tripId/revisions
+func (h *TripRevisionHandler) ListRevisions(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := h.revisionService.ListRevisions(c.Context(), ownerID, c.Params("tripId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.TripRevisionListResponse{Revisions: revisions})
+}
+
+// GetRevision handles GET /api/trips/:tripId/revisions/:revisionId
+func (h *TripRevisionHandler) GetRevision(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	revision, err := h.revisionService.GetRevision(c.Context(), ownerID, c.Params("tripId"), c.Params("revisionId"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.TripRevisionResponse{Revision: *revision})
+}
+
+// DiffRevisions handles GET /api/trips/:tripId/revisions/diff?from=&to=
+func (h *TripRevisionHandler) DiffRevisions(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	diff, err := h.revisionService.DiffRevisions(c.Context(), ownerID, c.Params("tripId"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(dto.TripRevisionDiffResponse{Diff: *diff})
+}
+
+// RestoreRevision handles POST /api/trips/:tripId/revisions/:revisionId/restore
+func (h *TripRevisionHandler) RestoreRevision(c *fiber.Ctx) error {
+	ownerID, err := getOwnerID(c)
+	if err != nil {
+		return err
+	}
+
+	trip, err := h.revisionService.RestoreRevision(c.Context(), ownerID, c.Params("tripId"), c.Params("revisionId"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, tripETag(trip))
+	return c.JSON(dto.TripDetailResponse{Trip: *trip})
+}
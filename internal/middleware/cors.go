@@ -9,8 +9,9 @@ import (
 func NewCORS(origin string) fiber.Handler {
 	return cors.New(cors.Config{
 		AllowOrigins:     origin,
-		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders:     "Content-Type,Authorization,X-Shadow-User-ID",
+		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
+		AllowHeaders:     "Content-Type,Authorization,X-Shadow-User-ID,If-Match",
+		ExposeHeaders:    "ETag",
 		AllowCredentials: true,
 	})
 }
@@ -5,7 +5,8 @@ import "time"
 // ActivityImport tracks when activities are imported from one trip to another
 // Useful for analytics and recommendations
 type ActivityImport struct {
-	ID string `json:"id" gorm:"primaryKey;size:64"`
+	ID       string `json:"id" gorm:"primaryKey;size:64"`
+	ImportID string `json:"importId" gorm:"size:64;index"` // groups rows written by the same import request
 
 	SourceTripID     *string `json:"sourceTripId" gorm:"size:64;index"` // can be null if activity is from library
 	TargetTripID     string  `json:"targetTripId" gorm:"size:64;not null;index"`
@@ -17,6 +17,12 @@ type DayPlanActivity struct {
 	CustomNotes *string    `json:"customNotes" gorm:"type:text"`
 	CustomTime  *time.Time `json:"customTime"` // specific time if user wants
 
+	// CustomTime in the timezone of the day's destination, filled in for responses (not stored)
+	Schedule *ActivitySchedule `json:"schedule,omitempty" gorm:"-"`
+
+	// The trip's reservations for this activity, filled in for members (not stored here)
+	Reservations []TripReservation `json:"reservations,omitempty" gorm:"-"`
+
 	// Status
 	Completed bool `json:"completed" gorm:"default:false"`
 	Skipped   bool `json:"skipped" gorm:"default:false"`
@@ -33,3 +39,13 @@ type DayPlanActivity struct {
 func (DayPlanActivity) TableName() string {
 	return "day_plan_activities"
 }
+
+// ActivitySchedule is a scheduled time in both its local zone (the day's destination for
+// activities, the departure or arrival place for trip legs) and UTC
+type ActivitySchedule struct {
+	Timezone  string `json:"timezone"`  // IANA zone, e.g. Asia/Tokyo (UTC when the day's zone is unknown)
+	Local     string `json:"local"`     // RFC 3339 with the local offset
+	LocalDate string `json:"localDate"` // YYYY-MM-DD
+	LocalTime string `json:"localTime"` // HH:MM
+	UTC       string `json:"utc"`       // RFC 3339 in UTC
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ExchangeRate is the price of one unit of BaseCurrency in QuoteCurrency, in effect from
+// EffectiveDate until a later rate for the same pair is loaded
+type ExchangeRate struct {
+	ID            string  `json:"id" gorm:"primaryKey;size:64"`
+	BaseCurrency  string  `json:"baseCurrency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate"`
+	QuoteCurrency string  `json:"quoteCurrency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rate"`
+	EffectiveDate string  `json:"effectiveDate" gorm:"type:date;not null;uniqueIndex:idx_exchange_rate"`
+	Rate          float64 `json:"rate" gorm:"type:decimal(20,10);not null"`
+	Source        *string `json:"source" gorm:"size:100"` // where the rates were taken from, e.g. "ecb"
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName specifies the table name
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
@@ -41,6 +41,9 @@ type Trip struct {
 	Likes        int     `json:"likes" gorm:"default:0"`
 	CloneCount   int     `json:"cloneCount" gorm:"default:0"` // Number of times this trip has been cloned
 
+	// Optimistic concurrency - incremented on every write to the trip or its itinerary
+	Version int `json:"version" gorm:"not null;default:1"`
+
 	// Timestamps
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
@@ -49,4 +52,6 @@ type Trip struct {
 	User             *User             `json:"-" gorm:"foreignKey:UserID"`
 	DayPlans         []DayPlan         `json:"dayPlans,omitempty" gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
 	TripDestinations []TripDestination `json:"-" gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
+	TripLegs         []TripLeg         `json:"legs,omitempty" gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
+	Reservations     []TripReservation `json:"-" gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"` // shown on their activity or leg
 }
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// TripAttachment is a file uploaded to a trip, such as a ticket PDF, a photo or a voucher,
+// optionally for one of its scheduled activities. Only the trip's members can download it.
+type TripAttachment struct {
+	ID                string  `json:"id" gorm:"primaryKey;size:64"`
+	TripID            string  `json:"tripId" gorm:"size:64;not null;index"`
+	DayPlanActivityID *string `json:"dayPlanActivityId" gorm:"size:64;index"`
+
+	FileName    string `json:"fileName" gorm:"size:255;not null"`
+	ContentType string `json:"contentType" gorm:"size:100;not null"` // detected from the content
+	SizeBytes   int64  `json:"sizeBytes" gorm:"not null"`
+	StorageKey  string `json:"-" gorm:"size:255;not null"`
+
+	// Signed, time-limited download link, filled in for members (not stored)
+	DownloadURL       string     `json:"downloadUrl,omitempty" gorm:"-"`
+	DownloadExpiresAt *time.Time `json:"downloadExpiresAt,omitempty" gorm:"-"`
+
+	UploadedByUserID string    `json:"uploadedByUserId" gorm:"size:64;not null"`
+	CreatedAt        time.Time `json:"createdAt"`
+
+	// Relations
+	Trip *Trip `json:"-" gorm:"foreignKey:TripID"`
+}
+
+// TableName specifies the table name
+func (TripAttachment) TableName() string {
+	return "trip_attachments"
+}
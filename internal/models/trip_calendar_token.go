@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// TripCalendarToken grants read-only access to a trip's calendar feed for subscribing calendars
+type TripCalendarToken struct {
+	ID     string `json:"id" gorm:"primaryKey;size:64"`
+	TripID string `json:"tripId" gorm:"size:64;not null;uniqueIndex"`
+	Token  string `json:"token" gorm:"size:64;not null;uniqueIndex"`
+
+	CreatedByUserID string    `json:"createdByUserId" gorm:"size:64;not null"`
+	CreatedAt       time.Time `json:"createdAt"`
+
+	// Relations
+	Trip *Trip `json:"-" gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name
+func (TripCalendarToken) TableName() string {
+	return "trip_calendar_tokens"
+}
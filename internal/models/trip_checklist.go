@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Checklist kinds
+const (
+	ChecklistKindPacking = "packing"
+	ChecklistKindTodo    = "todo"
+)
+
+// TripChecklist is a named list of things to pack or do for a trip
+type TripChecklist struct {
+	ID              string `json:"id" gorm:"primaryKey;size:64"`
+	TripID          string `json:"tripId" gorm:"size:64;not null;index"`
+	Title           string `json:"title" gorm:"size:255;not null"`
+	Kind            string `json:"kind" gorm:"size:20;not null;default:'packing'"` // packing, todo
+	OrderIndex      int    `json:"orderIndex" gorm:"not null;default:0"`
+	CreatedByUserID string `json:"createdByUserId" gorm:"size:64;not null"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Relations
+	Trip  *Trip           `json:"-" gorm:"foreignKey:TripID"`
+	Items []ChecklistItem `json:"items" gorm:"foreignKey:ChecklistID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name
+func (TripChecklist) TableName() string {
+	return "trip_checklists"
+}
+
+// ChecklistItem is one entry of a checklist
+type ChecklistItem struct {
+	ID          string  `json:"id" gorm:"primaryKey;size:64"`
+	ChecklistID string  `json:"checklistId" gorm:"size:64;not null;index"`
+	Title       string  `json:"title" gorm:"size:255;not null"`
+	Category    *string `json:"category" gorm:"size:50"` // documents, clothing, toiletries, health, electronics, kids, gear
+	Quantity    int     `json:"quantity" gorm:"not null;default:1"`
+	Notes       *string `json:"notes" gorm:"type:text"`
+	OrderIndex  int     `json:"orderIndex" gorm:"not null;default:0"`
+
+	// Who takes care of the item: a trip member, and/or a free-text traveler such as "Kids"
+	AssigneeUserID *string `json:"assigneeUserId" gorm:"size:64"`
+	Assignee       *string `json:"assignee" gorm:"size:100"`
+
+	Packed   bool       `json:"packed" gorm:"not null;default:false"`
+	PackedAt *time.Time `json:"packedAt"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Relations
+	Checklist *TripChecklist `json:"-" gorm:"foreignKey:ChecklistID"`
+}
+
+// TableName specifies the table name
+func (ChecklistItem) TableName() string {
+	return "checklist_items"
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Expense categories
+const (
+	ExpenseCategoryAccommodation  = "accommodation"
+	ExpenseCategoryTransportation = "transportation"
+	ExpenseCategoryFood           = "food"
+	ExpenseCategoryActivities     = "activities"
+	ExpenseCategoryShopping       = "shopping"
+	ExpenseCategoryOther          = "other"
+)
+
+// TripExpense is an entry in a trip's expense ledger. Linked to a scheduled activity it
+// records that activity's planned and/or actual cost; otherwise it is an ad-hoc expense,
+// optionally attributed to a day or a destination.
+type TripExpense struct {
+	ID                string  `json:"id" gorm:"primaryKey;size:64"`
+	TripID            string  `json:"tripId" gorm:"size:64;not null;index"`
+	DayPlanActivityID *string `json:"dayPlanActivityId" gorm:"size:64;index"` // the activity's day decides where the cost rolls up
+	DayPlanID         *string `json:"dayPlanId" gorm:"size:64"`               // ad-hoc expenses only
+	DestinationID     *string `json:"destinationId" gorm:"size:64"`           // defaults to the day's first destination
+
+	Title    string  `json:"title" gorm:"size:255;not null"`
+	Category string  `json:"category" gorm:"size:30;not null"` // accommodation, transportation, food, activities, shopping, other
+	Notes    *string `json:"notes" gorm:"type:text"`
+
+	// Amounts are whole units of Currency, like Activity.EstimatedCostAmount
+	PlannedAmount *int    `json:"plannedAmount"` // overrides the activity's estimate
+	ActualAmount  *int    `json:"actualAmount"`
+	Currency      string  `json:"currency" gorm:"size:3;not null"`
+	SpentOn       *string `json:"spentOn" gorm:"type:date"`
+
+	CreatedByUserID string    `json:"createdByUserId" gorm:"size:64;not null"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+
+	// Relations
+	Trip            *Trip            `json:"-" gorm:"foreignKey:TripID"`
+	DayPlanActivity *DayPlanActivity `json:"-" gorm:"foreignKey:DayPlanActivityID"`
+}
+
+// TableName specifies the table name
+func (TripExpense) TableName() string {
+	return "trip_expenses"
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Invitation statuses
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+)
+
+// TripInvitation invites someone to join a trip with a role.
+// Email invitations are addressed to one person and used once; link invitations
+// (no email) can be accepted by anyone holding the token until revoked or expired.
+type TripInvitation struct {
+	ID     string  `json:"id" gorm:"primaryKey;size:64"`
+	TripID string  `json:"tripId" gorm:"size:64;not null;index"`
+	Email  *string `json:"email" gorm:"size:255;index"` // lowercased; nil for link invitations
+	Token  string  `json:"token" gorm:"size:64;not null;uniqueIndex"`
+	Role   string  `json:"role" gorm:"size:20;not null"`                     // editor, viewer
+	Status string  `json:"status" gorm:"size:20;not null;default:'pending'"` // pending, accepted, declined, revoked
+
+	InvitedByUserID   string     `json:"invitedByUserId" gorm:"size:64;not null"`
+	RespondedByUserID *string    `json:"respondedByUserId" gorm:"size:64"`
+	ExpiresAt         time.Time  `json:"expiresAt"`
+	RespondedAt       *time.Time `json:"respondedAt"`
+	CreatedAt         time.Time  `json:"createdAt"`
+
+	// Relations
+	Trip *Trip `json:"trip,omitempty" gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name
+func (TripInvitation) TableName() string {
+	return "trip_invitations"
+}
+
+// IsLink reports whether the invitation is a shareable link rather than addressed to an email
+func (i *TripInvitation) IsLink() bool {
+	return i.Email == nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Leg transport modes
+const (
+	LegModeFlight = "flight"
+	LegModeTrain  = "train"
+	LegModeBus    = "bus"
+	LegModeCar    = "car"
+	LegModeFerry  = "ferry"
+)
+
+// TripLeg is the journey from one of a trip's destinations to the next. The endpoints are
+// library destination IDs, which identify the trip destinations (a trip lists each destination once).
+type TripLeg struct {
+	ID                string `json:"id" gorm:"primaryKey;size:64"`
+	TripID            string `json:"tripId" gorm:"size:64;not null;index"`
+	FromDestinationID string `json:"fromDestinationId" gorm:"size:64;not null"`
+	ToDestinationID   string `json:"toDestinationId" gorm:"size:64;not null"`
+
+	Mode             string  `json:"mode" gorm:"size:20;not null"` // flight, train, bus, car, ferry
+	Carrier          *string `json:"carrier" gorm:"size:100"`
+	BookingReference *string `json:"bookingReference" gorm:"size:100"`
+
+	// Departure and arrival instants, each with the IANA zone it is local to
+	DepartureTime     *time.Time `json:"departureTime"`
+	DepartureTimezone *string    `json:"departureTimezone" gorm:"size:64"`
+	ArrivalTime       *time.Time `json:"arrivalTime"`
+	ArrivalTimezone   *string    `json:"arrivalTimezone" gorm:"size:64"`
+
+	// Departure and arrival in their local zone and UTC, filled in for responses (not stored)
+	Departure *ActivitySchedule `json:"departure,omitempty" gorm:"-"`
+	Arrival   *ActivitySchedule `json:"arrival,omitempty" gorm:"-"`
+
+	// Whole units of CostCurrency, like Activity.EstimatedCostAmount
+	CostAmount   *int    `json:"costAmount"`
+	CostCurrency *string `json:"costCurrency" gorm:"size:3"`
+
+	Notes *string `json:"notes" gorm:"type:text"`
+
+	// The trip's reservations for this leg, filled in for members (not stored here)
+	Reservations []TripReservation `json:"reservations,omitempty" gorm:"-"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Relations
+	Trip            *Trip        `json:"-" gorm:"foreignKey:TripID"`
+	FromDestination *Destination `json:"fromDestination,omitempty" gorm:"foreignKey:FromDestinationID"`
+	ToDestination   *Destination `json:"toDestination,omitempty" gorm:"foreignKey:ToDestinationID"`
+}
+
+// TableName specifies the table name
+func (TripLeg) TableName() string {
+	return "trip_legs"
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Trip roles, from most to least privileged. The trip's creator (trips.user_id) is
+// always the owner and has no trip_members row; other members are editors or viewers.
+const (
+	TripRoleOwner  = "owner"
+	TripRoleEditor = "editor"
+	TripRoleViewer = "viewer"
+)
+
+// TripMember grants a user access to someone else's trip
+type TripMember struct {
+	ID     string `json:"id" gorm:"primaryKey;size:64"`
+	TripID string `json:"tripId" gorm:"size:64;not null;uniqueIndex:idx_trip_member"`
+	UserID string `json:"userId" gorm:"size:64;not null;uniqueIndex:idx_trip_member;index"`
+	Role   string `json:"role" gorm:"size:20;not null"` // editor, viewer
+
+	InvitedByUserID *string   `json:"invitedByUserId" gorm:"size:64"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+
+	// Relations
+	Trip *Trip `json:"-" gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name
+func (TripMember) TableName() string {
+	return "trip_members"
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Reservation types
+const (
+	ReservationTypeHotel      = "hotel"
+	ReservationTypeFlight     = "flight"
+	ReservationTypeRestaurant = "restaurant"
+	ReservationTypeTicket     = "ticket"
+)
+
+// TripReservation is a booking made for a trip, linked to the scheduled activity or the leg
+// it covers. Reservations are private to the trip's members, even on public trips.
+type TripReservation struct {
+	ID                string  `json:"id" gorm:"primaryKey;size:64"`
+	TripID            string  `json:"tripId" gorm:"size:64;not null;index"`
+	DayPlanActivityID *string `json:"dayPlanActivityId" gorm:"size:64;index"`
+	TripLegID         *string `json:"tripLegId" gorm:"size:64;index"`
+
+	Type               string  `json:"type" gorm:"size:20;not null"` // hotel, flight, restaurant, ticket
+	Provider           *string `json:"provider" gorm:"size:255"`
+	ConfirmationNumber *string `json:"confirmationNumber" gorm:"size:100"`
+
+	// Check-in and check-out, departure and arrival, or the table or admission time,
+	// each with the IANA zone it is local to
+	StartTime     *time.Time `json:"startTime"`
+	StartTimezone *string    `json:"startTimezone" gorm:"size:64"`
+	EndTime       *time.Time `json:"endTime"`
+	EndTimezone   *string    `json:"endTimezone" gorm:"size:64"`
+
+	// Last moment to cancel free of charge, local to the start zone
+	CancellationDeadline *time.Time `json:"cancellationDeadline"`
+
+	// Start, end and cancellation deadline in their local zone and UTC, filled in for responses (not stored)
+	Start        *ActivitySchedule `json:"start,omitempty" gorm:"-"`
+	End          *ActivitySchedule `json:"end,omitempty" gorm:"-"`
+	Cancellation *ActivitySchedule `json:"cancellation,omitempty" gorm:"-"`
+
+	Attachments ReservationAttachments `json:"attachments" gorm:"type:text"` // JSON array
+	Notes       *string                `json:"notes" gorm:"type:text"`
+
+	CreatedByUserID string    `json:"createdByUserId" gorm:"size:64;not null"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+
+	// Relations
+	Trip    *Trip    `json:"-" gorm:"foreignKey:TripID"`
+	TripLeg *TripLeg `json:"-" gorm:"foreignKey:TripLegID"`
+}
+
+// TableName specifies the table name
+func (TripReservation) TableName() string {
+	return "trip_reservations"
+}
+
+// ReservationAttachment is a link to a document of a reservation, such as an e-ticket or a voucher
+type ReservationAttachment struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// TripSnapshot is the immutable trip tree (days, destinations, activities) captured in a revision
+type TripSnapshot struct {
+	Trip             Trip              `json:"trip"` // includes dayPlans with their destinations and activities
+	TripDestinations []TripDestination `json:"tripDestinations"`
+}
+
+// Value implements the driver.Valuer interface
+func (s TripSnapshot) Value() (driver.Value, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface
+func (s *TripSnapshot) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return errors.New("failed to scan TripSnapshot")
+	}
+}
+
+// TripRevision stores the state of a trip as it was before a full-trip save replaced it
+type TripRevision struct {
+	ID     string `json:"id" gorm:"primaryKey;size:64"`
+	TripID string `json:"tripId" gorm:"size:64;not null;index:idx_trip_revisions_trip"`
+
+	// Version of the trip captured in the snapshot
+	Version int `json:"version" gorm:"not null"`
+
+	Snapshot        TripSnapshot `json:"snapshot" gorm:"type:text;not null"`
+	CreatedByUserID string       `json:"createdByUserId" gorm:"size:64;not null"`
+
+	CreatedAt time.Time `json:"createdAt" gorm:"index:idx_trip_revisions_trip"`
+
+	// Relations
+	Trip *Trip `json:"-" gorm:"foreignKey:TripID;constraint:OnDelete:CASCADE"`
+}
+
+// TableName specifies the table name
+func (TripRevision) TableName() string {
+	return "trip_revisions"
+}
@@ -33,3 +33,31 @@ func (a *StringArray) Scan(value interface{}) error {
 		return errors.New("failed to scan StringArray")
 	}
 }
+
+// ReservationAttachments is a custom type for a JSON array of reservation attachments
+type ReservationAttachments []ReservationAttachment
+
+// Value implements the driver.Valuer interface
+func (a ReservationAttachments) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return "[]", nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan implements the sql.Scanner interface
+func (a *ReservationAttachments) Scan(value interface{}) error {
+	if value == nil {
+		*a = ReservationAttachments{}
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	default:
+		return errors.New("failed to scan ReservationAttachments")
+	}
+}
@@ -0,0 +1,72 @@
+package realtime
+
+import (
+	"context"
+	"time"
+)
+
+// Trip change event types
+const (
+	EventTripUpdated         = "trip.updated"
+	EventTripDeleted         = "trip.deleted"
+	EventTripRestored        = "trip.restored"
+	EventDayAdded            = "day.added"
+	EventDayUpdated          = "day.updated"
+	EventDayDeleted          = "day.deleted"
+	EventActivityAdded       = "activity.added"
+	EventActivityUpdated     = "activity.updated"
+	EventActivityMoved       = "activity.moved"
+	EventActivityRemoved     = "activity.removed"
+	EventActivitiesReordered = "activities.reordered"
+	EventDestinationAdded    = "destination.added"
+	EventDestinationUpdated  = "destination.updated"
+	EventDestinationRemoved  = "destination.removed"
+	EventExpenseAdded        = "expense.added"
+	EventExpenseUpdated      = "expense.updated"
+	EventExpenseRemoved      = "expense.removed"
+	EventChecklistAdded      = "checklist.added"
+	EventChecklistUpdated    = "checklist.updated"
+	EventChecklistRemoved    = "checklist.removed"
+	EventLegAdded            = "leg.added"
+	EventLegUpdated          = "leg.updated"
+	EventLegRemoved          = "leg.removed"
+	EventReservationAdded    = "reservation.added"
+	EventReservationUpdated  = "reservation.updated"
+	EventReservationRemoved  = "reservation.removed"
+	EventAttachmentAdded     = "attachment.added"
+	EventAttachmentRemoved   = "attachment.removed"
+)
+
+// Event is a structured change to a trip, delivered to everyone subscribed to the trip
+type Event struct {
+	ID      string      `json:"id"`
+	Type    string      `json:"type"`
+	TripID  string      `json:"tripId"`
+	ActorID string      `json:"actorId,omitempty"` // user whose change caused the event
+	Data    interface{} `json:"data,omitempty"`    // the changed day, days or trip, depending on Type
+	At      time.Time   `json:"at"`
+}
+
+// Publisher sends trip events to subscribers
+type Publisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+// Subscription receives the events of one trip until it is closed
+type Subscription interface {
+	// Events is closed when the subscription ends, either by Close or because the
+	// subscriber fell too far behind; clients should then reconnect and refetch
+	Events() <-chan Event
+	Close()
+}
+
+// Hub fans trip events out to subscribers. The in-process MemoryHub only reaches clients
+// connected to the same server; a pub/sub backed hub can replace it for multiple instances.
+type Hub interface {
+	Publisher
+	// Subscribe starts receiving the trip's events on behalf of the user
+	Subscribe(tripID, userID string) Subscription
+	// Disconnect closes the user's subscriptions to the trip, or every subscription to it
+	// when userID is empty, once they may no longer see its changes
+	Disconnect(tripID, userID string)
+}
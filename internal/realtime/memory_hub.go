@@ -0,0 +1,100 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriberBuffer is how many events a subscriber may lag behind before it is dropped
+const subscriberBuffer = 32
+
+// MemoryHub is an in-process Hub
+type MemoryHub struct {
+	mu     sync.Mutex
+	topics map[string]map[*memorySubscription]struct{}
+}
+
+// NewMemoryHub creates a new in-process hub
+func NewMemoryHub() *MemoryHub {
+	return &MemoryHub{topics: make(map[string]map[*memorySubscription]struct{})}
+}
+
+// Publish delivers the event to the trip's current subscribers without blocking.
+// A subscriber whose buffer is full is closed rather than silently missing events.
+func (h *MemoryHub) Publish(ctx context.Context, event Event) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.topics[event.TripID] {
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribe starts receiving the trip's events on behalf of the user
+func (h *MemoryHub) Subscribe(tripID, userID string) Subscription {
+	sub := &memorySubscription{
+		hub:    h,
+		tripID: tripID,
+		userID: userID,
+		events: make(chan Event, subscriberBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.topics[tripID] == nil {
+		h.topics[tripID] = make(map[*memorySubscription]struct{})
+	}
+	h.topics[tripID][sub] = struct{}{}
+	return sub
+}
+
+// Disconnect closes the user's subscriptions to the trip, or all of them when userID is empty.
+// Events already delivered stay readable, so a final trip.deleted still reaches the client.
+func (h *MemoryHub) Disconnect(tripID, userID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.topics[tripID] {
+		if userID == "" || sub.userID == userID {
+			h.remove(sub)
+		}
+	}
+}
+
+// remove unregisters the subscription and closes its channel; callers hold h.mu
+func (h *MemoryHub) remove(sub *memorySubscription) {
+	subs, ok := h.topics[sub.tripID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.topics, sub.tripID)
+	}
+	close(sub.events)
+}
+
+type memorySubscription struct {
+	hub    *MemoryHub
+	tripID string
+	userID string
+	events chan Event
+}
+
+func (s *memorySubscription) Events() <-chan Event {
+	return s.events
+}
+
+func (s *memorySubscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
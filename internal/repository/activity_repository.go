@@ -10,7 +10,8 @@ import (
 // ActivityRepository defines the interface for activity data operations
 type ActivityRepository interface {
 	FindByDayPlanID(ctx context.Context, dayPlanID string) ([]models.DayPlanActivity, error)
-	UpdateOrders(ctx context.Context, dayPlanActivities []models.DayPlanActivity) error
+	FindByTitle(ctx context.Context, title, location string) (*models.Activity, error)
+	FindByIDs(ctx context.Context, ids []string) ([]models.Activity, error)
 }
 
 type activityRepository struct {
@@ -35,18 +36,35 @@ func (r *activityRepository) FindByDayPlanID(ctx context.Context, dayPlanID stri
 	return dayPlanActivities, nil
 }
 
-func (r *activityRepository) UpdateOrders(ctx context.Context, dayPlanActivities []models.DayPlanActivity) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		for _, dpa := range dayPlanActivities {
-			if err := tx.Model(&models.DayPlanActivity{}).
-				Where("id = ?", dpa.ID).
-				Updates(map[string]interface{}{
-					"time_of_day":       dpa.TimeOfDay,
-					"order_within_time": dpa.OrderWithinTime,
-				}).Error; err != nil {
-				return err
-			}
-		}
-		return nil
-	})
+// FindByTitle finds a library activity by title and location (case-insensitive), preferring
+// verified and frequently used entries
+func (r *activityRepository) FindByTitle(ctx context.Context, title, location string) (*models.Activity, error) {
+	var activity models.Activity
+	query := r.db.WithContext(ctx).Where("LOWER(title) = LOWER(?)", title)
+	if location == "" {
+		query = query.Where("location IS NULL OR location = ''")
+	} else {
+		query = query.Where("LOWER(location) = LOWER(?)", location)
+	}
+	err := query.
+		Order("is_verified DESC, usage_count DESC").
+		First(&activity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+func (r *activityRepository) FindByIDs(ctx context.Context, ids []string) ([]models.Activity, error) {
+	var activities []models.Activity
+	if len(ids) == 0 {
+		return activities, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&activities).Error
+	if err != nil {
+		return nil, err
+	}
+	return activities, nil
 }
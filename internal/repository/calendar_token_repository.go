@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// CalendarTokenRepository defines the interface for calendar feed token data operations
+type CalendarTokenRepository interface {
+	FindByTripID(ctx context.Context, tripID string) (*models.TripCalendarToken, error)
+	FindByToken(ctx context.Context, token string) (*models.TripCalendarToken, error)
+	Replace(ctx context.Context, token *models.TripCalendarToken) error
+	DeleteByTripID(ctx context.Context, tripID string) error
+}
+
+type calendarTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewCalendarTokenRepository creates a new calendar token repository instance
+func NewCalendarTokenRepository(db *gorm.DB) CalendarTokenRepository {
+	return &calendarTokenRepository{db: db}
+}
+
+func (r *calendarTokenRepository) FindByTripID(ctx context.Context, tripID string) (*models.TripCalendarToken, error) {
+	var token models.TripCalendarToken
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByToken looks up a feed token together with its trip (for the owner ID)
+func (r *calendarTokenRepository) FindByToken(ctx context.Context, token string) (*models.TripCalendarToken, error) {
+	var calendarToken models.TripCalendarToken
+	err := r.db.WithContext(ctx).
+		Where("token = ?", token).
+		Preload("Trip").
+		First(&calendarToken).Error
+	if err != nil {
+		return nil, err
+	}
+	return &calendarToken, nil
+}
+
+// Replace stores the token as the trip's only feed token, invalidating any previous one
+func (r *calendarTokenRepository) Replace(ctx context.Context, token *models.TripCalendarToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("trip_id = ?", token.TripID).Delete(&models.TripCalendarToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (r *calendarTokenRepository) DeleteByTripID(ctx context.Context, tripID string) error {
+	return r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		Delete(&models.TripCalendarToken{}).Error
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChecklistRepository defines the interface for trip checklist data operations
+type ChecklistRepository interface {
+	FindByTripID(ctx context.Context, tripID string) ([]models.TripChecklist, error)
+	FindByID(ctx context.Context, tripID, checklistID string) (*models.TripChecklist, error)
+	Create(ctx context.Context, checklists ...*models.TripChecklist) error
+	Update(ctx context.Context, checklist *models.TripChecklist) error
+	Delete(ctx context.Context, tripID, checklistID string) error
+	FindItem(ctx context.Context, checklistID, itemID string) (*models.ChecklistItem, error)
+	CreateItem(ctx context.Context, item *models.ChecklistItem) error
+	UpdateItem(ctx context.Context, item *models.ChecklistItem) error
+	DeleteItem(ctx context.Context, checklistID, itemID string) error
+}
+
+type checklistRepository struct {
+	db *gorm.DB
+}
+
+// NewChecklistRepository creates a new checklist repository instance
+func NewChecklistRepository(db *gorm.DB) ChecklistRepository {
+	return &checklistRepository{db: db}
+}
+
+// FindByTripID lists the trip's checklists with their items, both in display order
+func (r *checklistRepository) FindByTripID(ctx context.Context, tripID string) ([]models.TripChecklist, error) {
+	var checklists []models.TripChecklist
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		Preload("Items", orderedItems).
+		Order("order_index ASC, created_at ASC").
+		Find(&checklists).Error
+	if err != nil {
+		return nil, err
+	}
+	return checklists, nil
+}
+
+func (r *checklistRepository) FindByID(ctx context.Context, tripID, checklistID string) (*models.TripChecklist, error) {
+	var checklist models.TripChecklist
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, checklistID).
+		Preload("Items", orderedItems).
+		First(&checklist).Error
+	if err != nil {
+		return nil, err
+	}
+	return &checklist, nil
+}
+
+// Create stores the checklists together with their items in one transaction
+func (r *checklistRepository) Create(ctx context.Context, checklists ...*models.TripChecklist) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, checklist := range checklists {
+			if err := tx.Omit("Trip").Create(checklist).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Update saves the checklist's own fields; items are changed through the item methods
+func (r *checklistRepository) Update(ctx context.Context, checklist *models.TripChecklist) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(checklist).Error
+}
+
+func (r *checklistRepository) Delete(ctx context.Context, tripID, checklistID string) error {
+	result := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, checklistID).
+		Delete(&models.TripChecklist{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *checklistRepository) FindItem(ctx context.Context, checklistID, itemID string) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	err := r.db.WithContext(ctx).
+		Where("checklist_id = ? AND id = ?", checklistID, itemID).
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *checklistRepository) CreateItem(ctx context.Context, item *models.ChecklistItem) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(item).Error
+}
+
+func (r *checklistRepository) UpdateItem(ctx context.Context, item *models.ChecklistItem) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(item).Error
+}
+
+func (r *checklistRepository) DeleteItem(ctx context.Context, checklistID, itemID string) error {
+	result := r.db.WithContext(ctx).
+		Where("checklist_id = ? AND id = ?", checklistID, itemID).
+		Delete(&models.ChecklistItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func orderedItems(db *gorm.DB) *gorm.DB {
+	return db.Order("checklist_items.order_index ASC, checklist_items.created_at ASC")
+}
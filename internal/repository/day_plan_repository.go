@@ -0,0 +1,279 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DayPlanRepository defines the interface for granular day plan operations
+type DayPlanRepository interface {
+	FindByID(ctx context.Context, tripID, dayPlanID string) (*models.DayPlan, error)
+	FindByTripID(ctx context.Context, tripID string) ([]models.DayPlan, error)
+	FindActivitiesByTripID(ctx context.Context, tripID string) ([]models.DayPlanActivity, error)
+	Create(ctx context.Context, dayPlan *models.DayPlan, renumbered []models.DayPlan, userID string) error
+	Update(ctx context.Context, dayPlan *models.DayPlan, renumbered []models.DayPlan, userID string) error
+	Delete(ctx context.Context, dayPlan *models.DayPlan, renumbered []models.DayPlan, userID string) error
+
+	CreateActivity(ctx context.Context, tripID string, dpa *models.DayPlanActivity, positions []models.DayPlanActivity, userID string) error
+	UpdateActivity(ctx context.Context, tripID string, dpa *models.DayPlanActivity, userID string) error
+	DeleteActivity(ctx context.Context, tripID string, dpa *models.DayPlanActivity, positions []models.DayPlanActivity, userID string) error
+	MoveActivities(ctx context.Context, tripID string, positions []models.DayPlanActivity, userID string) error
+
+	CreateDestination(ctx context.Context, tripID string, dpd *models.DayPlanDestination, positions []models.DayPlanDestination, userID string) error
+	UpdateDestination(ctx context.Context, tripID string, dpd *models.DayPlanDestination, positions []models.DayPlanDestination, userID string) error
+	DeleteDestination(ctx context.Context, tripID string, dpd *models.DayPlanDestination, positions []models.DayPlanDestination, userID string) error
+
+	ActivityExists(ctx context.Context, activityID string) (bool, error)
+	DestinationExists(ctx context.Context, destinationID string) (bool, error)
+}
+
+type dayPlanRepository struct {
+	db *gorm.DB
+}
+
+// NewDayPlanRepository creates a new day plan repository instance
+func NewDayPlanRepository(db *gorm.DB) DayPlanRepository {
+	return &dayPlanRepository{db: db}
+}
+
+func (r *dayPlanRepository) FindByID(ctx context.Context, tripID, dayPlanID string) (*models.DayPlan, error) {
+	var dayPlan models.DayPlan
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND trip_id = ?", dayPlanID, tripID).
+		Preload("DayPlanDestinations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_plan_destinations.order_index ASC")
+		}).
+		Preload("DayPlanDestinations.Destination").
+		Preload("DayPlanActivities", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_plan_activities.time_of_day, day_plan_activities.order_within_time ASC")
+		}).
+		Preload("DayPlanActivities.Activity").
+		First(&dayPlan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &dayPlan, nil
+}
+
+func (r *dayPlanRepository) FindByTripID(ctx context.Context, tripID string) ([]models.DayPlan, error) {
+	var dayPlans []models.DayPlan
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		Order("day_number ASC").
+		Find(&dayPlans).Error
+	if err != nil {
+		return nil, err
+	}
+	return dayPlans, nil
+}
+
+// FindActivitiesByTripID lists the activities scheduled on any of the trip's days, in position order
+func (r *dayPlanRepository) FindActivitiesByTripID(ctx context.Context, tripID string) ([]models.DayPlanActivity, error) {
+	var dayPlanActivities []models.DayPlanActivity
+	err := r.db.WithContext(ctx).
+		Select("day_plan_activities.*").
+		Joins("JOIN day_plans ON day_plans.id = day_plan_activities.day_plan_id").
+		Where("day_plans.trip_id = ?", tripID).
+		Order("day_plans.day_number, day_plan_activities.time_of_day, day_plan_activities.order_within_time").
+		Find(&dayPlanActivities).Error
+	if err != nil {
+		return nil, err
+	}
+	return dayPlanActivities, nil
+}
+
+func (r *dayPlanRepository) Create(ctx context.Context, dayPlan *models.DayPlan, renumbered []models.DayPlan, userID string) error {
+	return r.editTrip(ctx, dayPlan.TripID, userID, func(tx *gorm.DB) error {
+		if err := saveDayNumbers(tx, renumbered); err != nil {
+			return err
+		}
+		return tx.Create(dayPlan).Error
+	})
+}
+
+func (r *dayPlanRepository) Update(ctx context.Context, dayPlan *models.DayPlan, renumbered []models.DayPlan, userID string) error {
+	return r.editTrip(ctx, dayPlan.TripID, userID, func(tx *gorm.DB) error {
+		if err := tx.Model(&models.DayPlan{}).
+			Where("id = ? AND trip_id = ?", dayPlan.ID, dayPlan.TripID).
+			Updates(map[string]interface{}{
+				"date":       dayPlan.Date,
+				"notes":      dayPlan.Notes,
+				"updated_at": dayPlan.UpdatedAt,
+			}).Error; err != nil {
+			return err
+		}
+		return saveDayNumbers(tx, renumbered)
+	})
+}
+
+func (r *dayPlanRepository) Delete(ctx context.Context, dayPlan *models.DayPlan, renumbered []models.DayPlan, userID string) error {
+	return r.editTrip(ctx, dayPlan.TripID, userID, func(tx *gorm.DB) error {
+		if err := tx.Where("day_plan_id = ?", dayPlan.ID).Delete(&models.DayPlanActivity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("day_plan_id = ?", dayPlan.ID).Delete(&models.DayPlanDestination{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id = ? AND trip_id = ?", dayPlan.ID, dayPlan.TripID).Delete(&models.DayPlan{}).Error; err != nil {
+			return err
+		}
+		return saveDayNumbers(tx, renumbered)
+	})
+}
+
+func (r *dayPlanRepository) CreateActivity(ctx context.Context, tripID string, dpa *models.DayPlanActivity, positions []models.DayPlanActivity, userID string) error {
+	return r.editTrip(ctx, tripID, userID, func(tx *gorm.DB) error {
+		if err := saveActivityPositions(tx, positions); err != nil {
+			return err
+		}
+		return tx.Omit("Activity").Create(dpa).Error
+	})
+}
+
+func (r *dayPlanRepository) UpdateActivity(ctx context.Context, tripID string, dpa *models.DayPlanActivity, userID string) error {
+	return r.editTrip(ctx, tripID, userID, func(tx *gorm.DB) error {
+		return tx.Model(&models.DayPlanActivity{}).
+			Where("id = ?", dpa.ID).
+			Updates(map[string]interface{}{
+				"custom_title": dpa.CustomTitle,
+				"custom_notes": dpa.CustomNotes,
+				"custom_time":  dpa.CustomTime,
+				"completed":    dpa.Completed,
+				"skipped":      dpa.Skipped,
+				"updated_at":   dpa.UpdatedAt,
+			}).Error
+	})
+}
+
+func (r *dayPlanRepository) DeleteActivity(ctx context.Context, tripID string, dpa *models.DayPlanActivity, positions []models.DayPlanActivity, userID string) error {
+	return r.editTrip(ctx, tripID, userID, func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", dpa.ID).Delete(&models.DayPlanActivity{}).Error; err != nil {
+			return err
+		}
+		return saveActivityPositions(tx, positions)
+	})
+}
+
+func (r *dayPlanRepository) MoveActivities(ctx context.Context, tripID string, positions []models.DayPlanActivity, userID string) error {
+	return r.editTrip(ctx, tripID, userID, func(tx *gorm.DB) error {
+		return saveActivityPositions(tx, positions)
+	})
+}
+
+func (r *dayPlanRepository) CreateDestination(ctx context.Context, tripID string, dpd *models.DayPlanDestination, positions []models.DayPlanDestination, userID string) error {
+	return r.editTrip(ctx, tripID, userID, func(tx *gorm.DB) error {
+		if err := saveDestinationPositions(tx, positions); err != nil {
+			return err
+		}
+		return tx.Omit("Destination").Create(dpd).Error
+	})
+}
+
+func (r *dayPlanRepository) UpdateDestination(ctx context.Context, tripID string, dpd *models.DayPlanDestination, positions []models.DayPlanDestination, userID string) error {
+	return r.editTrip(ctx, tripID, userID, func(tx *gorm.DB) error {
+		if err := tx.Model(&models.DayPlanDestination{}).
+			Where("id = ?", dpd.ID).
+			Update("part_of_day", dpd.PartOfDay).Error; err != nil {
+			return err
+		}
+		return saveDestinationPositions(tx, positions)
+	})
+}
+
+func (r *dayPlanRepository) DeleteDestination(ctx context.Context, tripID string, dpd *models.DayPlanDestination, positions []models.DayPlanDestination, userID string) error {
+	return r.editTrip(ctx, tripID, userID, func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", dpd.ID).Delete(&models.DayPlanDestination{}).Error; err != nil {
+			return err
+		}
+		return saveDestinationPositions(tx, positions)
+	})
+}
+
+func (r *dayPlanRepository) ActivityExists(ctx context.Context, activityID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Activity{}).
+		Where("id = ?", activityID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *dayPlanRepository) DestinationExists(ctx context.Context, destinationID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Destination{}).
+		Where("id = ?", destinationID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Helper functions shared by the transactional operations above
+
+// editTrip applies an itinerary change in a transaction. The trip is snapshotted for its
+// revision history before the change, and its version and timestamp are bumped after it.
+func (r *dayPlanRepository) editTrip(ctx context.Context, tripID, userID string, change func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := recordRevision(tx, tripID, userID); err != nil {
+			return err
+		}
+		if err := change(tx); err != nil {
+			return err
+		}
+		return touchTrip(tx, tripID)
+	})
+}
+
+func saveDayNumbers(tx *gorm.DB, dayPlans []models.DayPlan) error {
+	for _, day := range dayPlans {
+		if err := tx.Model(&models.DayPlan{}).
+			Where("id = ?", day.ID).
+			Updates(map[string]interface{}{
+				"day_number": day.DayNumber,
+				"updated_at": day.UpdatedAt,
+			}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveActivityPositions(tx *gorm.DB, positions []models.DayPlanActivity) error {
+	for _, dpa := range positions {
+		if err := tx.Model(&models.DayPlanActivity{}).
+			Where("id = ?", dpa.ID).
+			Updates(map[string]interface{}{
+				"day_plan_id":       dpa.DayPlanID,
+				"time_of_day":       dpa.TimeOfDay,
+				"order_within_time": dpa.OrderWithinTime,
+				"custom_time":       dpa.CustomTime,
+				"updated_at":        dpa.UpdatedAt,
+			}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func saveDestinationPositions(tx *gorm.DB, positions []models.DayPlanDestination) error {
+	for _, dpd := range positions {
+		if err := tx.Model(&models.DayPlanDestination{}).
+			Where("id = ?", dpd.ID).
+			Update("order_index", dpd.OrderIndex).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// touchTrip bumps the trip's version and timestamp after an itinerary change
+func touchTrip(tx *gorm.DB, tripID string) error {
+	return tx.Model(&models.Trip{}).
+		Where("id = ?", tripID).
+		Updates(map[string]interface{}{
+			"updated_at": time.Now(),
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+}
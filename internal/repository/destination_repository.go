@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// DestinationRepository defines the interface for destination catalog operations
+type DestinationRepository interface {
+	FindAll(ctx context.Context) ([]models.Destination, error)
+	FindByIDs(ctx context.Context, ids []string) ([]models.Destination, error)
+	UpdateHeroImage(ctx context.Context, destinationID, heroImage string) error
+}
+
+type destinationRepository struct {
+	db *gorm.DB
+}
+
+// NewDestinationRepository creates a new destination repository instance
+func NewDestinationRepository(db *gorm.DB) DestinationRepository {
+	return &destinationRepository{db: db}
+}
+
+// FindAll returns every destination, most popular first
+func (r *destinationRepository) FindAll(ctx context.Context) ([]models.Destination, error) {
+	var destinations []models.Destination
+	err := r.db.WithContext(ctx).
+		Order("popularity_score DESC, city ASC").
+		Find(&destinations).Error
+	if err != nil {
+		return nil, err
+	}
+	return destinations, nil
+}
+
+func (r *destinationRepository) FindByIDs(ctx context.Context, ids []string) ([]models.Destination, error) {
+	var destinations []models.Destination
+	if len(ids) == 0 {
+		return destinations, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&destinations).Error
+	if err != nil {
+		return nil, err
+	}
+	return destinations, nil
+}
+
+func (r *destinationRepository) UpdateHeroImage(ctx context.Context, destinationID, heroImage string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.Destination{}).
+		Where("id = ?", destinationID).
+		Updates(map[string]interface{}{"hero_image": heroImage, "updated_at": gorm.Expr("NOW()")})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
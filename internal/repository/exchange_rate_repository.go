@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ExchangeRateRepository defines the interface for exchange rate data operations
+type ExchangeRateRepository interface {
+	FindEffective(ctx context.Context, date string) ([]models.ExchangeRate, error)
+	Upsert(ctx context.Context, rates []models.ExchangeRate) error
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository instance
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+// FindEffective returns the latest rate of every currency pair on or before the date.
+// An empty date returns the latest rates overall.
+func (r *exchangeRateRepository) FindEffective(ctx context.Context, date string) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	query := r.db.WithContext(ctx).
+		Select("DISTINCT ON (base_currency, quote_currency) *").
+		Order("base_currency, quote_currency, effective_date DESC")
+	if date != "" {
+		query = query.Where("effective_date <= ?", date)
+	}
+	if err := query.Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// Upsert stores the rates, replacing any already loaded for the same pair and date
+func (r *exchangeRateRepository) Upsert(ctx context.Context, rates []models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "effective_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+		}).
+		Create(&rates).Error
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ImportChanges holds everything a single import writes to the target trip
+type ImportChanges struct {
+	// NewTrip is created first when the import targets a brand new trip
+	NewTrip *models.Trip
+
+	TripID    string
+	UserID    string // who imports, recorded on the revision of an existing trip
+	StartDate string // new trip start date (empty = unchanged)
+	EndDate   string // new trip end date (empty = unchanged)
+
+	// Library records the imported days refer to, created before the days
+	NewDestinations      []models.Destination
+	NewLibraryActivities []models.Activity
+
+	ShiftedDays         []models.DayPlan         // existing days whose day number/date moved
+	NewDays             []models.DayPlan         // new days with nested destinations and activities
+	NewActivities       []models.DayPlanActivity // activities appended to existing days
+	NewTripDestinations []models.TripDestination // destinations the target trip didn't have yet
+	NewLegs             []models.TripLeg         // legs between the target trip's destinations
+	ActivityImports     []models.ActivityImport
+}
+
+// ArchiveChanges holds everything a trip archive import writes
+type ArchiveChanges struct {
+	NewDestinations      []models.Destination
+	NewLibraryActivities []models.Activity
+	Trips                []models.Trip // with TripDestinations and nested DayPlans
+}
+
+// ImportRepository defines the interface for persisting imported trip parts
+type ImportRepository interface {
+	ApplyImport(ctx context.Context, changes *ImportChanges) error
+	ApplyArchive(ctx context.Context, changes *ArchiveChanges) error
+}
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+// NewImportRepository creates a new import repository instance
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) ApplyImport(ctx context.Context, changes *ImportChanges) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if changes.NewTrip != nil {
+			if err := tx.Create(changes.NewTrip).Error; err != nil {
+				return err
+			}
+		} else if err := recordRevision(tx, changes.TripID, changes.UserID); err != nil {
+			return err
+		}
+
+		for i := range changes.NewDestinations {
+			if err := tx.Create(&changes.NewDestinations[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range changes.NewLibraryActivities {
+			if err := tx.Create(&changes.NewLibraryActivities[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		// Move existing days out of the way before inserting new ones
+		for _, day := range changes.ShiftedDays {
+			if err := tx.Model(&models.DayPlan{}).
+				Where("id = ? AND trip_id = ?", day.ID, changes.TripID).
+				Updates(map[string]interface{}{
+					"day_number": day.DayNumber,
+					"date":       day.Date,
+					"updated_at": day.UpdatedAt,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range changes.NewDays {
+			changes.NewDays[i].TripID = changes.TripID
+			if err := tx.Create(&changes.NewDays[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range changes.NewActivities {
+			if err := tx.Create(&changes.NewActivities[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range changes.NewTripDestinations {
+			changes.NewTripDestinations[i].TripID = changes.TripID
+			if err := tx.Create(&changes.NewTripDestinations[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range changes.NewLegs {
+			changes.NewLegs[i].TripID = changes.TripID
+			if err := tx.Omit(clause.Associations).Create(&changes.NewLegs[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range changes.ActivityImports {
+			if err := tx.Create(&changes.ActivityImports[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		// Touch the trip and extend its date range if needed
+		updates := map[string]interface{}{
+			"updated_at": time.Now(),
+			"version":    gorm.Expr("version + 1"),
+		}
+		if changes.StartDate != "" {
+			updates["start_date"] = changes.StartDate
+		}
+		if changes.EndDate != "" {
+			updates["end_date"] = changes.EndDate
+		}
+		return tx.Model(&models.Trip{}).
+			Where("id = ?", changes.TripID).
+			Updates(updates).Error
+	})
+}
+
+// ApplyArchive creates the archive's library entries and trips in a single transaction
+func (r *importRepository) ApplyArchive(ctx context.Context, changes *ArchiveChanges) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range changes.NewDestinations {
+			if err := tx.Create(&changes.NewDestinations[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range changes.NewLibraryActivities {
+			if err := tx.Create(&changes.NewLibraryActivities[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range changes.Trips {
+			trip := &changes.Trips[i]
+			if err := tx.Omit(clause.Associations).Create(trip).Error; err != nil {
+				return err
+			}
+
+			for j := range trip.TripDestinations {
+				if err := tx.Omit(clause.Associations).Create(&trip.TripDestinations[j]).Error; err != nil {
+					return err
+				}
+			}
+
+			// Days are created together with their destinations and activities
+			for j := range trip.DayPlans {
+				if err := tx.Create(&trip.DayPlans[j]).Error; err != nil {
+					return err
+				}
+			}
+
+			for j := range trip.TripLegs {
+				if err := tx.Omit(clause.Associations).Create(&trip.TripLegs[j]).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
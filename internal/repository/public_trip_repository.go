@@ -215,6 +215,9 @@ func (r *publicTripRepository) FindByID(ctx context.Context, id string) (*models
 			return db.Order("day_plan_activities.time_of_day, day_plan_activities.order_within_time ASC")
 		}).
 		Preload("DayPlans.DayPlanActivities.Activity").
+		Preload("TripLegs", orderedLegs).
+		Preload("TripLegs.FromDestination").
+		Preload("TripLegs.ToDestination").
 		Where("id = ? AND visibility = ?", id, "public").
 		First(&trip).Error
 	if err != nil {
@@ -243,6 +246,9 @@ func (r *publicTripRepository) FindBySlug(ctx context.Context, slug string) (*mo
 			return db.Order("day_plan_activities.time_of_day, day_plan_activities.order_within_time ASC")
 		}).
 		Preload("DayPlans.DayPlanActivities.Activity").
+		Preload("TripLegs", orderedLegs).
+		Preload("TripLegs.FromDestination").
+		Preload("TripLegs.ToDestination").
 		Where("slug = ? AND visibility = ?", slug, "public").
 		First(&trip).Error
 	if err != nil {
@@ -256,6 +262,7 @@ func (r *publicTripRepository) ToggleVisibility(ctx context.Context, tripID stri
 	updates := map[string]interface{}{
 		"visibility": visibility,
 		"updated_at": now,
+		"version":    gorm.Expr("version + 1"),
 	}
 
 	return r.db.WithContext(ctx).
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TripAttachmentRepository defines the interface for trip attachment data operations
+type TripAttachmentRepository interface {
+	FindByTripID(ctx context.Context, tripID string, dayPlanActivityID *string) ([]models.TripAttachment, error)
+	FindByID(ctx context.Context, tripID, attachmentID string) (*models.TripAttachment, error)
+	Create(ctx context.Context, attachment *models.TripAttachment) error
+	Delete(ctx context.Context, tripID, attachmentID string) error
+}
+
+type tripAttachmentRepository struct {
+	db *gorm.DB
+}
+
+// NewTripAttachmentRepository creates a new trip attachment repository instance
+func NewTripAttachmentRepository(db *gorm.DB) TripAttachmentRepository {
+	return &tripAttachmentRepository{db: db}
+}
+
+// FindByTripID lists the trip's attachments, oldest first, optionally only those of one activity
+func (r *tripAttachmentRepository) FindByTripID(ctx context.Context, tripID string, dayPlanActivityID *string) ([]models.TripAttachment, error) {
+	query := r.db.WithContext(ctx).Where("trip_id = ?", tripID)
+	if dayPlanActivityID != nil {
+		query = query.Where("day_plan_activity_id = ?", *dayPlanActivityID)
+	}
+
+	var attachments []models.TripAttachment
+	if err := query.Order("created_at ASC").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *tripAttachmentRepository) FindByID(ctx context.Context, tripID, attachmentID string) (*models.TripAttachment, error) {
+	var attachment models.TripAttachment
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, attachmentID).
+		First(&attachment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+func (r *tripAttachmentRepository) Create(ctx context.Context, attachment *models.TripAttachment) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(attachment).Error
+}
+
+func (r *tripAttachmentRepository) Delete(ctx context.Context, tripID, attachmentID string) error {
+	result := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, attachmentID).
+		Delete(&models.TripAttachment{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TripExpenseRepository defines the interface for trip expense ledger data operations
+type TripExpenseRepository interface {
+	FindByTripID(ctx context.Context, tripID string) ([]models.TripExpense, error)
+	FindByID(ctx context.Context, tripID, expenseID string) (*models.TripExpense, error)
+	Create(ctx context.Context, expense *models.TripExpense) error
+	Update(ctx context.Context, expense *models.TripExpense) error
+	Delete(ctx context.Context, tripID, expenseID string) error
+}
+
+type tripExpenseRepository struct {
+	db *gorm.DB
+}
+
+// NewTripExpenseRepository creates a new trip expense repository instance
+func NewTripExpenseRepository(db *gorm.DB) TripExpenseRepository {
+	return &tripExpenseRepository{db: db}
+}
+
+// FindByTripID lists the trip's expenses, oldest spending date first (undated entries last)
+func (r *tripExpenseRepository) FindByTripID(ctx context.Context, tripID string) ([]models.TripExpense, error) {
+	var expenses []models.TripExpense
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		Order("spent_on ASC NULLS LAST, created_at ASC").
+		Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+	return expenses, nil
+}
+
+func (r *tripExpenseRepository) FindByID(ctx context.Context, tripID, expenseID string) (*models.TripExpense, error) {
+	var expense models.TripExpense
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, expenseID).
+		First(&expense).Error
+	if err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+func (r *tripExpenseRepository) Create(ctx context.Context, expense *models.TripExpense) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(expense).Error
+}
+
+func (r *tripExpenseRepository) Update(ctx context.Context, expense *models.TripExpense) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(expense).Error
+}
+
+func (r *tripExpenseRepository) Delete(ctx context.Context, tripID, expenseID string) error {
+	result := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, expenseID).
+		Delete(&models.TripExpense{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
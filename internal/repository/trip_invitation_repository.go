@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// TripInvitationRepository defines the interface for trip invitation data operations
+type TripInvitationRepository interface {
+	Create(ctx context.Context, invitation *models.TripInvitation) error
+	FindByID(ctx context.Context, tripID, invitationID string) (*models.TripInvitation, error)
+	FindByToken(ctx context.Context, token string) (*models.TripInvitation, error)
+	FindByTripID(ctx context.Context, tripID string) ([]models.TripInvitation, error)
+	FindPendingByEmail(ctx context.Context, email string) ([]models.TripInvitation, error)
+	Update(ctx context.Context, invitation *models.TripInvitation) error
+	Accept(ctx context.Context, invitation *models.TripInvitation, member *models.TripMember) error
+}
+
+type tripInvitationRepository struct {
+	db *gorm.DB
+}
+
+// NewTripInvitationRepository creates a new trip invitation repository instance
+func NewTripInvitationRepository(db *gorm.DB) TripInvitationRepository {
+	return &tripInvitationRepository{db: db}
+}
+
+func (r *tripInvitationRepository) Create(ctx context.Context, invitation *models.TripInvitation) error {
+	return r.db.WithContext(ctx).Omit("Trip").Create(invitation).Error
+}
+
+func (r *tripInvitationRepository) FindByID(ctx context.Context, tripID, invitationID string) (*models.TripInvitation, error) {
+	var invitation models.TripInvitation
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND trip_id = ?", invitationID, tripID).
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindByToken looks up an invitation together with its trip (for showing what is being joined)
+func (r *tripInvitationRepository) FindByToken(ctx context.Context, token string) (*models.TripInvitation, error) {
+	var invitation models.TripInvitation
+	err := r.db.WithContext(ctx).
+		Where("token = ?", token).
+		Preload("Trip").
+		First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// FindByTripID lists every invitation for the trip, newest first
+func (r *tripInvitationRepository) FindByTripID(ctx context.Context, tripID string) ([]models.TripInvitation, error) {
+	var invitations []models.TripInvitation
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// FindPendingByEmail lists unexpired pending invitations addressed to the email, with their trips
+func (r *tripInvitationRepository) FindPendingByEmail(ctx context.Context, email string) ([]models.TripInvitation, error) {
+	var invitations []models.TripInvitation
+	err := r.db.WithContext(ctx).
+		Where("email = ? AND status = ? AND expires_at > ?", email, models.InvitationStatusPending, time.Now()).
+		Preload("Trip").
+		Order("created_at DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *tripInvitationRepository) Update(ctx context.Context, invitation *models.TripInvitation) error {
+	return r.db.WithContext(ctx).Omit("Trip").Save(invitation).Error
+}
+
+// Accept adds (or updates) the membership and records the invitation's new state in one transaction
+func (r *tripInvitationRepository) Accept(ctx context.Context, invitation *models.TripInvitation, member *models.TripMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := upsertMember(tx, member); err != nil {
+			return err
+		}
+		return tx.Omit("Trip").Save(invitation).Error
+	})
+}
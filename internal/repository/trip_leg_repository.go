@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TripLegRepository defines the interface for trip leg data operations
+type TripLegRepository interface {
+	FindByTripID(ctx context.Context, tripID string) ([]models.TripLeg, error)
+	FindByID(ctx context.Context, tripID, legID string) (*models.TripLeg, error)
+	Create(ctx context.Context, leg *models.TripLeg) error
+	Update(ctx context.Context, leg *models.TripLeg) error
+	Delete(ctx context.Context, tripID, legID string) error
+}
+
+type tripLegRepository struct {
+	db *gorm.DB
+}
+
+// NewTripLegRepository creates a new trip leg repository instance
+func NewTripLegRepository(db *gorm.DB) TripLegRepository {
+	return &tripLegRepository{db: db}
+}
+
+// FindByTripID lists the trip's legs in departure order; legs without a departure time come last
+func (r *tripLegRepository) FindByTripID(ctx context.Context, tripID string) ([]models.TripLeg, error) {
+	var legs []models.TripLeg
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		Preload("FromDestination").
+		Preload("ToDestination").
+		Scopes(orderedLegs).
+		Find(&legs).Error
+	if err != nil {
+		return nil, err
+	}
+	return legs, nil
+}
+
+func (r *tripLegRepository) FindByID(ctx context.Context, tripID, legID string) (*models.TripLeg, error) {
+	var leg models.TripLeg
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, legID).
+		Preload("FromDestination").
+		Preload("ToDestination").
+		First(&leg).Error
+	if err != nil {
+		return nil, err
+	}
+	return &leg, nil
+}
+
+func (r *tripLegRepository) Create(ctx context.Context, leg *models.TripLeg) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(leg).Error
+}
+
+func (r *tripLegRepository) Update(ctx context.Context, leg *models.TripLeg) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(leg).Error
+}
+
+func (r *tripLegRepository) Delete(ctx context.Context, tripID, legID string) error {
+	result := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, legID).
+		Delete(&models.TripLeg{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// orderedLegs sorts legs by departure (Postgres puts missing times last), then creation
+func orderedLegs(db *gorm.DB) *gorm.DB {
+	return db.Order("trip_legs.departure_time ASC, trip_legs.created_at ASC")
+}
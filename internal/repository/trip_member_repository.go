@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TripMemberRepository defines the interface for trip membership data operations
+type TripMemberRepository interface {
+	FindByTripID(ctx context.Context, tripID string) ([]models.TripMember, error)
+	FindByTripAndUser(ctx context.Context, tripID, userID string) (*models.TripMember, error)
+	UpdateRole(ctx context.Context, tripID, userID, role string) error
+	Delete(ctx context.Context, tripID, userID string) error
+}
+
+type tripMemberRepository struct {
+	db *gorm.DB
+}
+
+// NewTripMemberRepository creates a new trip member repository instance
+func NewTripMemberRepository(db *gorm.DB) TripMemberRepository {
+	return &tripMemberRepository{db: db}
+}
+
+// FindByTripID lists the trip's members with their user profiles, oldest first
+func (r *tripMemberRepository) FindByTripID(ctx context.Context, tripID string) ([]models.TripMember, error) {
+	var members []models.TripMember
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		Preload("User").
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *tripMemberRepository) FindByTripAndUser(ctx context.Context, tripID, userID string) (*models.TripMember, error) {
+	var member models.TripMember
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND user_id = ?", tripID, userID).
+		Preload("User").
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *tripMemberRepository) UpdateRole(ctx context.Context, tripID, userID, role string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.TripMember{}).
+		Where("trip_id = ? AND user_id = ?", tripID, userID).
+		Updates(map[string]interface{}{"role": role, "updated_at": gorm.Expr("NOW()")})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *tripMemberRepository) Delete(ctx context.Context, tripID, userID string) error {
+	result := r.db.WithContext(ctx).
+		Where("trip_id = ? AND user_id = ?", tripID, userID).
+		Delete(&models.TripMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// upsertMember adds the member, or updates the role of an existing membership
+func upsertMember(tx *gorm.DB, member *models.TripMember) error {
+	return tx.Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "trip_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"role", "invited_by_user_id", "updated_at"}),
+		}).
+		Create(member).Error
+}
@@ -2,23 +2,41 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 	"triply-server/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrVersionConflict is returned when an update's expected version doesn't match the stored trip
+var ErrVersionConflict = errors.New("trip version conflict")
+
 // TripRepository defines the interface for trip data operations
 type TripRepository interface {
 	FindByUserID(ctx context.Context, userID string) ([]models.Trip, error)
+	FindOwnedByUserID(ctx context.Context, userID string) ([]models.Trip, error)
 	FindByShadowUserID(ctx context.Context, shadowUserID string) ([]models.Trip, error)
 	FindByID(ctx context.Context, tripID, userID string) (*models.Trip, error)
+	FindBasicByID(ctx context.Context, tripID, userID string) (*models.Trip, error)
 	FindByIDWithShadowUser(ctx context.Context, tripID, shadowUserID string) (*models.Trip, error)
+	FindRole(ctx context.Context, tripID, userID string) (string, error)
+	FindDestinations(ctx context.Context, tripID string) ([]models.TripDestination, error)
 	Create(ctx context.Context, trip *models.Trip) error
-	Update(ctx context.Context, trip *models.Trip) error
+	Update(ctx context.Context, trip *models.Trip, userID string) error
+	UpdateCoverImage(ctx context.Context, tripID, userID, coverImage string) (string, error)
+	CoverImageInUse(ctx context.Context, coverImage string) (bool, error)
 	Delete(ctx context.Context, tripID, userID string) error
 	MigrateShadowTrips(ctx context.Context, shadowUserID, userID string) error
 }
 
+// accessibleBy matches trips the user owns or has been added to as a member
+const accessibleBy = "(trips.user_id = ? OR EXISTS (SELECT 1 FROM trip_members tm WHERE tm.trip_id = trips.id AND tm.user_id = ?))"
+
+// editableBy matches trips the user owns or can edit as a member
+const editableBy = "(trips.user_id = ? OR EXISTS (SELECT 1 FROM trip_members tm WHERE tm.trip_id = trips.id AND tm.user_id = ? AND tm.role = 'editor'))"
+
 type tripRepository struct {
 	db *gorm.DB
 }
@@ -28,10 +46,21 @@ func NewTripRepository(db *gorm.DB) TripRepository {
 	return &tripRepository{db: db}
 }
 
+// FindByUserID lists the trips the user owns together with the trips shared with them
 func (r *tripRepository) FindByUserID(ctx context.Context, userID string) ([]models.Trip, error) {
+	return r.findTrips(ctx, accessibleBy, userID, userID)
+}
+
+// FindOwnedByUserID lists only the trips the user owns
+func (r *tripRepository) FindOwnedByUserID(ctx context.Context, userID string) ([]models.Trip, error) {
+	return r.findTrips(ctx, "trips.user_id = ?", userID)
+}
+
+// findTrips lists the trips matching the condition with their itineraries, most recently updated first
+func (r *tripRepository) findTrips(ctx context.Context, query string, args ...interface{}) ([]models.Trip, error) {
 	var trips []models.Trip
 	err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+		Where(query, args...).
 		Preload("TripDestinations", func(db *gorm.DB) *gorm.DB {
 			return db.Order("trip_destinations.order_index ASC")
 		}).
@@ -47,6 +76,10 @@ func (r *tripRepository) FindByUserID(ctx context.Context, userID string) ([]mod
 			return db.Order("day_plan_activities.time_of_day, day_plan_activities.order_within_time ASC")
 		}).
 		Preload("DayPlans.DayPlanActivities.Activity").
+		Preload("TripLegs", orderedLegs).
+		Preload("TripLegs.FromDestination").
+		Preload("TripLegs.ToDestination").
+		Preload("Reservations", orderedReservations).
 		Order("trips.updated_at DESC").
 		Find(&trips).Error
 	if err != nil {
@@ -60,10 +93,12 @@ func (r *tripRepository) FindByShadowUserID(ctx context.Context, shadowUserID st
 	return r.FindByUserID(ctx, shadowUserID)
 }
 
+// FindByID loads the trip with its itinerary if the user owns it or is a member
 func (r *tripRepository) FindByID(ctx context.Context, tripID, userID string) (*models.Trip, error) {
 	var trip models.Trip
 	err := r.db.WithContext(ctx).
-		Where("id = ? AND user_id = ?", tripID, userID).
+		Where("trips.id = ?", tripID).
+		Where(accessibleBy, userID, userID).
 		Preload("TripDestinations", func(db *gorm.DB) *gorm.DB {
 			return db.Order("trip_destinations.order_index ASC")
 		}).
@@ -79,6 +114,23 @@ func (r *tripRepository) FindByID(ctx context.Context, tripID, userID string) (*
 			return db.Order("day_plan_activities.time_of_day, day_plan_activities.order_within_time ASC")
 		}).
 		Preload("DayPlans.DayPlanActivities.Activity").
+		Preload("TripLegs", orderedLegs).
+		Preload("TripLegs.FromDestination").
+		Preload("TripLegs.ToDestination").
+		Preload("Reservations", orderedReservations).
+		First(&trip).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trip, nil
+}
+
+// FindBasicByID loads only the trip row (no itinerary), scoped to its owner and members
+func (r *tripRepository) FindBasicByID(ctx context.Context, tripID, userID string) (*models.Trip, error) {
+	var trip models.Trip
+	err := r.db.WithContext(ctx).
+		Where("trips.id = ?", tripID).
+		Where(accessibleBy, userID, userID).
 		First(&trip).Error
 	if err != nil {
 		return nil, err
@@ -90,6 +142,40 @@ func (r *tripRepository) FindByIDWithShadowUser(ctx context.Context, tripID, sha
 	return r.FindByID(ctx, tripID, shadowUserID)
 }
 
+// FindRole returns the user's role on the trip: owner for the trip's creator, otherwise
+// their membership role. gorm.ErrRecordNotFound means the user has no access at all.
+func (r *tripRepository) FindRole(ctx context.Context, tripID, userID string) (string, error) {
+	var trip models.Trip
+	if err := r.db.WithContext(ctx).Select("id", "user_id").Where("id = ?", tripID).First(&trip).Error; err != nil {
+		return "", err
+	}
+	if trip.UserID == userID {
+		return models.TripRoleOwner, nil
+	}
+
+	var member models.TripMember
+	if err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND user_id = ?", tripID, userID).
+		First(&member).Error; err != nil {
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// FindDestinations lists the trip's destinations in order, with their destination details
+func (r *tripRepository) FindDestinations(ctx context.Context, tripID string) ([]models.TripDestination, error) {
+	var tripDestinations []models.TripDestination
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		Order("order_index ASC").
+		Preload("Destination").
+		Find(&tripDestinations).Error
+	if err != nil {
+		return nil, err
+	}
+	return tripDestinations, nil
+}
+
 func (r *tripRepository) Create(ctx context.Context, trip *models.Trip) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Create the trip
@@ -111,11 +197,36 @@ func (r *tripRepository) Create(ctx context.Context, trip *models.Trip) error {
 	})
 }
 
-func (r *tripRepository) Update(ctx context.Context, trip *models.Trip) error {
+// Update replaces the trip and its itinerary on behalf of userID (the owner or an editor),
+// recording the previous state as a revision. When trip.Version is set it is treated as
+// the expected stored version, and ErrVersionConflict is returned if the trip has moved on.
+// The trip keeps its owner whoever makes the change.
+func (r *tripRepository) Update(ctx context.Context, trip *models.Trip, userID string) error {
 	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Update trip basic info
+		// Lock the row so concurrent writers are serialized on the version check
+		var current models.Trip
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "user_id", "version").
+			Where("trips.id = ?", trip.ID).
+			Where(editableBy, userID, userID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if trip.Version != 0 && trip.Version != current.Version {
+			return ErrVersionConflict
+		}
+		trip.Version = current.Version + 1
+		trip.UserID = current.UserID
+
+		// Keep the itinerary we're about to replace so it can be restored later
+		if err := recordRevision(tx, trip.ID, userID); err != nil {
+			return err
+		}
+
+		// Update trip basic info (legs and reservations are edited through their own endpoints)
 		if err := tx.Model(&models.Trip{}).
-			Where("id = ? AND user_id = ?", trip.ID, trip.UserID).
+			Where("id = ?", trip.ID).
+			Omit("TripLegs", "Reservations").
 			Updates(trip).Error; err != nil {
 			return err
 		}
@@ -154,6 +265,47 @@ func (r *tripRepository) Update(ctx context.Context, trip *models.Trip) error {
 	})
 }
 
+// UpdateCoverImage replaces the trip's cover image on behalf of userID, recording the previous
+// state as a revision and bumping its version like any other edit. It returns the replaced image.
+func (r *tripRepository) UpdateCoverImage(ctx context.Context, tripID, userID, coverImage string) (string, error) {
+	var current models.Trip
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "cover_image").
+			Where("trips.id = ?", tripID).
+			Where(editableBy, userID, userID).
+			First(&current).Error; err != nil {
+			return err
+		}
+		if err := recordRevision(tx, tripID, userID); err != nil {
+			return err
+		}
+		return tx.Model(&models.Trip{}).
+			Where("id = ?", tripID).
+			Updates(map[string]interface{}{
+				"cover_image": coverImage,
+				"updated_at":  time.Now(),
+				"version":     gorm.Expr("version + 1"),
+			}).Error
+	})
+	if err != nil {
+		return "", err
+	}
+	return current.CoverImage, nil
+}
+
+// CoverImageInUse reports whether any trip still shows the cover image, as clones and
+// imports copy their source's cover
+func (r *tripRepository) CoverImageInUse(ctx context.Context, coverImage string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Trip{}).
+		Where("cover_image = ?", coverImage).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Delete removes the trip; only its owner can delete it
 func (r *tripRepository) Delete(ctx context.Context, tripID, userID string) error {
 	return r.db.WithContext(ctx).
 		Where("id = ? AND user_id = ?", tripID, userID).
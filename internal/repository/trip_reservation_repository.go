@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TripReservationRepository defines the interface for trip reservation data operations
+type TripReservationRepository interface {
+	FindByTripID(ctx context.Context, tripID string) ([]models.TripReservation, error)
+	FindByID(ctx context.Context, tripID, reservationID string) (*models.TripReservation, error)
+	Create(ctx context.Context, reservation *models.TripReservation) error
+	Update(ctx context.Context, reservation *models.TripReservation) error
+	Delete(ctx context.Context, tripID, reservationID string) error
+}
+
+type tripReservationRepository struct {
+	db *gorm.DB
+}
+
+// NewTripReservationRepository creates a new trip reservation repository instance
+func NewTripReservationRepository(db *gorm.DB) TripReservationRepository {
+	return &tripReservationRepository{db: db}
+}
+
+// FindByTripID lists the trip's reservations in start order; reservations without a start time come last
+func (r *tripReservationRepository) FindByTripID(ctx context.Context, tripID string) ([]models.TripReservation, error) {
+	var reservations []models.TripReservation
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ?", tripID).
+		Scopes(orderedReservations).
+		Find(&reservations).Error
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
+
+func (r *tripReservationRepository) FindByID(ctx context.Context, tripID, reservationID string) (*models.TripReservation, error) {
+	var reservation models.TripReservation
+	err := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, reservationID).
+		First(&reservation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *tripReservationRepository) Create(ctx context.Context, reservation *models.TripReservation) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(reservation).Error
+}
+
+func (r *tripReservationRepository) Update(ctx context.Context, reservation *models.TripReservation) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(reservation).Error
+}
+
+func (r *tripReservationRepository) Delete(ctx context.Context, tripID, reservationID string) error {
+	result := r.db.WithContext(ctx).
+		Where("trip_id = ? AND id = ?", tripID, reservationID).
+		Delete(&models.TripReservation{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// orderedReservations sorts reservations by start (Postgres puts missing times last), then creation
+func orderedReservations(db *gorm.DB) *gorm.DB {
+	return db.Order("trip_reservations.start_time ASC, trip_reservations.created_at ASC")
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"triply-server/internal/models"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// maxRevisionsPerTrip bounds how many revisions are kept for a single trip
+const maxRevisionsPerTrip = 50
+
+// TripRevisionRepository defines the interface for trip revision data operations
+type TripRevisionRepository interface {
+	FindByTripID(ctx context.Context, tripID string) ([]models.TripRevision, error)
+	FindByID(ctx context.Context, tripID, revisionID string) (*models.TripRevision, error)
+}
+
+type tripRevisionRepository struct {
+	db *gorm.DB
+}
+
+// NewTripRevisionRepository creates a new trip revision repository instance
+func NewTripRevisionRepository(db *gorm.DB) TripRevisionRepository {
+	return &tripRevisionRepository{db: db}
+}
+
+// FindByTripID lists a trip's revisions, newest first, without their snapshots
+func (r *tripRevisionRepository) FindByTripID(ctx context.Context, tripID string) ([]models.TripRevision, error) {
+	var revisions []models.TripRevision
+	err := r.db.WithContext(ctx).
+		Omit("snapshot").
+		Where("trip_id = ?", tripID).
+		Order("created_at DESC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+func (r *tripRevisionRepository) FindByID(ctx context.Context, tripID, revisionID string) (*models.TripRevision, error) {
+	var revision models.TripRevision
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND trip_id = ?", revisionID, tripID).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// recordRevision snapshots the stored trip tree inside tx before it gets replaced,
+// then prunes the oldest revisions beyond maxRevisionsPerTrip
+func recordRevision(tx *gorm.DB, tripID, userID string) error {
+	var trip models.Trip
+	err := tx.
+		Where("id = ?", tripID).
+		Preload("TripDestinations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("trip_destinations.order_index ASC")
+		}).
+		Preload("DayPlans", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_plans.day_number ASC")
+		}).
+		Preload("DayPlans.DayPlanDestinations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_plan_destinations.order_index ASC")
+		}).
+		Preload("DayPlans.DayPlanActivities", func(db *gorm.DB) *gorm.DB {
+			return db.Order("day_plan_activities.time_of_day, day_plan_activities.order_within_time ASC")
+		}).
+		Preload("DayPlans.DayPlanActivities.Activity").
+		First(&trip).Error
+	if err != nil {
+		return err
+	}
+
+	tripDestinations := trip.TripDestinations
+	trip.TripDestinations = nil
+
+	revision := models.TripRevision{
+		ID:              utils.GenerateID("rev"),
+		TripID:          tripID,
+		Version:         trip.Version,
+		Snapshot:        models.TripSnapshot{Trip: trip, TripDestinations: tripDestinations},
+		CreatedByUserID: userID,
+		CreatedAt:       time.Now(),
+	}
+	if err := tx.Omit("Trip").Create(&revision).Error; err != nil {
+		return err
+	}
+
+	return tx.
+		Where("trip_id = ? AND id NOT IN (?)", tripID,
+			tx.Model(&models.TripRevision{}).
+				Select("id").
+				Where("trip_id = ?", tripID).
+				Order("created_at DESC").
+				Limit(maxRevisionsPerTrip)).
+		Delete(&models.TripRevision{}).Error
+}
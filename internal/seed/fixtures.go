@@ -0,0 +1,197 @@
+package seed
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FixtureSet is the declarative content of a fixture directory. Every YAML/JSON file in
+// the directory may contain any of the sections; files are merged in name order.
+type FixtureSet struct {
+	Users        []UserFixture        `yaml:"users" json:"users"`
+	Destinations []DestinationFixture `yaml:"destinations" json:"destinations"`
+	Activities   []ActivityFixture    `yaml:"activities" json:"activities"`
+	Trips        []TripFixture        `yaml:"trips" json:"trips"`
+}
+
+// UserFixture is a user account
+type UserFixture struct {
+	ID          string  `yaml:"id" json:"id"`
+	Name        string  `yaml:"name" json:"name"`
+	Email       string  `yaml:"email" json:"email"`
+	DisplayName *string `yaml:"displayName" json:"displayName"`
+	AvatarURL   *string `yaml:"avatarUrl" json:"avatarUrl"`
+	Locale      string  `yaml:"locale" json:"locale"` // defaults to "en"
+}
+
+// DestinationFixture is a library destination
+type DestinationFixture struct {
+	ID          string   `yaml:"id" json:"id"`
+	City        string   `yaml:"city" json:"city"`
+	Region      *string  `yaml:"region" json:"region"`
+	Country     string   `yaml:"country" json:"country"`
+	Latitude    *float64 `yaml:"latitude" json:"latitude"`
+	Longitude   *float64 `yaml:"longitude" json:"longitude"`
+	Timezone    *string  `yaml:"timezone" json:"timezone"`
+	HeroImage   *string  `yaml:"heroImage" json:"heroImage"`
+	Images      []string `yaml:"images" json:"images"`
+	Description *string  `yaml:"description" json:"description"`
+	PlaceID     *string  `yaml:"placeId" json:"placeId"`
+}
+
+// ActivityFixture is a library activity
+type ActivityFixture struct {
+	ID                    string   `yaml:"id" json:"id"`
+	Title                 string   `yaml:"title" json:"title"`
+	Description           *string  `yaml:"description" json:"description"`
+	Type                  string   `yaml:"type" json:"type"`
+	Location              *string  `yaml:"location" json:"location"`
+	Address               *string  `yaml:"address" json:"address"`
+	Latitude              *float64 `yaml:"latitude" json:"latitude"`
+	Longitude             *float64 `yaml:"longitude" json:"longitude"`
+	PlaceID               *string  `yaml:"placeId" json:"placeId"`
+	DurationMinutes       *int     `yaml:"durationMinutes" json:"durationMinutes"`
+	EstimatedCostAmount   *int     `yaml:"estimatedCostAmount" json:"estimatedCostAmount"`
+	EstimatedCostCurrency *string  `yaml:"estimatedCostCurrency" json:"estimatedCostCurrency"`
+	ImageURL              *string  `yaml:"imageUrl" json:"imageUrl"`
+	Images                []string `yaml:"images" json:"images"`
+	URL                   *string  `yaml:"url" json:"url"`
+	CreatedByUserID       *string  `yaml:"createdByUserId" json:"createdByUserId"`
+	IsVerified            bool     `yaml:"isVerified" json:"isVerified"`
+}
+
+// TripFixture is a trip with its destinations and itinerary
+type TripFixture struct {
+	ID            string  `yaml:"id" json:"id"`
+	UserID        string  `yaml:"userId" json:"userId"`
+	Name          string  `yaml:"name" json:"name"`
+	Slug          *string `yaml:"slug" json:"slug"`
+	StartDate     string  `yaml:"startDate" json:"startDate"`
+	EndDate       string  `yaml:"endDate" json:"endDate"`
+	CoverImage    string  `yaml:"coverImage" json:"coverImage"`
+	TravelerCount int     `yaml:"travelerCount" json:"travelerCount"` // defaults to 1
+	Adults        int     `yaml:"adults" json:"adults"`               // defaults to 2
+	ChildrenAges  string  `yaml:"childrenAges" json:"childrenAges"`
+	Visibility    string  `yaml:"visibility" json:"visibility"` // defaults to "private"
+	Status        string  `yaml:"status" json:"status"`         // defaults to "active"
+	Summary       *string `yaml:"summary" json:"summary"`
+	TravelerType  string  `yaml:"travelerType" json:"travelerType"`
+	Likes         int     `yaml:"likes" json:"likes"`
+	CloneCount    int     `yaml:"cloneCount" json:"cloneCount"`
+
+	// CreatedDaysAgo backdates created_at when the trip is first inserted (for "newest" sorting)
+	CreatedDaysAgo int `yaml:"createdDaysAgo" json:"createdDaysAgo"`
+	// Copies > 1 seeds that many copies with IDs, slugs and names suffixed by the copy number (load tests)
+	Copies int `yaml:"copies" json:"copies"`
+
+	Destinations []TripDestinationFixture `yaml:"destinations" json:"destinations"`
+	Days         []DayFixture             `yaml:"days" json:"days"`
+}
+
+// TripDestinationFixture links a trip to a destination; order follows list position
+type TripDestinationFixture struct {
+	DestinationID string  `yaml:"destinationId" json:"destinationId"`
+	StartDate     *string `yaml:"startDate" json:"startDate"`
+	EndDate       *string `yaml:"endDate" json:"endDate"`
+	CustomNotes   *string `yaml:"customNotes" json:"customNotes"`
+}
+
+// DayFixture is one day of a trip. DayNumber defaults to list position and
+// Date to the trip start date plus DayNumber - 1.
+type DayFixture struct {
+	DayNumber    int                     `yaml:"dayNumber" json:"dayNumber"`
+	Date         string                  `yaml:"date" json:"date"`
+	Notes        *string                 `yaml:"notes" json:"notes"`
+	Destinations []DayDestinationFixture `yaml:"destinations" json:"destinations"`
+	Activities   []DayActivityFixture    `yaml:"activities" json:"activities"`
+}
+
+// DayDestinationFixture places a destination on a day; order follows list position
+type DayDestinationFixture struct {
+	DestinationID string  `yaml:"destinationId" json:"destinationId"`
+	PartOfDay     *string `yaml:"partOfDay" json:"partOfDay"`
+}
+
+// DayActivityFixture schedules an activity; order within its time of day follows list position
+type DayActivityFixture struct {
+	ActivityID  string     `yaml:"activityId" json:"activityId"`
+	TimeOfDay   string     `yaml:"timeOfDay" json:"timeOfDay"` // start, mid, end; defaults to start
+	CustomTitle *string    `yaml:"customTitle" json:"customTitle"`
+	CustomNotes *string    `yaml:"customNotes" json:"customNotes"`
+	CustomTime  *time.Time `yaml:"customTime" json:"customTime"`
+	Completed   bool       `yaml:"completed" json:"completed"`
+	Skipped     bool       `yaml:"skipped" json:"skipped"`
+}
+
+// LoadSet reads every .yaml, .yml and .json file in dir/name
+func LoadSet(dir, name string) (*FixtureSet, error) {
+	setDir := filepath.Join(dir, name)
+	entries, err := os.ReadDir(setDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("fixture set %q not found in %s", name, dir)
+		}
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".yaml", ".yml", ".json":
+			files = append(files, filepath.Join(setDir, entry.Name()))
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("fixture set %q has no .yaml or .json files", name)
+	}
+	sort.Strings(files)
+
+	set := &FixtureSet{}
+	for _, file := range files {
+		part, err := loadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		set.Users = append(set.Users, part.Users...)
+		set.Destinations = append(set.Destinations, part.Destinations...)
+		set.Activities = append(set.Activities, part.Activities...)
+		set.Trips = append(set.Trips, part.Trips...)
+	}
+	return set, nil
+}
+
+// loadFile decodes a single fixture file, rejecting unknown fields so typos surface early
+func loadFile(file string) (*FixtureSet, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var part FixtureSet
+	if strings.EqualFold(filepath.Ext(file), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&part)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&part)
+	}
+	if err != nil && !errors.Is(err, io.EOF) { // empty files are allowed
+		return nil, err
+	}
+	return &part, nil
+}
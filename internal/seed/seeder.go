@@ -0,0 +1,376 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"triply-server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Result counts the rows a seed run inserted or updated
+type Result struct {
+	Users        int
+	Destinations int
+	Activities   int
+	Trips        int
+	Days         int
+}
+
+// Seeder upserts fixture sets. Every row gets a stable ID derived from the fixture,
+// so running the same set again updates rows in place instead of duplicating them.
+type Seeder struct {
+	db *gorm.DB
+}
+
+// NewSeeder creates a new seeder instance
+func NewSeeder(db *gorm.DB) *Seeder {
+	return &Seeder{db: db}
+}
+
+// Apply writes the whole set in a single transaction
+func (s *Seeder) Apply(ctx context.Context, set *FixtureSet) (*Result, error) {
+	now := time.Now().UTC()
+	result := &Result{}
+
+	trips := make([]models.Trip, 0, len(set.Trips))
+	for _, f := range expandCopies(set.Trips) {
+		trip, err := buildTrip(&f, now)
+		if err != nil {
+			return nil, err
+		}
+		trips = append(trips, *trip)
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, f := range set.Users {
+			user, err := buildUser(&f, now)
+			if err != nil {
+				return err
+			}
+			if err := upsert(tx, user); err != nil {
+				return fmt.Errorf("user %s: %w", f.ID, err)
+			}
+			result.Users++
+		}
+
+		for _, f := range set.Destinations {
+			dest, err := buildDestination(&f, now)
+			if err != nil {
+				return err
+			}
+			if err := upsert(tx, dest); err != nil {
+				return fmt.Errorf("destination %s: %w", f.ID, err)
+			}
+			result.Destinations++
+		}
+
+		for _, f := range set.Activities {
+			activity, err := buildActivity(&f, now)
+			if err != nil {
+				return err
+			}
+			if err := upsert(tx, activity); err != nil {
+				return fmt.Errorf("activity %s: %w", f.ID, err)
+			}
+			result.Activities++
+		}
+
+		for i := range trips {
+			if err := applyTrip(tx, &trips[i]); err != nil {
+				return fmt.Errorf("trip %s: %w", trips[i].ID, err)
+			}
+			result.Trips++
+			result.Days += len(trips[i].DayPlans)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// applyTrip upserts the trip and its children, removing children no longer in the fixture
+func applyTrip(tx *gorm.DB, trip *models.Trip) error {
+	// version is left to the database default on insert and never reset on update,
+	// so clients holding the current version keep working after a re-seed
+	if err := upsert(tx, trip, "version"); err != nil {
+		return err
+	}
+
+	tdIDs := make([]string, len(trip.TripDestinations))
+	for i, td := range trip.TripDestinations {
+		tdIDs[i] = td.ID
+	}
+	if err := deleteExcept(tx.Where("trip_id = ?", trip.ID), &models.TripDestination{}, tdIDs); err != nil {
+		return err
+	}
+	for i := range trip.TripDestinations {
+		if err := upsert(tx, &trip.TripDestinations[i]); err != nil {
+			return err
+		}
+	}
+
+	dayIDs := make([]string, len(trip.DayPlans))
+	var dpdIDs, dpaIDs []string
+	for i, day := range trip.DayPlans {
+		dayIDs[i] = day.ID
+		for _, dpd := range day.DayPlanDestinations {
+			dpdIDs = append(dpdIDs, dpd.ID)
+		}
+		for _, dpa := range day.DayPlanActivities {
+			dpaIDs = append(dpaIDs, dpa.ID)
+		}
+	}
+	if err := deleteExcept(tx.Where("trip_id = ?", trip.ID), &models.DayPlan{}, dayIDs); err != nil {
+		return err
+	}
+	if len(dayIDs) > 0 {
+		if err := deleteExcept(tx.Where("day_plan_id IN ?", dayIDs), &models.DayPlanDestination{}, dpdIDs); err != nil {
+			return err
+		}
+		if err := deleteExcept(tx.Where("day_plan_id IN ?", dayIDs), &models.DayPlanActivity{}, dpaIDs); err != nil {
+			return err
+		}
+	}
+
+	for i := range trip.DayPlans {
+		day := &trip.DayPlans[i]
+		if err := upsert(tx, day); err != nil {
+			return err
+		}
+		for j := range day.DayPlanDestinations {
+			if err := upsert(tx, &day.DayPlanDestinations[j]); err != nil {
+				return err
+			}
+		}
+		for j := range day.DayPlanActivities {
+			if err := upsert(tx, &day.DayPlanActivities[j]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// upsert inserts the row, or updates every column except the ID, created_at and omitted ones
+func upsert(tx *gorm.DB, value interface{}, omit ...string) error {
+	return tx.Omit(append([]string{clause.Associations}, omit...)...).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "id"}}, UpdateAll: true}).
+		Create(value).Error
+}
+
+// deleteExcept deletes the scoped rows whose ID is not in keep
+func deleteExcept(scope *gorm.DB, model interface{}, keep []string) error {
+	if len(keep) > 0 {
+		scope = scope.Where("id NOT IN ?", keep)
+	}
+	return scope.Delete(model).Error
+}
+
+// expandCopies replaces each trip with Copies > 1 by numbered copies
+func expandCopies(trips []TripFixture) []TripFixture {
+	expanded := make([]TripFixture, 0, len(trips))
+	for _, f := range trips {
+		if f.Copies <= 1 {
+			expanded = append(expanded, f)
+			continue
+		}
+		for n := 1; n <= f.Copies; n++ {
+			c := f
+			c.ID = fmt.Sprintf("%s-%d", f.ID, n)
+			c.Name = fmt.Sprintf("%s #%d", f.Name, n)
+			if f.Slug != nil {
+				slug := fmt.Sprintf("%s-%d", *f.Slug, n)
+				c.Slug = &slug
+			}
+			expanded = append(expanded, c)
+		}
+	}
+	return expanded
+}
+
+func buildUser(f *UserFixture, now time.Time) (*models.User, error) {
+	if f.ID == "" || f.Name == "" || f.Email == "" {
+		return nil, fmt.Errorf("user %q requires id, name and email", f.ID)
+	}
+	locale := f.Locale
+	if locale == "" {
+		locale = "en"
+	}
+	return &models.User{
+		ID:          f.ID,
+		Name:        f.Name,
+		Email:       f.Email,
+		DisplayName: f.DisplayName,
+		AvatarURL:   f.AvatarURL,
+		Locale:      locale,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+func buildDestination(f *DestinationFixture, now time.Time) (*models.Destination, error) {
+	if f.ID == "" || f.City == "" || f.Country == "" {
+		return nil, fmt.Errorf("destination %q requires id, city and country", f.ID)
+	}
+	return &models.Destination{
+		ID:          f.ID,
+		City:        f.City,
+		Region:      f.Region,
+		Country:     f.Country,
+		Latitude:    f.Latitude,
+		Longitude:   f.Longitude,
+		Timezone:    f.Timezone,
+		HeroImage:   f.HeroImage,
+		Images:      models.StringArray(f.Images),
+		Description: f.Description,
+		PlaceID:     f.PlaceID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+func buildActivity(f *ActivityFixture, now time.Time) (*models.Activity, error) {
+	if f.ID == "" || f.Title == "" || f.Type == "" {
+		return nil, fmt.Errorf("activity %q requires id, title and type", f.ID)
+	}
+	return &models.Activity{
+		ID:                    f.ID,
+		Title:                 f.Title,
+		Description:           f.Description,
+		Type:                  f.Type,
+		Location:              f.Location,
+		Address:               f.Address,
+		Latitude:              f.Latitude,
+		Longitude:             f.Longitude,
+		PlaceID:               f.PlaceID,
+		DurationMinutes:       f.DurationMinutes,
+		EstimatedCostAmount:   f.EstimatedCostAmount,
+		EstimatedCostCurrency: f.EstimatedCostCurrency,
+		ImageURL:              f.ImageURL,
+		Images:                models.StringArray(f.Images),
+		URL:                   f.URL,
+		CreatedByUserID:       f.CreatedByUserID,
+		IsVerified:            f.IsVerified,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}, nil
+}
+
+// buildTrip converts a trip fixture to models with IDs derived from the trip ID and positions
+func buildTrip(f *TripFixture, now time.Time) (*models.Trip, error) {
+	if f.ID == "" || f.UserID == "" || f.Name == "" {
+		return nil, fmt.Errorf("trip %q requires id, userId and name", f.ID)
+	}
+	start, err := time.Parse("2006-01-02", f.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("trip %s: invalid startDate %q", f.ID, f.StartDate)
+	}
+	if _, err := time.Parse("2006-01-02", f.EndDate); err != nil {
+		return nil, fmt.Errorf("trip %s: invalid endDate %q", f.ID, f.EndDate)
+	}
+
+	trip := &models.Trip{
+		ID:            f.ID,
+		UserID:        f.UserID,
+		Name:          f.Name,
+		Slug:          f.Slug,
+		TravelerCount: defaultInt(f.TravelerCount, 1),
+		Adults:        defaultInt(f.Adults, 2),
+		ChildrenAges:  f.ChildrenAges,
+		StartDate:     f.StartDate,
+		EndDate:       f.EndDate,
+		CoverImage:    f.CoverImage,
+		Visibility:    defaultString(f.Visibility, "private"),
+		Status:        defaultString(f.Status, "active"),
+		Summary:       f.Summary,
+		TravelerType:  f.TravelerType,
+		Likes:         f.Likes,
+		CloneCount:    f.CloneCount,
+		CreatedAt:     now.AddDate(0, 0, -f.CreatedDaysAgo),
+		UpdatedAt:     now,
+	}
+
+	for i, td := range f.Destinations {
+		trip.TripDestinations = append(trip.TripDestinations, models.TripDestination{
+			ID:            fmt.Sprintf("td-%s-%s", trip.ID, td.DestinationID),
+			TripID:        trip.ID,
+			DestinationID: td.DestinationID,
+			OrderIndex:    i,
+			StartDate:     td.StartDate,
+			EndDate:       td.EndDate,
+			CustomNotes:   td.CustomNotes,
+			CreatedAt:     now,
+		})
+	}
+
+	for i, d := range f.Days {
+		dayNumber := defaultInt(d.DayNumber, i+1)
+		date := d.Date
+		if date == "" {
+			date = start.AddDate(0, 0, dayNumber-1).Format("2006-01-02")
+		}
+		day := models.DayPlan{
+			ID:        fmt.Sprintf("%s-day-%d", trip.ID, dayNumber),
+			TripID:    trip.ID,
+			Date:      date,
+			DayNumber: dayNumber,
+			Notes:     d.Notes,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		for j, dd := range d.Destinations {
+			day.DayPlanDestinations = append(day.DayPlanDestinations, models.DayPlanDestination{
+				ID:            fmt.Sprintf("dpd-%s-%d-%d", trip.ID, dayNumber, j+1),
+				DayPlanID:     day.ID,
+				DestinationID: dd.DestinationID,
+				OrderIndex:    j,
+				PartOfDay:     dd.PartOfDay,
+				CreatedAt:     now,
+			})
+		}
+
+		orderWithinTime := make(map[string]int)
+		for j, da := range d.Activities {
+			timeOfDay := defaultString(da.TimeOfDay, "start")
+			day.DayPlanActivities = append(day.DayPlanActivities, models.DayPlanActivity{
+				ID:              fmt.Sprintf("dpa-%s-%d-%d", trip.ID, dayNumber, j+1),
+				DayPlanID:       day.ID,
+				ActivityID:      da.ActivityID,
+				TimeOfDay:       timeOfDay,
+				OrderWithinTime: orderWithinTime[timeOfDay],
+				CustomTitle:     da.CustomTitle,
+				CustomNotes:     da.CustomNotes,
+				CustomTime:      da.CustomTime,
+				Completed:       da.Completed,
+				Skipped:         da.Skipped,
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			})
+			orderWithinTime[timeOfDay]++
+		}
+
+		trip.DayPlans = append(trip.DayPlans, day)
+	}
+
+	return trip, nil
+}
+
+func defaultInt(value, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+func defaultString(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
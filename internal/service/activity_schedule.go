@@ -0,0 +1,179 @@
+package service
+
+import (
+	"time"
+	"triply-server/internal/models"
+	"triply-server/internal/utils"
+)
+
+// localTimeLayout is the wall-clock layout of localTime in requests and schedules
+const localTimeLayout = "15:04"
+
+// zonedTimeLayout is the local date and time layout of leg and reservation times in requests
+const zonedTimeLayout = "2006-01-02T15:04"
+
+// localizeTrip fills in the local schedule of every timed activity in the trip
+func localizeTrip(trip *models.Trip) {
+	for i := range trip.DayPlans {
+		localizeDay(trip, &trip.DayPlans[i])
+	}
+	localizeLegs(trip.TripLegs)
+}
+
+// localizeDay fills in the schedule of the day's timed activities in the day's timezone.
+// The trip supplies the fallback zone for days without destinations and may be nil.
+func localizeDay(trip *models.Trip, day *models.DayPlan) {
+	if trip == nil {
+		trip = &models.Trip{}
+	}
+	loc := dayLocation(trip, day)
+	for i := range day.DayPlanActivities {
+		dpa := &day.DayPlanActivities[i]
+		dpa.Schedule = nil
+		if dpa.CustomTime != nil {
+			dpa.Schedule = activitySchedule(*dpa.CustomTime, loc)
+		}
+	}
+}
+
+// destinationLocation returns the timezone of a destination used by any of the trips,
+// or fallback when none of them knows its zone
+func destinationLocation(destinationID string, fallback *time.Location, trips ...*models.Trip) *time.Location {
+	var candidates []*models.Destination
+	for _, trip := range trips {
+		for _, td := range trip.TripDestinations {
+			if td.DestinationID == destinationID {
+				candidates = append(candidates, td.Destination)
+			}
+		}
+		for _, day := range trip.DayPlans {
+			for _, dpd := range day.DayPlanDestinations {
+				if dpd.DestinationID == destinationID {
+					candidates = append(candidates, dpd.Destination)
+				}
+			}
+		}
+	}
+
+	for _, dest := range candidates {
+		if dest == nil || dest.Timezone == nil || *dest.Timezone == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(*dest.Timezone); err == nil {
+			return loc
+		}
+	}
+	return fallback
+}
+
+func activitySchedule(t time.Time, loc *time.Location) *models.ActivitySchedule {
+	local := t.In(loc)
+	return &models.ActivitySchedule{
+		Timezone:  loc.String(),
+		Local:     local.Format(time.RFC3339),
+		LocalDate: local.Format(utils.DateLayout),
+		LocalTime: local.Format(localTimeLayout),
+		UTC:       t.UTC().Format(time.RFC3339),
+	}
+}
+
+func localizeLegs(legs []models.TripLeg) {
+	for i := range legs {
+		localizeLeg(&legs[i])
+	}
+}
+
+// localizeLeg fills in the leg's departure and arrival in their own zones
+func localizeLeg(leg *models.TripLeg) {
+	leg.Departure, leg.Arrival = nil, nil
+	if leg.DepartureTime != nil {
+		leg.Departure = activitySchedule(*leg.DepartureTime, zoneLocation(leg.DepartureTimezone))
+	}
+	if leg.ArrivalTime != nil {
+		leg.Arrival = activitySchedule(*leg.ArrivalTime, zoneLocation(leg.ArrivalTimezone))
+	}
+}
+
+// zoneLocation loads a stored IANA zone name, falling back to UTC
+func zoneLocation(name *string) *time.Location {
+	if name == nil || *name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(*name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseLocalTime interprets an HH:MM wall-clock time on the day's date in the day's zone
+func parseLocalTime(value, date string, loc *time.Location) (time.Time, error) {
+	clock, err := time.Parse(localTimeLayout, value)
+	if err != nil {
+		return time.Time{}, utils.NewValidationError("localTime must be in HH:MM format")
+	}
+	day, err := utils.ParseDate(date)
+	if err != nil {
+		return time.Time{}, utils.NewValidationError("the day has no valid date")
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, loc), nil
+}
+
+// moveLocalTime moves a scheduled time to another day, keeping its local wall-clock time:
+// 09:00 in the source day's zone becomes 09:00 on the target date in the target day's zone
+func moveLocalTime(t time.Time, from *time.Location, targetDate string, to *time.Location) time.Time {
+	local := t.In(from)
+	day, err := utils.ParseDate(targetDate)
+	if err != nil {
+		day = local
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), local.Hour(), local.Minute(), local.Second(), 0, to)
+}
+
+type zonedTime struct {
+	time *time.Time
+	zone *string
+}
+
+// resolveZonedTime works out a time and its zone from the stored values and the requested
+// changes. The zone defaults to defaultZone. Changing the zone without a new time keeps the
+// time's local clock reading, so a corrected zone doesn't shift it.
+func resolveZonedTime(field string, current *time.Time, currentZone, value, zone, defaultZone *string) (zonedTime, error) {
+	result := zonedTime{time: current, zone: currentZone}
+	if zone != nil {
+		result.zone = emptyToNil(zone)
+		if result.zone != nil {
+			if _, err := time.LoadLocation(*result.zone); err != nil {
+				return result, utils.NewValidationError(field + "Timezone must be an IANA timezone, e.g. Europe/Paris")
+			}
+		}
+	}
+	if result.zone == nil {
+		result.zone = emptyToNil(defaultZone)
+	}
+	loc := zoneLocation(result.zone)
+
+	switch {
+	case value != nil && *value == "":
+		result.time = nil
+	case value != nil:
+		t, err := parseZonedTime(*value, loc)
+		if err != nil {
+			return result, utils.NewValidationError(field + "Time must be a local date and time (YYYY-MM-DDTHH:MM) or RFC 3339")
+		}
+		result.time = &t
+	case current != nil && zone != nil:
+		local := current.In(zoneLocation(currentZone))
+		moved := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), local.Minute(), local.Second(), 0, loc)
+		result.time = &moved
+	}
+	return result, nil
+}
+
+// parseZonedTime accepts an RFC 3339 time, or a local date and time in loc
+func parseZonedTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation(zonedTimeLayout, value, loc)
+}
@@ -2,34 +2,223 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
+	"triply-server/internal/dto"
 	"triply-server/internal/models"
+	"triply-server/internal/realtime"
 	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
 )
 
 // ActivityService defines the interface for activity operations
 type ActivityService interface {
 	GetActivitiesByDayPlan(ctx context.Context, dayPlanID string) ([]models.DayPlanActivity, error)
-	UpdateActivityOrders(ctx context.Context, dayPlanID string, dayPlanActivities []models.DayPlanActivity) error
+	UpdateActivityOrders(ctx context.Context, ownerID string, req *dto.ActivityOrderRequest) ([]dto.ActivityOrderPayload, error)
 }
 
 type activityService struct {
 	activityRepo repository.ActivityRepository
+	tripRepo     repository.TripRepository
+	dayPlanRepo  repository.DayPlanRepository
+	events       realtime.Publisher
 }
 
 // NewActivityService creates a new activity service instance
-func NewActivityService(activityRepo repository.ActivityRepository) ActivityService {
-	return &activityService{activityRepo: activityRepo}
+func NewActivityService(activityRepo repository.ActivityRepository, tripRepo repository.TripRepository, dayPlanRepo repository.DayPlanRepository, events realtime.Publisher) ActivityService {
+	return &activityService{
+		activityRepo: activityRepo,
+		tripRepo:     tripRepo,
+		dayPlanRepo:  dayPlanRepo,
+		events:       events,
+	}
 }
 
 func (s *activityService) GetActivitiesByDayPlan(ctx context.Context, dayPlanID string) ([]models.DayPlanActivity, error) {
 	return s.activityRepo.FindByDayPlanID(ctx, dayPlanID)
 }
 
-func (s *activityService) UpdateActivityOrders(ctx context.Context, dayPlanID string, dayPlanActivities []models.DayPlanActivity) error {
-	// Ensure all activities belong to the specified day plan
-	for i := range dayPlanActivities {
-		dayPlanActivities[i].DayPlanID = dayPlanID
+// UpdateActivityOrders applies a day's new activity layout, moving in activities listed from
+// other days of the same trip and closing the gaps they leave behind. It returns the canonical
+// positions of every activity on the affected days, target day first.
+func (s *activityService) UpdateActivityOrders(ctx context.Context, ownerID string, req *dto.ActivityOrderRequest) ([]dto.ActivityOrderPayload, error) {
+	if req.TripID == "" || req.DayID == "" {
+		return nil, utils.NewValidationError("tripId and dayId are required")
+	}
+	if _, err := requireTripRole(ctx, s.tripRepo, req.TripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+	if _, err := s.dayPlanRepo.FindByID(ctx, req.TripID, req.DayID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Day plan")
+		}
+		return nil, err
+	}
+
+	placed, err := s.dayPlanRepo.FindActivitiesByTripID(ctx, req.TripID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.DayPlanActivity, len(placed))
+	for _, dpa := range placed {
+		byID[dpa.ID] = dpa
+	}
+
+	listed := make(map[string]bool, len(req.Activities))
+	sourceDays := make(map[string]bool)
+	layout := make([]models.DayPlanActivity, 0, len(req.Activities))
+	for _, item := range req.Activities {
+		if item.ID == "" {
+			return nil, utils.NewValidationError("every activity needs an id")
+		}
+		if listed[item.ID] {
+			return nil, utils.NewValidationError(fmt.Sprintf("activity %s is listed more than once", item.ID))
+		}
+		listed[item.ID] = true
+
+		dpa, ok := byID[item.ID]
+		if !ok {
+			return nil, utils.NewNotFoundError("Day plan activity " + item.ID)
+		}
+		if item.TimeOfDay != "" {
+			dpa.TimeOfDay = item.TimeOfDay
+		}
+		if !validTimesOfDay[dpa.TimeOfDay] {
+			return nil, utils.NewValidationError("timeOfDay must be one of start, mid, end, morning, afternoon, evening")
+		}
+		if dpa.DayPlanID != req.DayID {
+			sourceDays[dpa.DayPlanID] = true
+		}
+		dpa.DayPlanID = req.DayID
+		dpa.OrderWithinTime = item.OrderWithinTime
+		layout = append(layout, dpa)
+	}
+
+	// The layout replaces the whole day, so nothing on it may be left out
+	var left []models.DayPlanActivity
+	for _, dpa := range placed {
+		if listed[dpa.ID] {
+			continue
+		}
+		if dpa.DayPlanID == req.DayID {
+			return nil, utils.NewValidationError(fmt.Sprintf("activities must list every activity on the day (missing %s)", dpa.ID))
+		}
+		if sourceDays[dpa.DayPlanID] {
+			left = append(left, dpa)
+		}
+	}
+
+	if len(sourceDays) > 0 {
+		if err := s.moveLocalTimes(ctx, ownerID, req.TripID, req.DayID, layout, byID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	positions := append(renumberLayout(layout, now), renumberLayout(left, now)...)
+	if err := s.dayPlanRepo.MoveActivities(ctx, req.TripID, positions, ownerID); err != nil {
+		return nil, err
+	}
+
+	order := canonicalActivityOrder(positions, req.DayID)
+	publishTripEvent(ctx, s.events, req.TripID, ownerID, realtime.EventActivitiesReordered, dto.ActivitiesReorderedEvent{
+		DayID:      req.DayID,
+		Activities: order,
+	})
+	return order, nil
+}
+
+// moveLocalTimes converts the custom times of activities moved in from other days so they keep
+// their local clock time on the target day, in the target day's zone
+func (s *activityService) moveLocalTimes(ctx context.Context, ownerID, tripID, dayID string, layout []models.DayPlanActivity, original map[string]models.DayPlanActivity) error {
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Trip")
+		}
+		return err
+	}
+	target := findTripDay(trip, dayID)
+	if target == nil {
+		return utils.NewNotFoundError("Day plan")
 	}
 
-	return s.activityRepo.UpdateOrders(ctx, dayPlanActivities)
+	for i := range layout {
+		dpa := &layout[i]
+		from := original[dpa.ID].DayPlanID
+		if dpa.CustomTime == nil || from == dayID {
+			continue
+		}
+		source := findTripDay(trip, from)
+		if source == nil {
+			continue
+		}
+		moved := moveLocalTime(*dpa.CustomTime, dayLocation(trip, source), target.Date, dayLocation(trip, target))
+		dpa.CustomTime = &moved
+	}
+	return nil
+}
+
+// renumberLayout assigns dense positions (0..n-1) within each day and time bucket,
+// keeping the requested relative order and falling back to input order for ties
+func renumberLayout(activities []models.DayPlanActivity, now time.Time) []models.DayPlanActivity {
+	sort.SliceStable(activities, func(i, j int) bool {
+		a, b := activities[i], activities[j]
+		if a.DayPlanID != b.DayPlanID {
+			return a.DayPlanID < b.DayPlanID
+		}
+		if timeOfDayRank[a.TimeOfDay] != timeOfDayRank[b.TimeOfDay] {
+			return timeOfDayRank[a.TimeOfDay] < timeOfDayRank[b.TimeOfDay]
+		}
+		if a.TimeOfDay != b.TimeOfDay {
+			return a.TimeOfDay < b.TimeOfDay
+		}
+		return a.OrderWithinTime < b.OrderWithinTime
+	})
+
+	next := make(map[string]int)
+	for i := range activities {
+		bucket := activities[i].DayPlanID + "/" + activities[i].TimeOfDay
+		activities[i].OrderWithinTime = next[bucket]
+		activities[i].UpdatedAt = now
+		next[bucket]++
+	}
+	return activities
+}
+
+// canonicalActivityOrder lists positions with the target day first and any other days after it
+// by ID, each chronologically by bucket
+func canonicalActivityOrder(positions []models.DayPlanActivity, targetDayID string) []dto.ActivityOrderPayload {
+	sorted := make([]models.DayPlanActivity, len(positions))
+	copy(sorted, positions)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.DayPlanID != b.DayPlanID {
+			if a.DayPlanID == targetDayID || b.DayPlanID == targetDayID {
+				return a.DayPlanID == targetDayID
+			}
+			return a.DayPlanID < b.DayPlanID
+		}
+		if timeOfDayRank[a.TimeOfDay] != timeOfDayRank[b.TimeOfDay] {
+			return timeOfDayRank[a.TimeOfDay] < timeOfDayRank[b.TimeOfDay]
+		}
+		if a.TimeOfDay != b.TimeOfDay {
+			return a.TimeOfDay < b.TimeOfDay
+		}
+		return a.OrderWithinTime < b.OrderWithinTime
+	})
+
+	order := make([]dto.ActivityOrderPayload, len(sorted))
+	for i, dpa := range sorted {
+		order[i] = dto.ActivityOrderPayload{
+			ID:              dpa.ID,
+			DayID:           dpa.DayPlanID,
+			OrderWithinTime: dpa.OrderWithinTime,
+			TimeOfDay:       dpa.TimeOfDay,
+		}
+	}
+	return order
 }
@@ -0,0 +1,501 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ArchiveService defines the interface for trip archive export and import
+type ArchiveService interface {
+	ExportTrip(ctx context.Context, ownerID, tripID string) (*dto.TripArchive, error)
+	ExportAll(ctx context.Context, ownerID string) (*dto.TripArchive, error)
+	ImportArchive(ctx context.Context, ownerID string, archive *dto.TripArchive) ([]models.Trip, error)
+}
+
+type archiveService struct {
+	tripRepo        repository.TripRepository
+	activityRepo    repository.ActivityRepository
+	destinationRepo repository.DestinationRepository
+	importRepo      repository.ImportRepository
+}
+
+// NewArchiveService creates a new archive service instance
+func NewArchiveService(tripRepo repository.TripRepository, activityRepo repository.ActivityRepository, destinationRepo repository.DestinationRepository, importRepo repository.ImportRepository) ArchiveService {
+	return &archiveService{
+		tripRepo:        tripRepo,
+		activityRepo:    activityRepo,
+		destinationRepo: destinationRepo,
+		importRepo:      importRepo,
+	}
+}
+
+func (s *archiveService) ExportTrip(ctx context.Context, ownerID, tripID string) (*dto.TripArchive, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+	return buildTripArchive([]models.Trip{*trip}), nil
+}
+
+// ExportAll archives the trips the user owns; trips shared with them belong in their owners' backups
+func (s *archiveService) ExportAll(ctx context.Context, ownerID string) (*dto.TripArchive, error) {
+	trips, err := s.tripRepo.FindOwnedByUserID(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	return buildTripArchive(trips), nil
+}
+
+// ImportArchive recreates the archived trips for the owner with fresh IDs. Library destinations
+// and activities that already exist under the same ID are reused; the rest are created.
+func (s *archiveService) ImportArchive(ctx context.Context, ownerID string, archive *dto.TripArchive) ([]models.Trip, error) {
+	if archive.Format != dto.ArchiveFormat {
+		return nil, utils.NewValidationError(fmt.Sprintf("format must be '%s'", dto.ArchiveFormat))
+	}
+	if archive.Version < 1 || archive.Version > dto.ArchiveFormatVersion {
+		return nil, utils.NewValidationError(fmt.Sprintf("unsupported archive version %d (supported: 1-%d)", archive.Version, dto.ArchiveFormatVersion))
+	}
+	if len(archive.Trips) == 0 {
+		return nil, utils.NewValidationError("archive contains no trips")
+	}
+
+	now := time.Now()
+	changes := &repository.ArchiveChanges{}
+
+	destinationIDs, err := s.mapArchiveDestinations(ctx, archive.Destinations, changes, now)
+	if err != nil {
+		return nil, err
+	}
+	activityIDs, err := s.mapArchiveActivities(ctx, archive.Activities, ownerID, changes, now)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, at := range archive.Trips {
+		trip, err := tripFromArchive(&at, ownerID, destinationIDs, activityIDs, now)
+		if err != nil {
+			return nil, err
+		}
+		changes.Trips = append(changes.Trips, *trip)
+	}
+
+	if err := s.importRepo.ApplyArchive(ctx, changes); err != nil {
+		return nil, err
+	}
+
+	imported := make([]models.Trip, 0, len(changes.Trips))
+	for _, trip := range changes.Trips {
+		loaded, err := s.tripRepo.FindByID(ctx, trip.ID, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		imported = append(imported, *loaded)
+	}
+	return imported, nil
+}
+
+// mapArchiveDestinations maps archive destination IDs to existing or newly created destinations
+func (s *archiveService) mapArchiveDestinations(ctx context.Context, entries []dto.ArchiveDestination, changes *repository.ArchiveChanges, now time.Time) (map[string]string, error) {
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	existing, err := s.destinationRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := make(map[string]string, len(entries))
+	for _, d := range existing {
+		mapping[d.ID] = d.ID
+	}
+	for _, e := range entries {
+		if _, ok := mapping[e.ID]; ok {
+			continue
+		}
+		if e.City == "" || e.Country == "" {
+			return nil, utils.NewValidationError(fmt.Sprintf("destination %s requires city and country", e.ID))
+		}
+		dest := models.Destination{
+			ID:          utils.GenerateID("dest"),
+			City:        e.City,
+			Region:      e.Region,
+			Country:     e.Country,
+			Latitude:    e.Latitude,
+			Longitude:   e.Longitude,
+			Timezone:    e.Timezone,
+			HeroImage:   e.HeroImage,
+			Images:      models.StringArray(e.Images),
+			Description: e.Description,
+			PlaceID:     e.PlaceID,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		changes.NewDestinations = append(changes.NewDestinations, dest)
+		mapping[e.ID] = dest.ID
+	}
+	return mapping, nil
+}
+
+// mapArchiveActivities maps archive activity IDs to existing or newly created library activities
+func (s *archiveService) mapArchiveActivities(ctx context.Context, entries []dto.ArchiveActivity, ownerID string, changes *repository.ArchiveChanges, now time.Time) (map[string]string, error) {
+	ids := make([]string, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	existing, err := s.activityRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := make(map[string]string, len(entries))
+	for _, a := range existing {
+		mapping[a.ID] = a.ID
+	}
+	for _, e := range entries {
+		if _, ok := mapping[e.ID]; ok {
+			continue
+		}
+		if e.Title == "" {
+			return nil, utils.NewValidationError(fmt.Sprintf("activity %s requires a title", e.ID))
+		}
+		activityType := e.Type
+		if activityType == "" {
+			activityType = inferActivityType(e.Title)
+		}
+		createdBy := ownerID
+		activity := models.Activity{
+			ID:                    utils.GenerateID("act"),
+			Title:                 e.Title,
+			Description:           e.Description,
+			Type:                  activityType,
+			Location:              e.Location,
+			Address:               e.Address,
+			Latitude:              e.Latitude,
+			Longitude:             e.Longitude,
+			PlaceID:               e.PlaceID,
+			DurationMinutes:       e.DurationMinutes,
+			EstimatedCostAmount:   e.EstimatedCostAmount,
+			EstimatedCostCurrency: e.EstimatedCostCurrency,
+			ImageURL:              e.ImageURL,
+			Images:                models.StringArray(e.Images),
+			URL:                   e.URL,
+			CreatedByUserID:       &createdBy,
+			CreatedAt:             now,
+			UpdatedAt:             now,
+		}
+		changes.NewLibraryActivities = append(changes.NewLibraryActivities, activity)
+		mapping[e.ID] = activity.ID
+	}
+	return mapping, nil
+}
+
+// tripFromArchive builds a new private trip with fresh IDs from an archive entry
+func tripFromArchive(at *dto.ArchiveTrip, ownerID string, destinationIDs, activityIDs map[string]string, now time.Time) (*models.Trip, error) {
+	if at.Name == "" {
+		return nil, utils.NewValidationError("archived trips require a name")
+	}
+	if _, err := utils.ParseDate(at.StartDate); err != nil {
+		return nil, utils.NewValidationError(fmt.Sprintf("trip %q has an invalid startDate", at.Name))
+	}
+	if _, err := utils.ParseDate(at.EndDate); err != nil {
+		return nil, utils.NewValidationError(fmt.Sprintf("trip %q has an invalid endDate", at.Name))
+	}
+
+	status := at.Status
+	if status == "" {
+		status = "active"
+	}
+	trip := &models.Trip{
+		ID:            utils.GenerateID("trip"),
+		UserID:        ownerID,
+		Name:          at.Name,
+		StartDate:     utils.FormatDate(at.StartDate),
+		EndDate:       utils.FormatDate(at.EndDate),
+		CoverImage:    at.CoverImage,
+		TravelerCount: at.TravelerCount,
+		Adults:        at.Adults,
+		ChildrenAges:  at.ChildrenAges,
+		Visibility:    "private",
+		Status:        status,
+		Summary:       at.Summary,
+		TravelerType:  at.TravelerType,
+		Version:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	lookupDestination := func(id string) (string, error) {
+		if mapped, ok := destinationIDs[id]; ok {
+			return mapped, nil
+		}
+		return "", utils.NewValidationError(fmt.Sprintf("trip %q references unknown destination %s", at.Name, id))
+	}
+
+	seen := make(map[string]bool)
+	for _, atd := range at.Destinations {
+		destID, err := lookupDestination(atd.DestinationID)
+		if err != nil {
+			return nil, err
+		}
+		if seen[destID] {
+			continue
+		}
+		seen[destID] = true
+		trip.TripDestinations = append(trip.TripDestinations, models.TripDestination{
+			ID:              utils.GenerateID("td"),
+			TripID:          trip.ID,
+			DestinationID:   destID,
+			OrderIndex:      atd.OrderIndex,
+			StartDate:       atd.StartDate,
+			EndDate:         atd.EndDate,
+			CustomNotes:     atd.CustomNotes,
+			CustomHeroImage: atd.CustomHeroImage,
+			CreatedAt:       now,
+		})
+	}
+
+	for _, ad := range at.Days {
+		if _, err := utils.ParseDate(ad.Date); err != nil {
+			return nil, utils.NewValidationError(fmt.Sprintf("trip %q has a day with an invalid date", at.Name))
+		}
+		day := models.DayPlan{
+			ID:        utils.GenerateID("day"),
+			TripID:    trip.ID,
+			Date:      utils.FormatDate(ad.Date),
+			DayNumber: ad.DayNumber,
+			Notes:     ad.Notes,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+
+		for _, add := range ad.Destinations {
+			destID, err := lookupDestination(add.DestinationID)
+			if err != nil {
+				return nil, err
+			}
+			day.DayPlanDestinations = append(day.DayPlanDestinations, models.DayPlanDestination{
+				ID:            utils.GenerateID("dpd"),
+				DayPlanID:     day.ID,
+				DestinationID: destID,
+				OrderIndex:    add.OrderIndex,
+				PartOfDay:     add.PartOfDay,
+				CreatedAt:     now,
+			})
+		}
+
+		for _, ada := range ad.Activities {
+			activityID, ok := activityIDs[ada.ActivityID]
+			if !ok {
+				return nil, utils.NewValidationError(fmt.Sprintf("trip %q references unknown activity %s", at.Name, ada.ActivityID))
+			}
+			timeOfDay := ada.TimeOfDay
+			if !validTimesOfDay[timeOfDay] {
+				timeOfDay = "start"
+			}
+			day.DayPlanActivities = append(day.DayPlanActivities, models.DayPlanActivity{
+				ID:              utils.GenerateID("dpa"),
+				DayPlanID:       day.ID,
+				ActivityID:      activityID,
+				TimeOfDay:       timeOfDay,
+				OrderWithinTime: ada.OrderWithinTime,
+				CustomTitle:     ada.CustomTitle,
+				CustomNotes:     ada.CustomNotes,
+				CustomTime:      ada.CustomTime,
+				Completed:       ada.Completed,
+				Skipped:         ada.Skipped,
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			})
+		}
+
+		trip.DayPlans = append(trip.DayPlans, day)
+	}
+
+	for _, al := range at.Legs {
+		fromID, err := lookupDestination(al.FromDestinationID)
+		if err != nil {
+			return nil, err
+		}
+		toID, err := lookupDestination(al.ToDestinationID)
+		if err != nil {
+			return nil, err
+		}
+		if !validLegModes[al.Mode] {
+			return nil, utils.NewValidationError(fmt.Sprintf("trip %q has a leg with an invalid mode", at.Name))
+		}
+		trip.TripLegs = append(trip.TripLegs, models.TripLeg{
+			ID:                utils.GenerateID("leg"),
+			TripID:            trip.ID,
+			FromDestinationID: fromID,
+			ToDestinationID:   toID,
+			Mode:              al.Mode,
+			Carrier:           al.Carrier,
+			BookingReference:  al.BookingReference,
+			DepartureTime:     al.DepartureTime,
+			DepartureTimezone: al.DepartureTimezone,
+			ArrivalTime:       al.ArrivalTime,
+			ArrivalTimezone:   al.ArrivalTimezone,
+			CostAmount:        al.CostAmount,
+			CostCurrency:      al.CostCurrency,
+			Notes:             al.Notes,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	}
+
+	return trip, nil
+}
+
+// buildTripArchive converts loaded trips (with destinations and activities preloaded) to an archive
+func buildTripArchive(trips []models.Trip) *dto.TripArchive {
+	archive := &dto.TripArchive{
+		Format:       dto.ArchiveFormat,
+		Version:      dto.ArchiveFormatVersion,
+		ExportedAt:   time.Now().UTC(),
+		Trips:        make([]dto.ArchiveTrip, 0, len(trips)),
+		Destinations: []dto.ArchiveDestination{},
+		Activities:   []dto.ArchiveActivity{},
+	}
+
+	seenDestinations := make(map[string]bool)
+	addDestination := func(d *models.Destination) {
+		if d == nil || seenDestinations[d.ID] {
+			return
+		}
+		seenDestinations[d.ID] = true
+		archive.Destinations = append(archive.Destinations, dto.ArchiveDestination{
+			ID:          d.ID,
+			City:        d.City,
+			Region:      d.Region,
+			Country:     d.Country,
+			Latitude:    d.Latitude,
+			Longitude:   d.Longitude,
+			Timezone:    d.Timezone,
+			HeroImage:   d.HeroImage,
+			Images:      []string(d.Images),
+			Description: d.Description,
+			PlaceID:     d.PlaceID,
+		})
+	}
+
+	seenActivities := make(map[string]bool)
+	addActivity := func(a *models.Activity) {
+		if a == nil || seenActivities[a.ID] {
+			return
+		}
+		seenActivities[a.ID] = true
+		archive.Activities = append(archive.Activities, dto.ArchiveActivity{
+			ID:                    a.ID,
+			Title:                 a.Title,
+			Description:           a.Description,
+			Type:                  a.Type,
+			Location:              a.Location,
+			Address:               a.Address,
+			Latitude:              a.Latitude,
+			Longitude:             a.Longitude,
+			PlaceID:               a.PlaceID,
+			DurationMinutes:       a.DurationMinutes,
+			EstimatedCostAmount:   a.EstimatedCostAmount,
+			EstimatedCostCurrency: a.EstimatedCostCurrency,
+			ImageURL:              a.ImageURL,
+			Images:                []string(a.Images),
+			URL:                   a.URL,
+		})
+	}
+
+	for _, trip := range trips {
+		at := dto.ArchiveTrip{
+			ID:            trip.ID,
+			Name:          trip.Name,
+			StartDate:     utils.FormatDate(trip.StartDate),
+			EndDate:       utils.FormatDate(trip.EndDate),
+			CoverImage:    trip.CoverImage,
+			TravelerCount: trip.TravelerCount,
+			Adults:        trip.Adults,
+			ChildrenAges:  trip.ChildrenAges,
+			Status:        trip.Status,
+			Summary:       trip.Summary,
+			TravelerType:  trip.TravelerType,
+			Destinations:  []dto.ArchiveTripDestination{},
+			Days:          []dto.ArchiveDay{},
+		}
+
+		for _, td := range trip.TripDestinations {
+			addDestination(td.Destination)
+			at.Destinations = append(at.Destinations, dto.ArchiveTripDestination{
+				DestinationID:   td.DestinationID,
+				OrderIndex:      td.OrderIndex,
+				StartDate:       td.StartDate,
+				EndDate:         td.EndDate,
+				CustomNotes:     td.CustomNotes,
+				CustomHeroImage: td.CustomHeroImage,
+			})
+		}
+
+		for _, day := range trip.DayPlans {
+			ad := dto.ArchiveDay{
+				Date:         utils.FormatDate(day.Date),
+				DayNumber:    day.DayNumber,
+				Notes:        day.Notes,
+				Destinations: []dto.ArchiveDayDestination{},
+				Activities:   []dto.ArchiveDayActivity{},
+			}
+			for _, dpd := range day.DayPlanDestinations {
+				addDestination(dpd.Destination)
+				ad.Destinations = append(ad.Destinations, dto.ArchiveDayDestination{
+					DestinationID: dpd.DestinationID,
+					OrderIndex:    dpd.OrderIndex,
+					PartOfDay:     dpd.PartOfDay,
+				})
+			}
+			for _, dpa := range orderedDayActivities(&day) {
+				addActivity(dpa.Activity)
+				ad.Activities = append(ad.Activities, dto.ArchiveDayActivity{
+					ActivityID:      dpa.ActivityID,
+					TimeOfDay:       dpa.TimeOfDay,
+					OrderWithinTime: dpa.OrderWithinTime,
+					CustomTitle:     dpa.CustomTitle,
+					CustomNotes:     dpa.CustomNotes,
+					CustomTime:      dpa.CustomTime,
+					Completed:       dpa.Completed,
+					Skipped:         dpa.Skipped,
+				})
+			}
+			at.Days = append(at.Days, ad)
+		}
+
+		for _, leg := range trip.TripLegs {
+			addDestination(leg.FromDestination)
+			addDestination(leg.ToDestination)
+			at.Legs = append(at.Legs, dto.ArchiveLeg{
+				FromDestinationID: leg.FromDestinationID,
+				ToDestinationID:   leg.ToDestinationID,
+				Mode:              leg.Mode,
+				Carrier:           leg.Carrier,
+				BookingReference:  leg.BookingReference,
+				DepartureTime:     leg.DepartureTime,
+				DepartureTimezone: leg.DepartureTimezone,
+				ArrivalTime:       leg.ArrivalTime,
+				ArrivalTimezone:   leg.ArrivalTimezone,
+				CostAmount:        leg.CostAmount,
+				CostCurrency:      leg.CostCurrency,
+				Notes:             leg.Notes,
+			})
+		}
+
+		archive.Trips = append(archive.Trips, at)
+	}
+
+	return archive
+}
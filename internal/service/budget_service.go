@@ -0,0 +1,662 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// validExpenseCategories lists the categories an expense can be filed under
+var validExpenseCategories = map[string]bool{
+	models.ExpenseCategoryAccommodation:  true,
+	models.ExpenseCategoryTransportation: true,
+	models.ExpenseCategoryFood:           true,
+	models.ExpenseCategoryActivities:     true,
+	models.ExpenseCategoryShopping:       true,
+	models.ExpenseCategoryOther:          true,
+}
+
+// BudgetService defines the interface for trip expense and budget operations
+type BudgetService interface {
+	ListExpenses(ctx context.Context, ownerID, tripID string) ([]models.TripExpense, error)
+	CreateExpense(ctx context.Context, ownerID, tripID string, req *dto.CreateExpenseRequest) (*models.TripExpense, error)
+	UpdateExpense(ctx context.Context, ownerID, tripID, expenseID string, req *dto.UpdateExpenseRequest) (*models.TripExpense, error)
+	DeleteExpense(ctx context.Context, ownerID, tripID, expenseID string) error
+	GetSummary(ctx context.Context, ownerID, tripID string, query *dto.BudgetSummaryQuery) (*dto.BudgetSummaryResponse, error)
+}
+
+type budgetService struct {
+	tripRepo        repository.TripRepository
+	expenseRepo     repository.TripExpenseRepository
+	currencyService CurrencyService
+	events          realtime.Publisher
+}
+
+// NewBudgetService creates a new budget service instance
+func NewBudgetService(tripRepo repository.TripRepository, expenseRepo repository.TripExpenseRepository, currencyService CurrencyService, events realtime.Publisher) BudgetService {
+	return &budgetService{
+		tripRepo:        tripRepo,
+		expenseRepo:     expenseRepo,
+		currencyService: currencyService,
+		events:          events,
+	}
+}
+
+func (s *budgetService) ListExpenses(ctx context.Context, ownerID, tripID string) ([]models.TripExpense, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.expenseRepo.FindByTripID(ctx, tripID)
+}
+
+func (s *budgetService) CreateExpense(ctx context.Context, ownerID, tripID string, req *dto.CreateExpenseRequest) (*models.TripExpense, error) {
+	trip, err := s.findEditableTrip(ctx, ownerID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expense := &models.TripExpense{
+		ID:                utils.GenerateID("exp"),
+		TripID:            tripID,
+		DayPlanActivityID: emptyToNil(req.DayPlanActivityID),
+		DayPlanID:         emptyToNil(req.DayPlanID),
+		DestinationID:     emptyToNil(req.DestinationID),
+		Title:             strings.TrimSpace(req.Title),
+		Category:          req.Category,
+		Notes:             req.Notes,
+		PlannedAmount:     req.PlannedAmount,
+		ActualAmount:      req.ActualAmount,
+		Currency:          req.Currency,
+		SpentOn:           emptyToNil(req.SpentOn),
+		CreatedByUserID:   ownerID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := validateExpense(trip, expense); err != nil {
+		return nil, err
+	}
+
+	if err := s.expenseRepo.Create(ctx, expense); err != nil {
+		return nil, err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventExpenseAdded, dto.ExpenseChangeEvent{
+		ExpenseID: expense.ID,
+		Expense:   expense,
+	})
+	return expense, nil
+}
+
+func (s *budgetService) UpdateExpense(ctx context.Context, ownerID, tripID, expenseID string, req *dto.UpdateExpenseRequest) (*models.TripExpense, error) {
+	trip, err := s.findEditableTrip(ctx, ownerID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	expense, err := s.expenseRepo.FindByID(ctx, tripID, expenseID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Expense")
+		}
+		return nil, err
+	}
+
+	if req.DayPlanActivityID != nil {
+		expense.DayPlanActivityID = emptyToNil(req.DayPlanActivityID)
+	}
+	if req.DayPlanID != nil {
+		expense.DayPlanID = emptyToNil(req.DayPlanID)
+	}
+	if req.DestinationID != nil {
+		expense.DestinationID = emptyToNil(req.DestinationID)
+	}
+	if req.Title != nil {
+		expense.Title = strings.TrimSpace(*req.Title)
+	}
+	if req.Category != nil {
+		expense.Category = *req.Category
+	}
+	if req.Notes != nil {
+		expense.Notes = req.Notes
+	}
+	if req.PlannedAmount != nil {
+		expense.PlannedAmount = req.PlannedAmount
+	}
+	if req.ActualAmount != nil {
+		expense.ActualAmount = req.ActualAmount
+	}
+	if req.Currency != nil {
+		expense.Currency = *req.Currency
+	}
+	if req.SpentOn != nil {
+		expense.SpentOn = emptyToNil(req.SpentOn)
+	}
+	expense.UpdatedAt = time.Now()
+
+	if err := validateExpense(trip, expense); err != nil {
+		return nil, err
+	}
+
+	if err := s.expenseRepo.Update(ctx, expense); err != nil {
+		return nil, err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventExpenseUpdated, dto.ExpenseChangeEvent{
+		ExpenseID: expense.ID,
+		Expense:   expense,
+	})
+	return expense, nil
+}
+
+func (s *budgetService) DeleteExpense(ctx context.Context, ownerID, tripID, expenseID string) error {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return err
+	}
+
+	if err := s.expenseRepo.Delete(ctx, tripID, expenseID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Expense")
+		}
+		return err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventExpenseRemoved, dto.ExpenseChangeEvent{
+		ExpenseID: expenseID,
+	})
+	return nil
+}
+
+// GetSummary rolls the trip's costs up per activity, day, destination, category and trip,
+// optionally converted into one currency at the rates of the query's date
+func (s *budgetService) GetSummary(ctx context.Context, ownerID, tripID string, query *dto.BudgetSummaryQuery) (*dto.BudgetSummaryResponse, error) {
+	currency, err := normalizeCurrencyQuery(query.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+
+	expenses, err := s.expenseRepo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := summarizeBudget(trip, expenses, query.Travelers)
+	if currency != "" {
+		rates, err := s.currencyService.RatesOn(ctx, query.Date)
+		if err != nil {
+			return nil, err
+		}
+		convertBudget(&summary, rates, currency, query.Date)
+	}
+	return &summary, nil
+}
+
+// findEditableTrip loads the trip's itinerary for validating expense links, requiring editor access
+func (s *budgetService) findEditableTrip(ctx context.Context, ownerID, tripID string) (*models.Trip, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+	return trip, nil
+}
+
+// validateExpense checks the expense against the trip, filling in the title, category and
+// currency of activity expenses from the activity when they are not set
+func validateExpense(trip *models.Trip, expense *models.TripExpense) error {
+	if expense.DayPlanActivityID != nil {
+		dpa := findTripActivity(trip, *expense.DayPlanActivityID)
+		if dpa == nil {
+			return utils.NewNotFoundError("Day plan activity")
+		}
+		if expense.DayPlanID != nil {
+			return utils.NewValidationError("dayPlanId cannot be set on an activity expense; it follows the activity's day")
+		}
+		if expense.Title == "" {
+			expense.Title = activityTitle(*dpa)
+		}
+		if expense.Category == "" && dpa.Activity != nil {
+			expense.Category = expenseCategoryFor(dpa.Activity.Type)
+		}
+		if expense.Currency == "" && dpa.Activity != nil && dpa.Activity.EstimatedCostCurrency != nil {
+			expense.Currency = *dpa.Activity.EstimatedCostCurrency
+		}
+	}
+
+	if expense.DayPlanID != nil && findTripDay(trip, *expense.DayPlanID) == nil {
+		return utils.NewNotFoundError("Day plan")
+	}
+	if expense.DestinationID != nil && findTripDestination(trip, *expense.DestinationID) == nil {
+		return utils.NewNotFoundError("Destination")
+	}
+
+	if expense.Title == "" {
+		return utils.NewValidationError("title is required")
+	}
+	if expense.Category == "" {
+		expense.Category = models.ExpenseCategoryOther
+	}
+	if !validExpenseCategories[expense.Category] {
+		return utils.NewValidationError("category must be one of accommodation, transportation, food, activities, shopping, other")
+	}
+
+	expense.Currency = strings.ToUpper(strings.TrimSpace(expense.Currency))
+	if !isCurrencyCode(expense.Currency) {
+		return utils.NewValidationError("currency must be a 3-letter ISO 4217 code")
+	}
+
+	if expense.PlannedAmount == nil && expense.ActualAmount == nil {
+		return utils.NewValidationError("plannedAmount or actualAmount is required")
+	}
+	if (expense.PlannedAmount != nil && *expense.PlannedAmount < 0) || (expense.ActualAmount != nil && *expense.ActualAmount < 0) {
+		return utils.NewValidationError("amounts cannot be negative")
+	}
+
+	if expense.SpentOn != nil {
+		if _, err := utils.ParseDate(*expense.SpentOn); err != nil {
+			return utils.NewValidationError("spentOn must be in YYYY-MM-DD format")
+		}
+		spentOn := utils.FormatDate(*expense.SpentOn)
+		expense.SpentOn = &spentOn
+	}
+	return nil
+}
+
+// costLedger sums planned and actual costs per currency
+type costLedger map[string]*dto.CostTotal
+
+func (l costLedger) add(currency string, planned, actual int) {
+	total, ok := l[currency]
+	if !ok {
+		total = &dto.CostTotal{Currency: currency}
+		l[currency] = total
+	}
+	total.Planned += planned
+	total.Actual += actual
+}
+
+// totals lists the ledger's sums ordered by currency
+func (l costLedger) totals() []dto.CostTotal {
+	totals := make([]dto.CostTotal, 0, len(l))
+	for _, total := range l {
+		totals = append(totals, *total)
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].Currency < totals[j].Currency })
+	return totals
+}
+
+// summarizeBudget builds the budget rollups of a trip loaded with its itinerary
+func summarizeBudget(trip *models.Trip, expenses []models.TripExpense, travelers int) dto.BudgetSummaryResponse {
+	tripLedger := costLedger{}
+	unscheduled := costLedger{}
+	dayLedgers := make(map[string]costLedger, len(trip.DayPlans))
+	categoryLedgers := map[string]costLedger{}
+	destinationLedgers := map[string]costLedger{}
+	var destinationOrder []string
+	destinations := map[string]*models.Destination{}
+
+	for _, td := range trip.TripDestinations {
+		if td.Destination != nil {
+			destinations[td.DestinationID] = td.Destination
+			destinationOrder = append(destinationOrder, td.DestinationID)
+		}
+	}
+
+	// record adds a cost line to every rollup it belongs to
+	record := func(day *models.DayPlan, destinationID *string, category, currency string, planned, actual int) {
+		tripLedger.add(currency, planned, actual)
+
+		if categoryLedgers[category] == nil {
+			categoryLedgers[category] = costLedger{}
+		}
+		categoryLedgers[category].add(currency, planned, actual)
+
+		if day != nil {
+			dayLedgers[day.ID].add(currency, planned, actual)
+		} else {
+			unscheduled.add(currency, planned, actual)
+		}
+
+		if destinationID == nil && day != nil {
+			destinationID = dayDestinationID(trip, day, destinations)
+		}
+		if destinationID != nil {
+			if destinationLedgers[*destinationID] == nil {
+				destinationLedgers[*destinationID] = costLedger{}
+				if _, listed := destinations[*destinationID]; !listed {
+					destinationOrder = append(destinationOrder, *destinationID)
+				}
+			}
+			destinationLedgers[*destinationID].add(currency, planned, actual)
+		}
+	}
+
+	byActivity := map[string][]models.TripExpense{}
+	scheduled := map[string]bool{}
+	for i := range trip.DayPlans {
+		dayLedgers[trip.DayPlans[i].ID] = costLedger{}
+		for _, dpa := range trip.DayPlans[i].DayPlanActivities {
+			scheduled[dpa.ID] = true
+		}
+	}
+	for _, expense := range expenses {
+		if expense.DayPlanActivityID != nil && scheduled[*expense.DayPlanActivityID] {
+			byActivity[*expense.DayPlanActivityID] = append(byActivity[*expense.DayPlanActivityID], expense)
+		}
+	}
+
+	activities := []dto.ActivityBudget{}
+	for i := range trip.DayPlans {
+		day := &trip.DayPlans[i]
+		for _, dpa := range day.DayPlanActivities {
+			linked := byActivity[dpa.ID]
+			activityLedger := costLedger{}
+			expenseIDs := make([]string, 0, len(linked))
+
+			plannedByExpense := false
+			for _, expense := range linked {
+				if expense.PlannedAmount != nil {
+					plannedByExpense = true
+				}
+			}
+			// Skipped activities drop out of the plan, as in the printable itinerary
+			if !plannedByExpense && !dpa.Skipped && dpa.Activity != nil && dpa.Activity.EstimatedCostAmount != nil {
+				currency := strings.ToUpper(derefString(dpa.Activity.EstimatedCostCurrency))
+				activityLedger.add(currency, *dpa.Activity.EstimatedCostAmount, 0)
+				record(day, nil, expenseCategoryFor(dpa.Activity.Type), currency, *dpa.Activity.EstimatedCostAmount, 0)
+			}
+			for _, expense := range linked {
+				planned, actual := expenseAmounts(expense)
+				activityLedger.add(expense.Currency, planned, actual)
+				record(day, expense.DestinationID, expense.Category, expense.Currency, planned, actual)
+				expenseIDs = append(expenseIDs, expense.ID)
+			}
+
+			if len(activityLedger) > 0 {
+				activities = append(activities, dto.ActivityBudget{
+					DayPlanActivityID: dpa.ID,
+					DayID:             day.ID,
+					Title:             activityTitle(dpa),
+					Totals:            activityLedger.totals(),
+					ExpenseIDs:        expenseIDs,
+				})
+			}
+		}
+	}
+
+	// Ad-hoc expenses, plus any whose activity is no longer on the trip
+	for _, expense := range expenses {
+		if expense.DayPlanActivityID != nil && scheduled[*expense.DayPlanActivityID] {
+			continue
+		}
+		var day *models.DayPlan
+		if expense.DayPlanID != nil {
+			day = findTripDay(trip, *expense.DayPlanID)
+		}
+		planned, actual := expenseAmounts(expense)
+		record(day, expense.DestinationID, expense.Category, expense.Currency, planned, actual)
+	}
+
+	summary := dto.BudgetSummaryResponse{
+		TripID:       trip.ID,
+		Totals:       tripLedger.totals(),
+		Categories:   []dto.CategoryBudget{},
+		Days:         make([]dto.DayBudget, 0, len(trip.DayPlans)),
+		Destinations: []dto.DestinationBudget{},
+		Unscheduled:  unscheduled.totals(),
+		Activities:   activities,
+	}
+
+	for _, category := range []string{
+		models.ExpenseCategoryAccommodation,
+		models.ExpenseCategoryTransportation,
+		models.ExpenseCategoryFood,
+		models.ExpenseCategoryActivities,
+		models.ExpenseCategoryShopping,
+		models.ExpenseCategoryOther,
+	} {
+		if ledger, ok := categoryLedgers[category]; ok {
+			summary.Categories = append(summary.Categories, dto.CategoryBudget{Category: category, Totals: ledger.totals()})
+		}
+	}
+
+	for _, day := range trip.DayPlans {
+		summary.Days = append(summary.Days, dto.DayBudget{
+			DayID:     day.ID,
+			DayNumber: day.DayNumber,
+			Date:      utils.FormatDate(day.Date),
+			Totals:    dayLedgers[day.ID].totals(),
+		})
+	}
+
+	for _, destinationID := range destinationOrder {
+		ledger, ok := destinationLedgers[destinationID]
+		if !ok {
+			ledger = costLedger{}
+		}
+		entry := dto.DestinationBudget{DestinationID: destinationID, Totals: ledger.totals()}
+		if destination := destinations[destinationID]; destination != nil {
+			entry.City = destination.City
+			entry.Country = destination.Country
+		}
+		summary.Destinations = append(summary.Destinations, entry)
+	}
+
+	summary.Split = travelerSplit(trip, summary.Totals, travelers)
+	return summary
+}
+
+// convertBudget folds every rollup of the summary into the currency. Amounts in currencies
+// without a rate stay separate and are listed in UnconvertedCurrencies.
+func convertBudget(summary *dto.BudgetSummaryResponse, rates *RateTable, currency, date string) {
+	missing := map[string]bool{}
+	summary.Currency = currency
+	summary.RatesDate = date
+	summary.Totals = convertTotals(summary.Totals, rates, currency, missing)
+	summary.Unscheduled = convertTotals(summary.Unscheduled, rates, currency, missing)
+	for i := range summary.Categories {
+		summary.Categories[i].Totals = convertTotals(summary.Categories[i].Totals, rates, currency, missing)
+	}
+	for i := range summary.Days {
+		summary.Days[i].Totals = convertTotals(summary.Days[i].Totals, rates, currency, missing)
+	}
+	for i := range summary.Destinations {
+		summary.Destinations[i].Totals = convertTotals(summary.Destinations[i].Totals, rates, currency, missing)
+	}
+	for i := range summary.Activities {
+		summary.Activities[i].Totals = convertTotals(summary.Activities[i].Totals, rates, currency, missing)
+	}
+	summary.Split.PerTraveler = perTravelerShares(summary.Totals, summary.Split.Travelers)
+	summary.UnconvertedCurrencies = missingCurrencies(missing)
+}
+
+// travelerSplit divides the trip totals evenly, by default between Adults plus the children in ChildrenAges
+func travelerSplit(trip *models.Trip, totals []dto.CostTotal, travelers int) dto.TravelerSplit {
+	split := dto.TravelerSplit{
+		Adults:   trip.Adults,
+		Children: childrenCount(trip.ChildrenAges),
+	}
+	split.Travelers = split.Adults + split.Children
+	if travelers > 0 {
+		split.Travelers = travelers
+	}
+	if split.Travelers < 1 {
+		split.Travelers = 1
+	}
+
+	split.PerTraveler = perTravelerShares(totals, split.Travelers)
+	return split
+}
+
+func perTravelerShares(totals []dto.CostTotal, travelers int) []dto.CostShare {
+	shares := make([]dto.CostShare, 0, len(totals))
+	for _, total := range totals {
+		shares = append(shares, dto.CostShare{
+			Currency: total.Currency,
+			Planned:  roundCents(float64(total.Planned) / float64(travelers)),
+			Actual:   roundCents(float64(total.Actual) / float64(travelers)),
+		})
+	}
+	return shares
+}
+
+// dayDestinationID picks the destination a day's costs roll up to: the day's first destination,
+// or else the trip destination whose date range covers the day
+func dayDestinationID(trip *models.Trip, day *models.DayPlan, destinations map[string]*models.Destination) *string {
+	if len(day.DayPlanDestinations) > 0 {
+		dpd := day.DayPlanDestinations[0]
+		if dpd.Destination != nil && destinations[dpd.DestinationID] == nil {
+			destinations[dpd.DestinationID] = dpd.Destination
+		}
+		return &dpd.DestinationID
+	}
+
+	date := utils.FormatDate(day.Date)
+	for _, td := range trip.TripDestinations {
+		if td.StartDate == nil || td.EndDate == nil {
+			continue
+		}
+		if date >= utils.FormatDate(*td.StartDate) && date <= utils.FormatDate(*td.EndDate) {
+			id := td.DestinationID
+			return &id
+		}
+	}
+	return nil
+}
+
+// expenseCategoryFor maps an activity type to the expense category its costs are filed under
+func expenseCategoryFor(activityType string) string {
+	switch activityType {
+	case "accommodation":
+		return models.ExpenseCategoryAccommodation
+	case "transportation":
+		return models.ExpenseCategoryTransportation
+	case "meal", "food", "restaurant":
+		return models.ExpenseCategoryFood
+	case "shopping":
+		return models.ExpenseCategoryShopping
+	default:
+		return models.ExpenseCategoryActivities
+	}
+}
+
+func expenseAmounts(expense models.TripExpense) (planned, actual int) {
+	if expense.PlannedAmount != nil {
+		planned = *expense.PlannedAmount
+	}
+	if expense.ActualAmount != nil {
+		actual = *expense.ActualAmount
+	}
+	return planned, actual
+}
+
+func findTripDay(trip *models.Trip, dayID string) *models.DayPlan {
+	for i := range trip.DayPlans {
+		if trip.DayPlans[i].ID == dayID {
+			return &trip.DayPlans[i]
+		}
+	}
+	return nil
+}
+
+func findTripActivity(trip *models.Trip, dayPlanActivityID string) *models.DayPlanActivity {
+	for i := range trip.DayPlans {
+		activities := trip.DayPlans[i].DayPlanActivities
+		for j := range activities {
+			if activities[j].ID == dayPlanActivityID {
+				return &activities[j]
+			}
+		}
+	}
+	return nil
+}
+
+// findTripDestination finds a destination on the trip or on any of its days
+func findTripDestination(trip *models.Trip, destinationID string) *models.Destination {
+	for _, td := range trip.TripDestinations {
+		if td.DestinationID == destinationID && td.Destination != nil {
+			return td.Destination
+		}
+	}
+	for _, day := range trip.DayPlans {
+		for _, dpd := range day.DayPlanDestinations {
+			if dpd.DestinationID == destinationID && dpd.Destination != nil {
+				return dpd.Destination
+			}
+		}
+	}
+	return nil
+}
+
+// activityTitle returns the title shown for a scheduled activity
+func activityTitle(dpa models.DayPlanActivity) string {
+	if title := derefString(dpa.CustomTitle); title != "" {
+		return title
+	}
+	if dpa.Activity != nil {
+		return dpa.Activity.Title
+	}
+	return ""
+}
+
+// childrenCount counts the ages in a trip's ChildrenAges JSON array
+func childrenCount(childrenAges string) int {
+	return len(childrenAgesOf(childrenAges))
+}
+
+// childrenAgesOf parses a trip's ChildrenAges JSON array, e.g. "[5,8,12]"
+func childrenAgesOf(childrenAges string) []int {
+	if strings.TrimSpace(childrenAges) == "" {
+		return nil
+	}
+	var ages []int
+	if err := json.Unmarshal([]byte(childrenAges), &ages); err != nil {
+		return nil
+	}
+	return ages
+}
+
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// emptyToNil treats an empty string as unset
+func emptyToNil(value *string) *string {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"triply-server/internal/models"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// defaultActivityMinutes is used when an activity has no DurationMinutes
+const defaultActivityMinutes = 60
+
+// timeOfDayStartHour is the local hour each TimeOfDay bucket starts at
+var timeOfDayStartHour = map[string]int{
+	"start":     9,
+	"morning":   9,
+	"mid":       13,
+	"afternoon": 13,
+	"end":       18,
+	"evening":   18,
+}
+
+// CalendarService defines the interface for iCalendar export operations
+type CalendarService interface {
+	ExportTrip(ctx context.Context, ownerID, tripID string) (string, error)
+	ExportFeed(ctx context.Context, token string) (string, error)
+	CreateFeedToken(ctx context.Context, ownerID, tripID string) (*models.TripCalendarToken, error)
+	GetFeedToken(ctx context.Context, ownerID, tripID string) (*models.TripCalendarToken, error)
+	RevokeFeedToken(ctx context.Context, ownerID, tripID string) error
+}
+
+type calendarService struct {
+	tripRepo  repository.TripRepository
+	tokenRepo repository.CalendarTokenRepository
+}
+
+// NewCalendarService creates a new calendar service instance
+func NewCalendarService(tripRepo repository.TripRepository, tokenRepo repository.CalendarTokenRepository) CalendarService {
+	return &calendarService{
+		tripRepo:  tripRepo,
+		tokenRepo: tokenRepo,
+	}
+}
+
+func (s *calendarService) ExportTrip(ctx context.Context, ownerID, tripID string) (string, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", utils.NewNotFoundError("Trip")
+		}
+		return "", err
+	}
+	return renderTripCalendar(trip, time.Now()), nil
+}
+
+func (s *calendarService) ExportFeed(ctx context.Context, token string) (string, error) {
+	calendarToken, err := s.tokenRepo.FindByToken(ctx, token)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", utils.NewNotFoundError("Calendar feed")
+		}
+		return "", err
+	}
+	if calendarToken.Trip == nil {
+		return "", utils.NewNotFoundError("Calendar feed")
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, calendarToken.TripID, calendarToken.Trip.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", utils.NewNotFoundError("Calendar feed")
+		}
+		return "", err
+	}
+	return renderTripCalendar(trip, time.Now()), nil
+}
+
+// CreateFeedToken issues a new subscription token for the trip, revoking any previous one
+func (s *calendarService) CreateFeedToken(ctx context.Context, ownerID, tripID string) (*models.TripCalendarToken, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	token := &models.TripCalendarToken{
+		ID:              utils.GenerateID("cal"),
+		TripID:          tripID,
+		Token:           utils.GenerateToken(24),
+		CreatedByUserID: ownerID,
+		CreatedAt:       time.Now(),
+	}
+	if err := s.tokenRepo.Replace(ctx, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// GetFeedToken returns the trip's feed token. The token grants read access to anyone
+// holding it, so only the owner and editors can see it.
+func (s *calendarService) GetFeedToken(ctx context.Context, ownerID, tripID string) (*models.TripCalendarToken, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	token, err := s.tokenRepo.FindByTripID(ctx, tripID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Calendar feed")
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *calendarService) RevokeFeedToken(ctx context.Context, ownerID, tripID string) error {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return err
+	}
+	return s.tokenRepo.DeleteByTripID(ctx, tripID)
+}
+
+// renderTripCalendar renders each day as an all-day event and each activity as a timed event.
+// Times are resolved in the day's destination timezone and written in UTC, so no VTIMEZONE
+// definitions are needed.
+func renderTripCalendar(trip *models.Trip, now time.Time) string {
+	var w utils.ICalWriter
+	w.Line("BEGIN", "VCALENDAR")
+	w.Line("VERSION", "2.0")
+	w.Line("PRODID", "-//Triply//Trip Calendar//EN")
+	w.Line("CALSCALE", "GREGORIAN")
+	w.Line("METHOD", "PUBLISH")
+	w.Text("X-WR-CALNAME", trip.Name)
+	w.Line("REFRESH-INTERVAL;VALUE=DURATION", "PT1H")
+	w.Line("X-PUBLISHED-TTL", "PT1H")
+
+	for i := range trip.DayPlans {
+		day := &trip.DayPlans[i]
+		date, err := utils.ParseDate(day.Date)
+		if err != nil {
+			continue
+		}
+		loc := dayLocation(trip, day)
+
+		w.Line("BEGIN", "VEVENT")
+		w.Line("UID", day.ID+"@triply")
+		w.Time("DTSTAMP", now)
+		w.Line("DTSTART;VALUE=DATE", date.Format(utils.ICalDateLayout))
+		w.Line("DTEND;VALUE=DATE", date.AddDate(0, 0, 1).Format(utils.ICalDateLayout))
+		w.Text("SUMMARY", dayTitle(day))
+		if day.Notes != nil {
+			w.Text("DESCRIPTION", *day.Notes)
+		}
+		w.Line("TRANSP", "TRANSPARENT")
+		w.Time("LAST-MODIFIED", day.UpdatedAt)
+		w.Line("END", "VEVENT")
+
+		// Activities in the same bucket run back to back from the bucket's start hour
+		cursors := make(map[string]time.Time)
+		for j := range day.DayPlanActivities {
+			dpa := &day.DayPlanActivities[j]
+
+			minutes := defaultActivityMinutes
+			if dpa.Activity != nil && dpa.Activity.DurationMinutes != nil && *dpa.Activity.DurationMinutes > 0 {
+				minutes = *dpa.Activity.DurationMinutes
+			}
+
+			var start time.Time
+			if dpa.CustomTime != nil {
+				start = *dpa.CustomTime
+			} else {
+				cursor, ok := cursors[dpa.TimeOfDay]
+				if !ok {
+					hour, known := timeOfDayStartHour[dpa.TimeOfDay]
+					if !known {
+						hour = timeOfDayStartHour["start"]
+					}
+					cursor = time.Date(date.Year(), date.Month(), date.Day(), hour, 0, 0, 0, loc)
+				}
+				start = cursor
+				cursors[dpa.TimeOfDay] = cursor.Add(time.Duration(minutes) * time.Minute)
+			}
+
+			writeActivityEvent(&w, dpa, start, start.Add(time.Duration(minutes)*time.Minute), now)
+		}
+	}
+
+	w.Line("END", "VCALENDAR")
+	return w.String()
+}
+
+func writeActivityEvent(w *utils.ICalWriter, dpa *models.DayPlanActivity, start, end, now time.Time) {
+	w.Line("BEGIN", "VEVENT")
+	w.Line("UID", dpa.ID+"@triply")
+	w.Time("DTSTAMP", now)
+	w.Time("DTSTART", start)
+	w.Time("DTEND", end)
+
+	title := derefString(dpa.CustomTitle)
+	description := derefString(dpa.CustomNotes)
+	if act := dpa.Activity; act != nil {
+		if title == "" {
+			title = act.Title
+		}
+		if description == "" {
+			description = derefString(act.Description)
+		}
+		location := derefString(act.Address)
+		if location == "" {
+			location = derefString(act.Location)
+		}
+		w.Text("LOCATION", location)
+		if act.Latitude != nil && act.Longitude != nil {
+			w.Line("GEO", strconv.FormatFloat(*act.Latitude, 'f', -1, 64)+";"+strconv.FormatFloat(*act.Longitude, 'f', -1, 64))
+		}
+		if act.URL != nil && *act.URL != "" {
+			w.Line("URL", *act.URL)
+		}
+		w.Text("CATEGORIES", act.Type)
+	}
+	w.Text("SUMMARY", title)
+	w.Text("DESCRIPTION", description)
+
+	if dpa.Skipped {
+		w.Line("STATUS", "CANCELLED")
+	} else {
+		w.Line("STATUS", "CONFIRMED")
+	}
+	w.Time("LAST-MODIFIED", dpa.UpdatedAt)
+	w.Line("END", "VEVENT")
+}
+
+// dayLocation returns the timezone of the day's first destination, falling back to the
+// trip's first destination and then UTC
+func dayLocation(trip *models.Trip, day *models.DayPlan) *time.Location {
+	var candidates []*models.Destination
+	for _, dpd := range day.DayPlanDestinations {
+		candidates = append(candidates, dpd.Destination)
+	}
+	for _, td := range trip.TripDestinations {
+		candidates = append(candidates, td.Destination)
+	}
+
+	for _, dest := range candidates {
+		if dest == nil || dest.Timezone == nil || *dest.Timezone == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(*dest.Timezone); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+func dayTitle(day *models.DayPlan) string {
+	title := fmt.Sprintf("Day %d", day.DayNumber)
+	for _, dpd := range day.DayPlanDestinations {
+		if dpd.Destination != nil {
+			title += " · " + dpd.Destination.City
+			break
+		}
+	}
+	return title
+}
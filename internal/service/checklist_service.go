@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// ChecklistService defines the interface for trip packing list and checklist operations
+type ChecklistService interface {
+	ListChecklists(ctx context.Context, ownerID, tripID string) ([]models.TripChecklist, error)
+	GetTemplate(ctx context.Context, ownerID, tripID string) (*dto.ChecklistTemplateResponse, error)
+	CreateChecklist(ctx context.Context, ownerID, tripID string, req *dto.CreateChecklistRequest) (*models.TripChecklist, error)
+	UpdateChecklist(ctx context.Context, ownerID, tripID, checklistID string, req *dto.UpdateChecklistRequest) (*models.TripChecklist, error)
+	DeleteChecklist(ctx context.Context, ownerID, tripID, checklistID string) error
+	AddItem(ctx context.Context, ownerID, tripID, checklistID string, req *dto.CreateChecklistItemRequest) (*models.ChecklistItem, error)
+	UpdateItem(ctx context.Context, ownerID, tripID, checklistID, itemID string, req *dto.UpdateChecklistItemRequest) (*models.ChecklistItem, error)
+	DeleteItem(ctx context.Context, ownerID, tripID, checklistID, itemID string) error
+}
+
+type checklistService struct {
+	tripRepo      repository.TripRepository
+	checklistRepo repository.ChecklistRepository
+	events        realtime.Publisher
+}
+
+// NewChecklistService creates a new checklist service instance
+func NewChecklistService(tripRepo repository.TripRepository, checklistRepo repository.ChecklistRepository, events realtime.Publisher) ChecklistService {
+	return &checklistService{
+		tripRepo:      tripRepo,
+		checklistRepo: checklistRepo,
+		events:        events,
+	}
+}
+
+func (s *checklistService) ListChecklists(ctx context.Context, ownerID, tripID string) ([]models.TripChecklist, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.checklistRepo.FindByTripID(ctx, tripID)
+}
+
+// GetTemplate previews the packing list generated for the trip without saving it
+func (s *checklistService) GetTemplate(ctx context.Context, ownerID, tripID string) (*dto.ChecklistTemplateResponse, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+
+	return &dto.ChecklistTemplateResponse{
+		Title: "Packing list",
+		Kind:  models.ChecklistKindPacking,
+		Items: buildPackingTemplate(trip),
+	}, nil
+}
+
+func (s *checklistService) CreateChecklist(ctx context.Context, ownerID, tripID string, req *dto.CreateChecklistRequest) (*models.TripChecklist, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	kind := req.Kind
+	if kind == "" {
+		kind = models.ChecklistKindPacking
+	}
+	if kind != models.ChecklistKindPacking && kind != models.ChecklistKindTodo {
+		return nil, utils.NewValidationError("kind must be packing or todo")
+	}
+
+	items := req.Items
+	if req.FromTemplate {
+		trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, buildPackingTemplate(trip)...)
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		if req.FromTemplate || kind == models.ChecklistKindPacking {
+			title = "Packing list"
+		} else {
+			title = "To do"
+		}
+	}
+
+	existing, err := s.checklistRepo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	checklist := &models.TripChecklist{
+		ID:              utils.GenerateID("chk"),
+		TripID:          tripID,
+		Title:           title,
+		Kind:            kind,
+		OrderIndex:      len(existing),
+		CreatedByUserID: ownerID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	for i := range items {
+		item, err := s.newItem(ctx, tripID, checklist.ID, &items[i], i)
+		if err != nil {
+			return nil, err
+		}
+		checklist.Items = append(checklist.Items, *item)
+	}
+
+	if err := s.checklistRepo.Create(ctx, checklist); err != nil {
+		return nil, err
+	}
+	return s.publishChecklist(ctx, ownerID, tripID, checklist.ID, realtime.EventChecklistAdded)
+}
+
+func (s *checklistService) UpdateChecklist(ctx context.Context, ownerID, tripID, checklistID string, req *dto.UpdateChecklistRequest) (*models.TripChecklist, error) {
+	checklist, err := s.findEditableChecklist(ctx, ownerID, tripID, checklistID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, utils.NewValidationError("title is required")
+		}
+		checklist.Title = title
+	}
+	if req.OrderIndex != nil {
+		checklist.OrderIndex = *req.OrderIndex
+	}
+	checklist.UpdatedAt = time.Now()
+
+	if err := s.checklistRepo.Update(ctx, checklist); err != nil {
+		return nil, err
+	}
+	return s.publishChecklist(ctx, ownerID, tripID, checklistID, realtime.EventChecklistUpdated)
+}
+
+func (s *checklistService) DeleteChecklist(ctx context.Context, ownerID, tripID, checklistID string) error {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return err
+	}
+
+	if err := s.checklistRepo.Delete(ctx, tripID, checklistID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Checklist")
+		}
+		return err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventChecklistRemoved, dto.ChecklistChangeEvent{
+		ChecklistID: checklistID,
+	})
+	return nil
+}
+
+// AddItem adds an item to the checklist, at the end unless an order index is given
+func (s *checklistService) AddItem(ctx context.Context, ownerID, tripID, checklistID string, req *dto.CreateChecklistItemRequest) (*models.ChecklistItem, error) {
+	checklist, err := s.findEditableChecklist(ctx, ownerID, tripID, checklistID)
+	if err != nil {
+		return nil, err
+	}
+
+	order := 0
+	for _, item := range checklist.Items {
+		if item.OrderIndex >= order {
+			order = item.OrderIndex + 1
+		}
+	}
+	item, err := s.newItem(ctx, tripID, checklistID, req, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checklistRepo.CreateItem(ctx, item); err != nil {
+		return nil, err
+	}
+	if _, err := s.publishChecklist(ctx, ownerID, tripID, checklistID, realtime.EventChecklistUpdated); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *checklistService) UpdateItem(ctx context.Context, ownerID, tripID, checklistID, itemID string, req *dto.UpdateChecklistItemRequest) (*models.ChecklistItem, error) {
+	if _, err := s.findEditableChecklist(ctx, ownerID, tripID, checklistID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.checklistRepo.FindItem(ctx, checklistID, itemID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Checklist item")
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if title == "" {
+			return nil, utils.NewValidationError("title is required")
+		}
+		item.Title = title
+	}
+	if req.Category != nil {
+		item.Category = emptyToNil(req.Category)
+	}
+	if req.Quantity != nil {
+		if *req.Quantity < 1 {
+			return nil, utils.NewValidationError("quantity must be at least 1")
+		}
+		item.Quantity = *req.Quantity
+	}
+	if req.Notes != nil {
+		item.Notes = req.Notes
+	}
+	if req.AssigneeUserID != nil {
+		item.AssigneeUserID = emptyToNil(req.AssigneeUserID)
+		if err := s.validateAssignee(ctx, tripID, item.AssigneeUserID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Assignee != nil {
+		item.Assignee = emptyToNil(req.Assignee)
+	}
+	if req.Packed != nil && *req.Packed != item.Packed {
+		item.Packed = *req.Packed
+		if item.Packed {
+			item.PackedAt = &now
+		} else {
+			item.PackedAt = nil
+		}
+	}
+	if req.OrderIndex != nil {
+		item.OrderIndex = *req.OrderIndex
+	}
+	item.UpdatedAt = now
+
+	if err := s.checklistRepo.UpdateItem(ctx, item); err != nil {
+		return nil, err
+	}
+	if _, err := s.publishChecklist(ctx, ownerID, tripID, checklistID, realtime.EventChecklistUpdated); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (s *checklistService) DeleteItem(ctx context.Context, ownerID, tripID, checklistID, itemID string) error {
+	if _, err := s.findEditableChecklist(ctx, ownerID, tripID, checklistID); err != nil {
+		return err
+	}
+
+	if err := s.checklistRepo.DeleteItem(ctx, checklistID, itemID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Checklist item")
+		}
+		return err
+	}
+	_, err := s.publishChecklist(ctx, ownerID, tripID, checklistID, realtime.EventChecklistUpdated)
+	return err
+}
+
+func (s *checklistService) findEditableChecklist(ctx context.Context, ownerID, tripID, checklistID string) (*models.TripChecklist, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	checklist, err := s.checklistRepo.FindByID(ctx, tripID, checklistID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Checklist")
+		}
+		return nil, err
+	}
+	return checklist, nil
+}
+
+// newItem validates an item request and builds the item, placed at order unless the request sets one
+func (s *checklistService) newItem(ctx context.Context, tripID, checklistID string, req *dto.CreateChecklistItemRequest, order int) (*models.ChecklistItem, error) {
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return nil, utils.NewValidationError("item title is required")
+	}
+	quantity := 1
+	if req.Quantity != nil {
+		if *req.Quantity < 1 {
+			return nil, utils.NewValidationError("quantity must be at least 1")
+		}
+		quantity = *req.Quantity
+	}
+	if req.OrderIndex != nil {
+		order = *req.OrderIndex
+	}
+
+	assigneeUserID := emptyToNil(req.AssigneeUserID)
+	if err := s.validateAssignee(ctx, tripID, assigneeUserID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &models.ChecklistItem{
+		ID:             utils.GenerateID("chi"),
+		ChecklistID:    checklistID,
+		Title:          title,
+		Category:       emptyToNil(req.Category),
+		Quantity:       quantity,
+		Notes:          req.Notes,
+		OrderIndex:     order,
+		AssigneeUserID: assigneeUserID,
+		Assignee:       emptyToNil(req.Assignee),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// validateAssignee checks that an assigned user is the trip's owner or one of its members
+func (s *checklistService) validateAssignee(ctx context.Context, tripID string, userID *string) error {
+	if userID == nil {
+		return nil
+	}
+	if _, err := s.tripRepo.FindRole(ctx, tripID, *userID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewValidationError("assigneeUserId must be a member of the trip")
+		}
+		return err
+	}
+	return nil
+}
+
+// publishChecklist reloads the checklist and publishes it to the trip's subscribers
+func (s *checklistService) publishChecklist(ctx context.Context, ownerID, tripID, checklistID, eventType string) (*models.TripChecklist, error) {
+	checklist, err := s.checklistRepo.FindByID(ctx, tripID, checklistID)
+	if err != nil {
+		return nil, err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, eventType, dto.ChecklistChangeEvent{
+		ChecklistID: checklistID,
+		Checklist:   checklist,
+	})
+	return checklist, nil
+}
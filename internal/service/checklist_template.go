@@ -0,0 +1,249 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/utils"
+)
+
+// Checklist item categories, in the order generated packing lists show them
+var checklistCategories = []string{"documents", "clothing", "toiletries", "health", "electronics", "kids", "gear"}
+
+// plugTypesByCountry maps destination countries to the socket types in use there
+var plugTypesByCountry = map[string]string{
+	"australia":      "I",
+	"austria":        "C/F",
+	"brazil":         "C/N",
+	"canada":         "A/B",
+	"china":          "A/C/I",
+	"france":         "C/E",
+	"germany":        "C/F",
+	"greece":         "C/F",
+	"india":          "C/D/M",
+	"ireland":        "G",
+	"israel":         "C/H",
+	"italy":          "C/F/L",
+	"japan":          "A/B",
+	"mexico":         "A/B",
+	"netherlands":    "C/F",
+	"new zealand":    "I",
+	"portugal":       "C/F",
+	"south africa":   "M/N",
+	"spain":          "C/F",
+	"switzerland":    "C/J",
+	"thailand":       "A/B/C/O",
+	"united kingdom": "G",
+	"uk":             "G",
+	"united states":  "A/B",
+	"usa":            "A/B",
+}
+
+// checklistTemplate accumulates generated items, keeping the first of any duplicate titles
+type checklistTemplate struct {
+	items []dto.CreateChecklistItemRequest
+	seen  map[string]bool
+}
+
+func (t *checklistTemplate) add(category, title string, quantity int, assignee, notes string) {
+	if t.seen[title] {
+		return
+	}
+	t.seen[title] = true
+
+	item := dto.CreateChecklistItemRequest{
+		Title:    title,
+		Category: &category,
+		Quantity: &quantity,
+	}
+	if assignee != "" {
+		item.Assignee = &assignee
+	}
+	if notes != "" {
+		item.Notes = &notes
+	}
+	t.items = append(t.items, item)
+}
+
+// buildPackingTemplate suggests a packing list from the trip's travelers, length,
+// destination countries and the types of its activities
+func buildPackingTemplate(trip *models.Trip) []dto.CreateChecklistItemRequest {
+	t := &checklistTemplate{seen: map[string]bool{}}
+
+	adults := trip.Adults
+	if adults < 1 {
+		adults = 1
+	}
+	childAges := childrenAgesOf(trip.ChildrenAges)
+	travelers := adults + len(childAges)
+	days := tripLengthDays(trip)
+
+	// Documents and money
+	t.add("documents", "Passports / ID cards", travelers, "", "")
+	t.add("documents", "Travel insurance details", 1, "", "")
+	t.add("documents", "Credit cards and some local cash", 1, "", "")
+
+	// Clothing, scaled to the trip length (laundry after a week)
+	perTraveler := func(category, title string, n int) {
+		t.add(category, title, n*travelers, "", fmt.Sprintf("%d per traveler", n))
+	}
+	perTraveler("clothing", "Underwear", clamp(days+1, 1, 8))
+	perTraveler("clothing", "Socks", clamp(days+1, 1, 8))
+	perTraveler("clothing", "T-shirts / tops", clamp(days, 1, 7))
+	perTraveler("clothing", "Pants / shorts", clamp((days+2)/3, 1, 4))
+	t.add("clothing", "Sleepwear", travelers, "", "")
+	t.add("clothing", "Comfortable walking shoes", travelers, "", "")
+	t.add("clothing", "Light jacket or sweater", travelers, "", "")
+	if days > 7 {
+		t.add("clothing", "Laundry bag and detergent sheets", 1, "", "")
+	}
+
+	// Toiletries and health
+	t.add("toiletries", "Toothbrushes and toothpaste", travelers, "", "")
+	t.add("toiletries", "Deodorant", adults, "", "")
+	t.add("toiletries", "Sunscreen", 1, "", "")
+	t.add("toiletries", "Travel-size shampoo and soap", 1, "", "")
+	t.add("health", "Personal medications", 1, "", "")
+	t.add("health", "Hand sanitizer", 1, "", "")
+
+	// Electronics, with an adapter for each socket type at the destinations
+	t.add("electronics", "Phone chargers", adults, "", "")
+	t.add("electronics", "Power bank", 1, "", "")
+	for _, adapter := range tripPlugAdapters(trip) {
+		t.add("electronics", "Travel adapter (type "+adapter.plugTypes+")", 1, "", "For "+strings.Join(adapter.countries, ", "))
+	}
+
+	// Children, by age
+	for i, age := range childAges {
+		child := fmt.Sprintf("Child %d (age %d)", i+1, age)
+		switch {
+		case age < 3:
+			t.add("kids", "Diapers", clamp(days*6, 6, 60), child, "")
+			t.add("kids", "Baby wipes", 1, child, "")
+			t.add("kids", "Stroller or baby carrier", 1, child, "")
+			t.add("kids", "Baby food, bottles and bib", 1, child, "")
+		case age <= 12:
+			t.add("kids", "Toys, books or tablet for the journey", 1, child, "")
+			t.add("kids", "Kids' snacks", 1, child, "")
+		}
+		if age < 8 && tripHasActivityType(trip, "transportation") {
+			t.add("kids", "Car seat or booster", 1, child, "Check whether rentals and transfers provide one")
+		}
+	}
+	if len(childAges) > 0 {
+		t.add("health", "First aid kit", 1, "", "")
+		t.add("health", "Children's fever and pain relief", 1, "", "")
+	}
+
+	// Traveler type
+	switch trip.TravelerType {
+	case string(models.TravelerTypeSolo):
+		t.add("gear", "Padlock for hostel lockers", 1, "", "")
+		t.add("documents", "Copies of documents stored online", 1, "", "")
+	case string(models.TravelerTypeFamily):
+		t.add("health", "First aid kit", 1, "", "")
+		t.add("gear", "Snacks and wet wipes for the road", 1, "", "")
+	case string(models.TravelerTypeFriends):
+		t.add("gear", "Card or party games", 1, "", "")
+		t.add("gear", "Portable speaker", 1, "", "")
+	}
+
+	// Activities
+	if tripHasActivityType(trip, "accommodation") {
+		t.add("documents", "Hotel booking confirmations", 1, "", "")
+	}
+	if tripHasActivityType(trip, "transportation") {
+		t.add("documents", "Tickets and boarding passes", 1, "", "")
+		t.add("gear", "Neck pillow", adults, "", "")
+		t.add("health", "Motion sickness tablets", 1, "", "")
+	}
+	if tripHasActivityType(trip, "beach") {
+		t.add("clothing", "Swimsuits", travelers, "", "")
+		t.add("gear", "Beach towels", travelers, "", "")
+	}
+	if tripHasActivityType(trip, "nature") || tripHasActivityType(trip, "hiking") {
+		t.add("clothing", "Hiking shoes", travelers, "", "")
+		t.add("gear", "Reusable water bottles", travelers, "", "")
+		t.add("gear", "Day backpack", 1, "", "")
+	}
+
+	// Group by category, keeping the generated order within each
+	rank := make(map[string]int, len(checklistCategories))
+	for i, category := range checklistCategories {
+		rank[category] = i
+	}
+	sort.SliceStable(t.items, func(i, j int) bool {
+		return rank[*t.items[i].Category] < rank[*t.items[j].Category]
+	})
+	for i := range t.items {
+		order := i
+		t.items[i].OrderIndex = &order
+	}
+	return t.items
+}
+
+type plugAdapter struct {
+	plugTypes string
+	countries []string
+}
+
+// tripPlugAdapters lists the socket types of the trip's destination countries
+func tripPlugAdapters(trip *models.Trip) []plugAdapter {
+	var adapters []plugAdapter
+	index := map[string]int{}
+	seen := map[string]bool{}
+	addCountry := func(country string) {
+		key := strings.ToLower(strings.TrimSpace(country))
+		plugTypes, ok := plugTypesByCountry[key]
+		if !ok || seen[key] {
+			return
+		}
+		seen[key] = true
+		if i, ok := index[plugTypes]; ok {
+			adapters[i].countries = append(adapters[i].countries, country)
+			return
+		}
+		index[plugTypes] = len(adapters)
+		adapters = append(adapters, plugAdapter{plugTypes: plugTypes, countries: []string{country}})
+	}
+
+	for _, td := range trip.TripDestinations {
+		if td.Destination != nil {
+			addCountry(td.Destination.Country)
+		}
+	}
+	for _, day := range trip.DayPlans {
+		for _, dpd := range day.DayPlanDestinations {
+			if dpd.Destination != nil {
+				addCountry(dpd.Destination.Country)
+			}
+		}
+	}
+	return adapters
+}
+
+func tripHasActivityType(trip *models.Trip, activityType string) bool {
+	for _, day := range trip.DayPlans {
+		for _, dpa := range day.DayPlanActivities {
+			if dpa.Activity != nil && dpa.Activity.Type == activityType {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tripLengthDays counts the trip's days, start and end included
+func tripLengthDays(trip *models.Trip) int {
+	start, err := utils.ParseDate(trip.StartDate)
+	if err != nil {
+		return len(trip.DayPlans)
+	}
+	end, err := utils.ParseDate(trip.EndDate)
+	if err != nil || end.Before(start) {
+		return len(trip.DayPlans)
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
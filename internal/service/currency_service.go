@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+)
+
+// CurrencyService defines the interface for exchange rates. The default implementation
+// reads a rate table loaded into the database; other rate providers can implement it instead.
+type CurrencyService interface {
+	// RatesOn returns the rates in effect on the date (YYYY-MM-DD), or the latest rates if date is empty
+	RatesOn(ctx context.Context, date string) (*RateTable, error)
+	ListRates(ctx context.Context, date string) (*dto.ExchangeRateListResponse, error)
+	LoadRates(ctx context.Context, req *dto.LoadExchangeRatesRequest) (*dto.LoadExchangeRatesResponse, error)
+}
+
+type currencyService struct {
+	rateRepo repository.ExchangeRateRepository
+}
+
+// NewCurrencyService creates a new currency service instance backed by the exchange rate table
+func NewCurrencyService(rateRepo repository.ExchangeRateRepository) CurrencyService {
+	return &currencyService{rateRepo: rateRepo}
+}
+
+func (s *currencyService) RatesOn(ctx context.Context, date string) (*RateTable, error) {
+	rates, err := s.findEffective(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	return NewRateTable(rates), nil
+}
+
+func (s *currencyService) ListRates(ctx context.Context, date string) (*dto.ExchangeRateListResponse, error) {
+	rates, err := s.findEffective(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ExchangeRateListResponse{
+		Date:  date,
+		Rates: make([]dto.ExchangeRateResponse, len(rates)),
+	}
+	for i, rate := range rates {
+		response.Rates[i] = dto.ExchangeRateResponse{
+			Base:          rate.BaseCurrency,
+			Quote:         rate.QuoteCurrency,
+			Rate:          rate.Rate,
+			EffectiveDate: utils.FormatDate(rate.EffectiveDate),
+			Source:        rate.Source,
+		}
+	}
+	return response, nil
+}
+
+// LoadRates validates and stores rate tables, replacing rates already loaded for the same pair and date
+func (s *currencyService) LoadRates(ctx context.Context, req *dto.LoadExchangeRatesRequest) (*dto.LoadExchangeRatesResponse, error) {
+	tables := req.Tables
+	if req.Base != "" || len(req.Rates) > 0 {
+		tables = append([]dto.ExchangeRateTable{req.ExchangeRateTable}, tables...)
+	}
+	if len(tables) == 0 {
+		return nil, utils.NewValidationError("at least one rate table is required")
+	}
+
+	now := time.Now()
+	var rates []models.ExchangeRate
+	// A pair can be stored once per date; the upsert rejects a batch that repeats one
+	seen := make(map[string]bool)
+	for _, table := range tables {
+		base := strings.ToUpper(strings.TrimSpace(table.Base))
+		if !isCurrencyCode(base) {
+			return nil, utils.NewValidationError("base must be a 3-letter ISO 4217 code")
+		}
+		date := table.Date
+		if date == "" {
+			date = now.Format(utils.DateLayout)
+		} else if _, err := utils.ParseDate(date); err != nil {
+			return nil, utils.NewValidationError("date must be in YYYY-MM-DD format")
+		}
+		if len(table.Rates) == 0 {
+			return nil, utils.NewValidationError("rates are required for base " + base)
+		}
+
+		for code, rate := range table.Rates {
+			quote := strings.ToUpper(strings.TrimSpace(code))
+			if !isCurrencyCode(quote) {
+				return nil, utils.NewValidationError("rate currency " + code + " must be a 3-letter ISO 4217 code")
+			}
+			if quote == base {
+				continue
+			}
+			if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+				return nil, utils.NewValidationError("rate for " + quote + " must be a positive number")
+			}
+			key := base + "/" + quote + "/" + utils.FormatDate(date)
+			if seen[key] {
+				return nil, utils.NewValidationError("the rate for " + base + " to " + quote + " on " + utils.FormatDate(date) + " is given more than once")
+			}
+			seen[key] = true
+			rates = append(rates, models.ExchangeRate{
+				ID:            utils.GenerateID("fx"),
+				BaseCurrency:  base,
+				QuoteCurrency: quote,
+				EffectiveDate: utils.FormatDate(date),
+				Rate:          rate,
+				Source:        table.Source,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			})
+		}
+	}
+
+	if err := s.rateRepo.Upsert(ctx, rates); err != nil {
+		return nil, err
+	}
+	return &dto.LoadExchangeRatesResponse{Loaded: len(rates)}, nil
+}
+
+func (s *currencyService) findEffective(ctx context.Context, date string) ([]models.ExchangeRate, error) {
+	if date != "" {
+		if _, err := utils.ParseDate(date); err != nil {
+			return nil, utils.NewValidationError("date must be in YYYY-MM-DD format")
+		}
+		date = utils.FormatDate(date)
+	}
+	return s.rateRepo.FindEffective(ctx, date)
+}
+
+// RateTable converts amounts between currencies using the rates in effect on one date
+type RateTable struct {
+	rates map[string]map[string]float64 // base -> quote -> rate
+}
+
+// NewRateTable indexes a set of rates for conversion
+func NewRateTable(rates []models.ExchangeRate) *RateTable {
+	table := &RateTable{rates: make(map[string]map[string]float64)}
+	for _, rate := range rates {
+		if table.rates[rate.BaseCurrency] == nil {
+			table.rates[rate.BaseCurrency] = make(map[string]float64)
+		}
+		table.rates[rate.BaseCurrency][rate.QuoteCurrency] = rate.Rate
+	}
+	return table
+}
+
+// Rate returns how many units of to one unit of from buys: directly, through the inverse
+// pair, or crossed through a base currency both are quoted against
+func (t *RateTable) Rate(from, to string) (float64, bool) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, true
+	}
+	if rate, ok := t.rates[from][to]; ok {
+		return rate, true
+	}
+	if rate, ok := t.rates[to][from]; ok {
+		return 1 / rate, true
+	}
+
+	bases := make([]string, 0, len(t.rates))
+	for base := range t.rates {
+		bases = append(bases, base)
+	}
+	sort.Strings(bases)
+	for _, base := range bases {
+		fromRate, fromOK := t.rates[base][from]
+		toRate, toOK := t.rates[base][to]
+		if fromOK && toOK {
+			return toRate / fromRate, true
+		}
+	}
+	return 0, false
+}
+
+// Convert converts an amount, reporting false when no rate connects the two currencies
+func (t *RateTable) Convert(amount float64, from, to string) (float64, bool) {
+	rate, ok := t.Rate(from, to)
+	if !ok {
+		return 0, false
+	}
+	return amount * rate, true
+}
+
+// costConverter returns a converter into the currency that leaves amounts it cannot
+// convert untouched and records their currencies in missing
+func (t *RateTable) costConverter(currency string, missing map[string]bool) costConverter {
+	return func(amount int, from string) (int, string) {
+		converted, ok := t.Convert(float64(amount), from, currency)
+		if !ok {
+			missing[from] = true
+			return amount, from
+		}
+		return int(math.Round(converted)), currency
+	}
+}
+
+// costConverter converts an amount into a display currency, returning the amount and its currency
+type costConverter func(amount int, currency string) (int, string)
+
+// convertTotals folds per-currency totals into one total in the currency. Totals in
+// currencies without a rate are kept as they are and recorded in missing.
+func convertTotals(totals []dto.CostTotal, rates *RateTable, currency string, missing map[string]bool) []dto.CostTotal {
+	var planned, actual float64
+	converted := false
+	var kept []dto.CostTotal
+	for _, total := range totals {
+		rate, ok := rates.Rate(total.Currency, currency)
+		if !ok {
+			missing[total.Currency] = true
+			kept = append(kept, total)
+			continue
+		}
+		planned += float64(total.Planned) * rate
+		actual += float64(total.Actual) * rate
+		converted = true
+	}
+
+	result := make([]dto.CostTotal, 0, len(kept)+1)
+	if converted {
+		result = append(result, dto.CostTotal{
+			Currency: currency,
+			Planned:  int(math.Round(planned)),
+			Actual:   int(math.Round(actual)),
+		})
+	}
+	return append(result, kept...)
+}
+
+// missingCurrencies lists the recorded currencies in order, leaving out costs without a currency
+func missingCurrencies(missing map[string]bool) []string {
+	currencies := make([]string, 0, len(missing))
+	for currency := range missing {
+		if currency != "" {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// normalizeCurrencyQuery validates an optional requested display currency
+func normalizeCurrencyQuery(currency string) (string, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency != "" && !isCurrencyCode(currency) {
+		return "", utils.NewValidationError("currency must be a 3-letter ISO 4217 code")
+	}
+	return currency, nil
+}
@@ -0,0 +1,760 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// validTimesOfDay lists the time buckets a day plan activity can be placed in
+var validTimesOfDay = map[string]bool{
+	"start": true, "mid": true, "end": true,
+	"morning": true, "afternoon": true, "evening": true,
+}
+
+// validPartsOfDay lists the values accepted for DayPlanDestination.PartOfDay
+var validPartsOfDay = map[string]bool{
+	"morning": true, "afternoon": true, "evening": true, "all-day": true,
+}
+
+// DayPlanService defines the interface for granular day plan operations
+type DayPlanService interface {
+	CreateDayPlan(ctx context.Context, ownerID, tripID string, req *dto.CreateDayPlanRequest) (*models.DayPlan, error)
+	UpdateDayPlan(ctx context.Context, ownerID, tripID, dayID string, req *dto.UpdateDayPlanRequest) (*models.DayPlan, error)
+	DeleteDayPlan(ctx context.Context, ownerID, tripID, dayID string) error
+
+	AddActivity(ctx context.Context, ownerID, tripID, dayID string, req *dto.CreateDayPlanActivityRequest) (*models.DayPlan, error)
+	UpdateActivity(ctx context.Context, ownerID, tripID, dayID, dayPlanActivityID string, req *dto.UpdateDayPlanActivityRequest) (*models.DayPlan, error)
+	MoveActivity(ctx context.Context, ownerID, tripID, dayID, dayPlanActivityID string, req *dto.MoveDayPlanActivityRequest) ([]models.DayPlan, error)
+	RemoveActivity(ctx context.Context, ownerID, tripID, dayID, dayPlanActivityID string) (*models.DayPlan, error)
+
+	AddDestination(ctx context.Context, ownerID, tripID, dayID string, req *dto.CreateDayPlanDestinationRequest) (*models.DayPlan, error)
+	UpdateDestination(ctx context.Context, ownerID, tripID, dayID, dayPlanDestinationID string, req *dto.UpdateDayPlanDestinationRequest) (*models.DayPlan, error)
+	RemoveDestination(ctx context.Context, ownerID, tripID, dayID, dayPlanDestinationID string) (*models.DayPlan, error)
+}
+
+type dayPlanService struct {
+	tripRepo    repository.TripRepository
+	dayPlanRepo repository.DayPlanRepository
+	events      realtime.Publisher
+}
+
+// NewDayPlanService creates a new day plan service instance
+func NewDayPlanService(tripRepo repository.TripRepository, dayPlanRepo repository.DayPlanRepository, events realtime.Publisher) DayPlanService {
+	return &dayPlanService{
+		tripRepo:    tripRepo,
+		dayPlanRepo: dayPlanRepo,
+		events:      events,
+	}
+}
+
+func (s *dayPlanService) CreateDayPlan(ctx context.Context, ownerID, tripID string, req *dto.CreateDayPlanRequest) (*models.DayPlan, error) {
+	trip, err := s.findEditableTrip(ctx, ownerID, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := s.dayPlanRepo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	position := len(days) + 1
+	if req.DayNumber != nil {
+		position = clamp(*req.DayNumber, 1, len(days)+1)
+	}
+
+	date := req.Date
+	if date == "" {
+		if position > 1 {
+			date = utils.AddDays(days[position-2].Date, 1)
+		} else {
+			date = utils.FormatDate(trip.StartDate)
+		}
+	} else if _, err := utils.ParseDate(date); err != nil {
+		return nil, utils.NewValidationError("date must be in YYYY-MM-DD format")
+	}
+
+	now := time.Now()
+	dayPlan := &models.DayPlan{
+		ID:        utils.GenerateID("day"),
+		TripID:    tripID,
+		Date:      utils.FormatDate(date),
+		DayNumber: position,
+		Notes:     req.Notes,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	ordered := make([]models.DayPlan, 0, len(days)+1)
+	ordered = append(ordered, days[:position-1]...)
+	ordered = append(ordered, *dayPlan)
+	ordered = append(ordered, days[position-1:]...)
+	renumbered := renumberDays(ordered, now)
+
+	if err := s.dayPlanRepo.Create(ctx, dayPlan, renumbered, ownerID); err != nil {
+		return nil, err
+	}
+
+	created, err := s.dayPlanRepo.FindByID(ctx, tripID, dayPlan.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.localizeDays(ctx, tripID, created); err != nil {
+		return nil, err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventDayAdded, dto.DayChangeEvent{
+		DayID:      created.ID,
+		Day:        created,
+		DayNumbers: dayNumbers(renumbered),
+	})
+	return created, nil
+}
+
+func (s *dayPlanService) UpdateDayPlan(ctx context.Context, ownerID, tripID, dayID string, req *dto.UpdateDayPlanRequest) (*models.DayPlan, error) {
+	if _, err := s.findEditableTrip(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	dayPlan, err := s.findDay(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if req.Date != nil {
+		if _, err := utils.ParseDate(*req.Date); err != nil {
+			return nil, utils.NewValidationError("date must be in YYYY-MM-DD format")
+		}
+		dayPlan.Date = utils.FormatDate(*req.Date)
+	}
+	if req.Notes != nil {
+		dayPlan.Notes = req.Notes
+	}
+	dayPlan.UpdatedAt = now
+
+	var renumbered []models.DayPlan
+	if req.DayNumber != nil && *req.DayNumber != dayPlan.DayNumber {
+		days, err := s.dayPlanRepo.FindByTripID(ctx, tripID)
+		if err != nil {
+			return nil, err
+		}
+
+		others := make([]models.DayPlan, 0, len(days))
+		for _, day := range days {
+			if day.ID != dayPlan.ID {
+				others = append(others, day)
+			}
+		}
+
+		position := clamp(*req.DayNumber, 1, len(others)+1)
+		ordered := make([]models.DayPlan, 0, len(days))
+		ordered = append(ordered, others[:position-1]...)
+		ordered = append(ordered, *dayPlan)
+		ordered = append(ordered, others[position-1:]...)
+		renumbered = renumberDays(ordered, now)
+	}
+
+	if err := s.dayPlanRepo.Update(ctx, dayPlan, renumbered, ownerID); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.dayPlanRepo.FindByID(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.localizeDays(ctx, tripID, updated); err != nil {
+		return nil, err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventDayUpdated, dto.DayChangeEvent{
+		DayID:      dayID,
+		Day:        updated,
+		DayNumbers: dayNumbers(renumbered),
+	})
+	return updated, nil
+}
+
+func (s *dayPlanService) DeleteDayPlan(ctx context.Context, ownerID, tripID, dayID string) error {
+	if _, err := s.findEditableTrip(ctx, ownerID, tripID); err != nil {
+		return err
+	}
+
+	dayPlan, err := s.findDay(ctx, tripID, dayID)
+	if err != nil {
+		return err
+	}
+
+	days, err := s.dayPlanRepo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return err
+	}
+
+	remaining := make([]models.DayPlan, 0, len(days))
+	for _, day := range days {
+		if day.ID != dayID {
+			remaining = append(remaining, day)
+		}
+	}
+
+	renumbered := renumberDays(remaining, time.Now())
+	if err := s.dayPlanRepo.Delete(ctx, dayPlan, renumbered, ownerID); err != nil {
+		return err
+	}
+
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventDayDeleted, dto.DayChangeEvent{
+		DayID:      dayID,
+		DayNumbers: dayNumbers(renumbered),
+	})
+	return nil
+}
+
+func (s *dayPlanService) AddActivity(ctx context.Context, ownerID, tripID, dayID string, req *dto.CreateDayPlanActivityRequest) (*models.DayPlan, error) {
+	if req.ActivityID == "" {
+		return nil, utils.NewValidationError("activityId is required")
+	}
+	if !validTimesOfDay[req.TimeOfDay] {
+		return nil, utils.NewValidationError("timeOfDay must be one of start, mid, end, morning, afternoon, evening")
+	}
+
+	if _, err := s.findEditableTrip(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	dayPlan, err := s.findDay(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.dayPlanRepo.ActivityExists(ctx, req.ActivityID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.NewNotFoundError("Activity")
+	}
+
+	customTime := req.CustomTime
+	if req.LocalTime != nil && *req.LocalTime != "" {
+		zones, err := s.zoneTrip(ctx, tripID)
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseLocalTime(*req.LocalTime, dayPlan.Date, dayLocation(zones, dayPlan))
+		if err != nil {
+			return nil, err
+		}
+		customTime = &t
+	}
+
+	now := time.Now()
+	dpa := models.DayPlanActivity{
+		ID:          utils.GenerateID("dpa"),
+		DayPlanID:   dayPlan.ID,
+		ActivityID:  req.ActivityID,
+		TimeOfDay:   req.TimeOfDay,
+		CustomTitle: req.CustomTitle,
+		CustomNotes: req.CustomNotes,
+		CustomTime:  customTime,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	bucket := activitiesInBucket(dayPlan, req.TimeOfDay, "")
+	position := len(bucket)
+	if req.OrderWithinTime != nil {
+		position = *req.OrderWithinTime
+	}
+	bucket = insertActivity(bucket, dpa, position)
+	renumberActivities(bucket, dayPlan.ID, now)
+
+	// The new activity is created with its final order; the rest are repositioned
+	var positions []models.DayPlanActivity
+	for _, item := range bucket {
+		if item.ID == dpa.ID {
+			dpa.OrderWithinTime = item.OrderWithinTime
+			continue
+		}
+		positions = append(positions, item)
+	}
+
+	if err := s.dayPlanRepo.CreateActivity(ctx, tripID, &dpa, positions, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.reloadDayAndPublish(ctx, ownerID, tripID, dayID, dpa.ID, realtime.EventActivityAdded)
+}
+
+func (s *dayPlanService) UpdateActivity(ctx context.Context, ownerID, tripID, dayID, dayPlanActivityID string, req *dto.UpdateDayPlanActivityRequest) (*models.DayPlan, error) {
+	if _, err := s.findEditableTrip(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	dayPlan, err := s.findDay(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+
+	dpa := findDayPlanActivity(dayPlan, dayPlanActivityID)
+	if dpa == nil {
+		return nil, utils.NewNotFoundError("Day plan activity")
+	}
+
+	if req.CustomTitle != nil {
+		dpa.CustomTitle = req.CustomTitle
+	}
+	if req.CustomNotes != nil {
+		dpa.CustomNotes = req.CustomNotes
+	}
+	if req.CustomTime != nil {
+		dpa.CustomTime = req.CustomTime
+	}
+	if req.LocalTime != nil {
+		if *req.LocalTime == "" {
+			dpa.CustomTime = nil
+		} else {
+			zones, err := s.zoneTrip(ctx, tripID)
+			if err != nil {
+				return nil, err
+			}
+			t, err := parseLocalTime(*req.LocalTime, dayPlan.Date, dayLocation(zones, dayPlan))
+			if err != nil {
+				return nil, err
+			}
+			dpa.CustomTime = &t
+		}
+	}
+	if req.Completed != nil {
+		dpa.Completed = *req.Completed
+	}
+	if req.Skipped != nil {
+		dpa.Skipped = *req.Skipped
+	}
+	dpa.UpdatedAt = time.Now()
+
+	if err := s.dayPlanRepo.UpdateActivity(ctx, tripID, dpa, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.reloadDayAndPublish(ctx, ownerID, tripID, dayID, dpa.ID, realtime.EventActivityUpdated)
+}
+
+func (s *dayPlanService) MoveActivity(ctx context.Context, ownerID, tripID, dayID, dayPlanActivityID string, req *dto.MoveDayPlanActivityRequest) ([]models.DayPlan, error) {
+	if _, err := s.findEditableTrip(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	sourceDay, err := s.findDay(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+
+	dpa := findDayPlanActivity(sourceDay, dayPlanActivityID)
+	if dpa == nil {
+		return nil, utils.NewNotFoundError("Day plan activity")
+	}
+
+	targetDay := sourceDay
+	if req.DayID != "" && req.DayID != sourceDay.ID {
+		targetDay, err = s.findDay(ctx, tripID, req.DayID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	timeOfDay := req.TimeOfDay
+	if timeOfDay == "" {
+		timeOfDay = dpa.TimeOfDay
+	}
+	if !validTimesOfDay[timeOfDay] {
+		return nil, utils.NewValidationError("timeOfDay must be one of start, mid, end, morning, afternoon, evening")
+	}
+
+	// A timed activity keeps its local clock time on the target day, in the target day's zone
+	if dpa.CustomTime != nil && targetDay.ID != sourceDay.ID {
+		zones, err := s.zoneTrip(ctx, tripID)
+		if err != nil {
+			return nil, err
+		}
+		moved := moveLocalTime(*dpa.CustomTime, dayLocation(zones, sourceDay), targetDay.Date, dayLocation(zones, targetDay))
+		dpa.CustomTime = &moved
+	}
+
+	positions := moveActivityPositions(sourceDay, targetDay, *dpa, timeOfDay, req.OrderWithinTime, time.Now())
+
+	if err := s.dayPlanRepo.MoveActivities(ctx, tripID, positions, ownerID); err != nil {
+		return nil, err
+	}
+
+	days, err := s.reloadDays(ctx, tripID, sourceDay.ID, targetDay.ID)
+	if err != nil {
+		return nil, err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventActivityMoved, dto.DayChangeEvent{
+		DayID:  targetDay.ID,
+		ItemID: dayPlanActivityID,
+		Days:   days,
+	})
+	return days, nil
+}
+
+func (s *dayPlanService) RemoveActivity(ctx context.Context, ownerID, tripID, dayID, dayPlanActivityID string) (*models.DayPlan, error) {
+	if _, err := s.findEditableTrip(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	dayPlan, err := s.findDay(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+
+	dpa := findDayPlanActivity(dayPlan, dayPlanActivityID)
+	if dpa == nil {
+		return nil, utils.NewNotFoundError("Day plan activity")
+	}
+
+	bucket := activitiesInBucket(dayPlan, dpa.TimeOfDay, dpa.ID)
+	renumberActivities(bucket, dayPlan.ID, time.Now())
+
+	if err := s.dayPlanRepo.DeleteActivity(ctx, tripID, dpa, bucket, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.reloadDayAndPublish(ctx, ownerID, tripID, dayID, dpa.ID, realtime.EventActivityRemoved)
+}
+
+func (s *dayPlanService) AddDestination(ctx context.Context, ownerID, tripID, dayID string, req *dto.CreateDayPlanDestinationRequest) (*models.DayPlan, error) {
+	if req.DestinationID == "" {
+		return nil, utils.NewValidationError("destinationId is required")
+	}
+	if req.PartOfDay != nil && !validPartsOfDay[*req.PartOfDay] {
+		return nil, utils.NewValidationError("partOfDay must be one of morning, afternoon, evening, all-day")
+	}
+
+	if _, err := s.findEditableTrip(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	dayPlan, err := s.findDay(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dpd := range dayPlan.DayPlanDestinations {
+		if dpd.DestinationID == req.DestinationID {
+			return nil, utils.NewValidationError("destination is already part of this day")
+		}
+	}
+
+	exists, err := s.dayPlanRepo.DestinationExists(ctx, req.DestinationID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.NewNotFoundError("Destination")
+	}
+
+	dpd := models.DayPlanDestination{
+		ID:            utils.GenerateID("dpd"),
+		DayPlanID:     dayPlan.ID,
+		DestinationID: req.DestinationID,
+		PartOfDay:     req.PartOfDay,
+		CreatedAt:     time.Now(),
+	}
+
+	position := len(dayPlan.DayPlanDestinations)
+	if req.OrderIndex != nil {
+		position = *req.OrderIndex
+	}
+	ordered := insertDestination(dayPlan.DayPlanDestinations, dpd, position)
+
+	var positions []models.DayPlanDestination
+	for i := range ordered {
+		ordered[i].OrderIndex = i
+		if ordered[i].ID == dpd.ID {
+			dpd.OrderIndex = i
+			continue
+		}
+		positions = append(positions, ordered[i])
+	}
+
+	if err := s.dayPlanRepo.CreateDestination(ctx, tripID, &dpd, positions, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.reloadDayAndPublish(ctx, ownerID, tripID, dayID, dpd.ID, realtime.EventDestinationAdded)
+}
+
+func (s *dayPlanService) UpdateDestination(ctx context.Context, ownerID, tripID, dayID, dayPlanDestinationID string, req *dto.UpdateDayPlanDestinationRequest) (*models.DayPlan, error) {
+	if req.PartOfDay != nil && !validPartsOfDay[*req.PartOfDay] {
+		return nil, utils.NewValidationError("partOfDay must be one of morning, afternoon, evening, all-day")
+	}
+
+	if _, err := s.findEditableTrip(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	dayPlan, err := s.findDay(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+
+	var dpd *models.DayPlanDestination
+	others := make([]models.DayPlanDestination, 0, len(dayPlan.DayPlanDestinations))
+	for i := range dayPlan.DayPlanDestinations {
+		if dayPlan.DayPlanDestinations[i].ID == dayPlanDestinationID {
+			dpd = &dayPlan.DayPlanDestinations[i]
+			continue
+		}
+		others = append(others, dayPlan.DayPlanDestinations[i])
+	}
+	if dpd == nil {
+		return nil, utils.NewNotFoundError("Day plan destination")
+	}
+
+	if req.PartOfDay != nil {
+		dpd.PartOfDay = req.PartOfDay
+	}
+
+	var positions []models.DayPlanDestination
+	if req.OrderIndex != nil {
+		positions = insertDestination(others, *dpd, *req.OrderIndex)
+		for i := range positions {
+			positions[i].OrderIndex = i
+		}
+	}
+
+	if err := s.dayPlanRepo.UpdateDestination(ctx, tripID, dpd, positions, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.reloadDayAndPublish(ctx, ownerID, tripID, dayID, dpd.ID, realtime.EventDestinationUpdated)
+}
+
+func (s *dayPlanService) RemoveDestination(ctx context.Context, ownerID, tripID, dayID, dayPlanDestinationID string) (*models.DayPlan, error) {
+	if _, err := s.findEditableTrip(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	dayPlan, err := s.findDay(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+
+	var dpd *models.DayPlanDestination
+	remaining := make([]models.DayPlanDestination, 0, len(dayPlan.DayPlanDestinations))
+	for i := range dayPlan.DayPlanDestinations {
+		if dayPlan.DayPlanDestinations[i].ID == dayPlanDestinationID {
+			dpd = &dayPlan.DayPlanDestinations[i]
+			continue
+		}
+		remaining = append(remaining, dayPlan.DayPlanDestinations[i])
+	}
+	if dpd == nil {
+		return nil, utils.NewNotFoundError("Day plan destination")
+	}
+
+	for i := range remaining {
+		remaining[i].OrderIndex = i
+	}
+
+	if err := s.dayPlanRepo.DeleteDestination(ctx, tripID, dpd, remaining, ownerID); err != nil {
+		return nil, err
+	}
+
+	return s.reloadDayAndPublish(ctx, ownerID, tripID, dayID, dpd.ID, realtime.EventDestinationRemoved)
+}
+
+// findEditableTrip loads the trip row, returning not found unless ownerID can see it
+// and forbidden unless they own it or are an editor
+func (s *dayPlanService) findEditableTrip(ctx context.Context, ownerID, tripID string) (*models.Trip, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+	trip, err := s.tripRepo.FindBasicByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+	return trip, nil
+}
+
+func (s *dayPlanService) findDay(ctx context.Context, tripID, dayID string) (*models.DayPlan, error) {
+	dayPlan, err := s.dayPlanRepo.FindByID(ctx, tripID, dayID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Day plan")
+		}
+		return nil, err
+	}
+	return dayPlan, nil
+}
+
+// reloadDays returns the given days (deduplicated) with their current contents
+func (s *dayPlanService) reloadDays(ctx context.Context, tripID string, dayIDs ...string) ([]models.DayPlan, error) {
+	seen := make(map[string]bool)
+	var days []models.DayPlan
+	for _, id := range dayIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		day, err := s.dayPlanRepo.FindByID(ctx, tripID, id)
+		if err != nil {
+			return nil, err
+		}
+		days = append(days, *day)
+	}
+
+	localized := make([]*models.DayPlan, len(days))
+	for i := range days {
+		localized[i] = &days[i]
+	}
+	if err := s.localizeDays(ctx, tripID, localized...); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// zoneTrip returns the trip's destinations as a trip, which is all dayLocation needs to fall
+// back on for days without destinations of their own
+func (s *dayPlanService) zoneTrip(ctx context.Context, tripID string) (*models.Trip, error) {
+	destinations, err := s.tripRepo.FindDestinations(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.Trip{ID: tripID, TripDestinations: destinations}, nil
+}
+
+// localizeDays fills in the local and UTC schedule of the days' timed activities
+func (s *dayPlanService) localizeDays(ctx context.Context, tripID string, days ...*models.DayPlan) error {
+	zones, err := s.zoneTrip(ctx, tripID)
+	if err != nil {
+		return err
+	}
+	for _, day := range days {
+		localizeDay(zones, day)
+	}
+	return nil
+}
+
+// Helper functions for dense ordering
+
+// moveActivityPositions computes the new positions of every activity affected by moving dpa
+// into targetDay's timeOfDay bucket at the given position (both buckets renumbered densely)
+func moveActivityPositions(sourceDay, targetDay *models.DayPlan, dpa models.DayPlanActivity, timeOfDay string, position int, now time.Time) []models.DayPlanActivity {
+	target := activitiesInBucket(targetDay, timeOfDay, dpa.ID)
+	dpa.DayPlanID = targetDay.ID
+	dpa.TimeOfDay = timeOfDay
+	target = insertActivity(target, dpa, position)
+	renumberActivities(target, targetDay.ID, now)
+
+	// Close the gap left in the source bucket when the activity changes bucket
+	positions := target
+	original := findDayPlanActivity(sourceDay, dpa.ID)
+	if original != nil && (sourceDay.ID != targetDay.ID || original.TimeOfDay != timeOfDay) {
+		source := activitiesInBucket(sourceDay, original.TimeOfDay, dpa.ID)
+		renumberActivities(source, sourceDay.ID, now)
+		positions = append(positions, source...)
+	}
+
+	return positions
+}
+
+// activitiesInBucket returns a day's activities in one time bucket ordered by position,
+// leaving out excludeID
+func activitiesInBucket(day *models.DayPlan, timeOfDay, excludeID string) []models.DayPlanActivity {
+	var bucket []models.DayPlanActivity
+	for _, dpa := range day.DayPlanActivities {
+		if dpa.TimeOfDay == timeOfDay && dpa.ID != excludeID {
+			bucket = append(bucket, dpa)
+		}
+	}
+	sort.SliceStable(bucket, func(i, j int) bool {
+		return bucket[i].OrderWithinTime < bucket[j].OrderWithinTime
+	})
+	return bucket
+}
+
+func insertActivity(bucket []models.DayPlanActivity, dpa models.DayPlanActivity, position int) []models.DayPlanActivity {
+	position = clamp(position, 0, len(bucket))
+	result := make([]models.DayPlanActivity, 0, len(bucket)+1)
+	result = append(result, bucket[:position]...)
+	result = append(result, dpa)
+	return append(result, bucket[position:]...)
+}
+
+func renumberActivities(bucket []models.DayPlanActivity, dayPlanID string, now time.Time) {
+	for i := range bucket {
+		bucket[i].DayPlanID = dayPlanID
+		bucket[i].OrderWithinTime = i
+		bucket[i].UpdatedAt = now
+	}
+}
+
+func insertDestination(list []models.DayPlanDestination, dpd models.DayPlanDestination, position int) []models.DayPlanDestination {
+	position = clamp(position, 0, len(list))
+	result := make([]models.DayPlanDestination, 0, len(list)+1)
+	result = append(result, list[:position]...)
+	result = append(result, dpd)
+	return append(result, list[position:]...)
+}
+
+// reloadDayAndPublish returns the day after a change to one of its items and tells the trip's subscribers
+func (s *dayPlanService) reloadDayAndPublish(ctx context.Context, ownerID, tripID, dayID, itemID, eventType string) (*models.DayPlan, error) {
+	day, err := s.dayPlanRepo.FindByID(ctx, tripID, dayID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.localizeDays(ctx, tripID, day); err != nil {
+		return nil, err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, eventType, dto.DayChangeEvent{
+		DayID:  dayID,
+		ItemID: itemID,
+		Day:    day,
+	})
+	return day, nil
+}
+
+// renumberDays assigns dense day numbers (1..n) and returns the days whose number changed
+func renumberDays(days []models.DayPlan, now time.Time) []models.DayPlan {
+	var changed []models.DayPlan
+	for i := range days {
+		if days[i].DayNumber == i+1 {
+			continue
+		}
+		days[i].DayNumber = i + 1
+		days[i].UpdatedAt = now
+		changed = append(changed, days[i])
+	}
+	return changed
+}
+
+func findDayPlanActivity(day *models.DayPlan, id string) *models.DayPlanActivity {
+	for i := range day.DayPlanActivities {
+		if day.DayPlanActivities[i].ID == id {
+			return &day.DayPlanActivities[i]
+		}
+	}
+	return nil
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
@@ -0,0 +1,37 @@
+package service
+
+import "context"
+
+// GeoPoint is a latitude/longitude pair in degrees
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// DistanceMatrix defines the interface for measuring travel distances between places.
+// The default implementation measures great-circle distances; a road network or
+// Distance Matrix API backed implementation can be plugged in instead.
+type DistanceMatrix interface {
+	// Distances returns the distance in meters from every point to every other point
+	Distances(ctx context.Context, points []GeoPoint) ([][]float64, error)
+}
+
+type haversineMatrix struct{}
+
+// NewHaversineMatrix creates a distance matrix of straight-line (great-circle) distances
+func NewHaversineMatrix() DistanceMatrix {
+	return haversineMatrix{}
+}
+
+func (haversineMatrix) Distances(ctx context.Context, points []GeoPoint) ([][]float64, error) {
+	matrix := make([][]float64, len(points))
+	for i, from := range points {
+		matrix[i] = make([]float64, len(points))
+		for j, to := range points {
+			if i != j {
+				matrix[i][j] = haversineKm(from.Latitude, from.Longitude, to.Latitude, to.Longitude) * 1000
+			}
+		}
+	}
+	return matrix, nil
+}
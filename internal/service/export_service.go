@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"triply-server/internal/models"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// timeOfDayRank orders TimeOfDay buckets chronologically (the database orders them alphabetically)
+var timeOfDayRank = map[string]int{
+	"start": 0, "morning": 0,
+	"mid": 1, "afternoon": 1,
+	"end": 2, "evening": 2,
+}
+
+// ExportService defines the interface for exporting trips to external formats
+type ExportService interface {
+	ExportGPX(ctx context.Context, ownerID, tripID string) (string, error)
+	ExportKML(ctx context.Context, ownerID, tripID string) (string, error)
+	ExportDocument(ctx context.Context, ownerID, tripID, format string) (string, error)
+}
+
+type exportService struct {
+	tripRepo repository.TripRepository
+}
+
+// NewExportService creates a new export service instance
+func NewExportService(tripRepo repository.TripRepository) ExportService {
+	return &exportService{tripRepo: tripRepo}
+}
+
+func (s *exportService) ExportGPX(ctx context.Context, ownerID, tripID string) (string, error) {
+	trip, err := s.findTrip(ctx, ownerID, tripID)
+	if err != nil {
+		return "", err
+	}
+	return renderGPX(trip)
+}
+
+func (s *exportService) ExportKML(ctx context.Context, ownerID, tripID string) (string, error) {
+	trip, err := s.findTrip(ctx, ownerID, tripID)
+	if err != nil {
+		return "", err
+	}
+	return renderKML(trip)
+}
+
+// ExportDocument renders the trip as a printable HTML or Markdown itinerary
+func (s *exportService) ExportDocument(ctx context.Context, ownerID, tripID, format string) (string, error) {
+	trip, err := s.findTrip(ctx, ownerID, tripID)
+	if err != nil {
+		return "", err
+	}
+	return renderItinerary(trip, "", format, nil)
+}
+
+func (s *exportService) findTrip(ctx context.Context, ownerID, tripID string) (*models.Trip, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+	return trip, nil
+}
+
+// routePoint is a located stop on a day's route
+type routePoint struct {
+	Name        string
+	Description string
+	Type        string
+	Lat         float64
+	Lon         float64
+	Time        *time.Time
+}
+
+// dayRoute is the ordered list of stops for one day
+type dayRoute struct {
+	Name   string
+	Points []routePoint
+}
+
+// buildDayRoutes orders each day's located activities by TimeOfDay and OrderWithinTime.
+// Days without located activities fall back to their destinations.
+func buildDayRoutes(trip *models.Trip) []dayRoute {
+	days := make([]*models.DayPlan, len(trip.DayPlans))
+	for i := range trip.DayPlans {
+		days[i] = &trip.DayPlans[i]
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].DayNumber < days[j].DayNumber })
+
+	routes := make([]dayRoute, 0, len(days))
+	for _, day := range days {
+		route := dayRoute{Name: fmt.Sprintf("Day %d (%s)", day.DayNumber, utils.FormatDate(day.Date))}
+
+		for _, dpa := range orderedDayActivities(day) {
+			act := dpa.Activity
+			if act == nil || act.Latitude == nil || act.Longitude == nil {
+				continue
+			}
+			title := derefString(dpa.CustomTitle)
+			if title == "" {
+				title = act.Title
+			}
+			description := derefString(dpa.CustomNotes)
+			if description == "" {
+				description = derefString(act.Description)
+			}
+			route.Points = append(route.Points, routePoint{
+				Name:        title,
+				Description: description,
+				Type:        act.Type,
+				Lat:         *act.Latitude,
+				Lon:         *act.Longitude,
+				Time:        dpa.CustomTime,
+			})
+		}
+
+		if len(route.Points) == 0 {
+			for _, dpd := range day.DayPlanDestinations {
+				dest := dpd.Destination
+				if dest == nil || dest.Latitude == nil || dest.Longitude == nil {
+					continue
+				}
+				route.Points = append(route.Points, routePoint{
+					Name: dest.City,
+					Type: "destination",
+					Lat:  *dest.Latitude,
+					Lon:  *dest.Longitude,
+				})
+			}
+		}
+
+		if len(route.Points) > 0 {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// orderedDayActivities returns a day's activities in chronological bucket order
+func orderedDayActivities(day *models.DayPlan) []models.DayPlanActivity {
+	activities := make([]models.DayPlanActivity, len(day.DayPlanActivities))
+	copy(activities, day.DayPlanActivities)
+	sort.SliceStable(activities, func(i, j int) bool {
+		ri, rj := timeOfDayRank[activities[i].TimeOfDay], timeOfDayRank[activities[j].TimeOfDay]
+		if ri != rj {
+			return ri < rj
+		}
+		return activities[i].OrderWithinTime < activities[j].OrderWithinTime
+	})
+	return activities
+}
+
+// GPX 1.1 document (https://www.topografix.com/GPX/1/1/)
+type gpxDocument struct {
+	XMLName   xml.Name      `xml:"gpx"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Xmlns     string        `xml:"xmlns,attr"`
+	Metadata  gpxMetadata   `xml:"metadata"`
+	Waypoints []gpxWaypoint `xml:"wpt"`
+	Routes    []gpxRoute    `xml:"rte"`
+}
+
+type gpxMetadata struct {
+	Name string `xml:"name"`
+	Time string `xml:"time"`
+}
+
+type gpxWaypoint struct {
+	Lat  string `xml:"lat,attr"`
+	Lon  string `xml:"lon,attr"`
+	Time string `xml:"time,omitempty"`
+	Name string `xml:"name,omitempty"`
+	Desc string `xml:"desc,omitempty"`
+	Type string `xml:"type,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string        `xml:"name"`
+	Number int           `xml:"number"`
+	Points []gpxWaypoint `xml:"rtept"`
+}
+
+// renderGPX writes every stop as a waypoint and each day as a route
+func renderGPX(trip *models.Trip) (string, error) {
+	doc := gpxDocument{
+		Version:  "1.1",
+		Creator:  "Triply",
+		Xmlns:    "http://www.topografix.com/GPX/1/1",
+		Metadata: gpxMetadata{Name: trip.Name, Time: time.Now().UTC().Format(time.RFC3339)},
+	}
+
+	for i, route := range buildDayRoutes(trip) {
+		rte := gpxRoute{Name: route.Name, Number: i + 1}
+		for _, p := range route.Points {
+			wpt := gpxWaypoint{
+				Lat:  formatCoordinate(p.Lat),
+				Lon:  formatCoordinate(p.Lon),
+				Name: p.Name,
+				Desc: p.Description,
+				Type: p.Type,
+			}
+			if p.Time != nil {
+				wpt.Time = p.Time.UTC().Format(time.RFC3339)
+			}
+			doc.Waypoints = append(doc.Waypoints, wpt)
+			rte.Points = append(rte.Points, wpt)
+		}
+		doc.Routes = append(doc.Routes, rte)
+	}
+
+	return marshalXML(doc)
+}
+
+// KML 2.2 document (https://developers.google.com/kml/documentation/kmlreference)
+type kmlDocument struct {
+	XMLName  xml.Name        `xml:"kml"`
+	Xmlns    string          `xml:"xmlns,attr"`
+	Document kmlDocumentBody `xml:"Document"`
+}
+
+type kmlDocumentBody struct {
+	Name    string      `xml:"name"`
+	Folders []kmlFolder `xml:"Folder"`
+}
+
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Placemarks []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name        string         `xml:"name"`
+	Description string         `xml:"description,omitempty"`
+	Point       *kmlPoint      `xml:"Point,omitempty"`
+	LineString  *kmlLineString `xml:"LineString,omitempty"`
+}
+
+type kmlPoint struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlLineString struct {
+	Tessellate  int    `xml:"tessellate"`
+	Coordinates string `xml:"coordinates"`
+}
+
+// renderKML writes each day as a folder with a placemark per stop and a line through them
+func renderKML(trip *models.Trip) (string, error) {
+	doc := kmlDocument{
+		Xmlns:    "http://www.opengis.net/kml/2.2",
+		Document: kmlDocumentBody{Name: trip.Name},
+	}
+
+	for _, route := range buildDayRoutes(trip) {
+		folder := kmlFolder{Name: route.Name}
+		coords := make([]string, 0, len(route.Points))
+		for _, p := range route.Points {
+			coord := formatCoordinate(p.Lon) + "," + formatCoordinate(p.Lat) + ",0"
+			coords = append(coords, coord)
+			folder.Placemarks = append(folder.Placemarks, kmlPlacemark{
+				Name:        p.Name,
+				Description: p.Description,
+				Point:       &kmlPoint{Coordinates: coord},
+			})
+		}
+		if len(coords) > 1 {
+			folder.Placemarks = append(folder.Placemarks, kmlPlacemark{
+				Name:       route.Name,
+				LineString: &kmlLineString{Tessellate: 1, Coordinates: strings.Join(coords, " ")},
+			})
+		}
+		doc.Document.Folders = append(doc.Document.Folders, folder)
+	}
+
+	return marshalXML(doc)
+}
+
+func marshalXML(v interface{}) (string, error) {
+	out, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return xml.Header + string(out) + "\n", nil
+}
+
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'f', 6, 64)
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+)
+
+// ImageEncoder writes resized images in one output format. JPEG is built in; WebP is
+// encoded by the cwebp tool from libwebp, as the Go libraries only decode it.
+type ImageEncoder interface {
+	// Extension is the file extension of the format's variants, e.g. "jpg"
+	Extension() string
+	ContentType() string
+	Encode(ctx context.Context, w io.Writer, img image.Image) error
+}
+
+type jpegEncoder struct {
+	quality int
+}
+
+// NewJPEGEncoder creates an encoder for baseline JPEG at the given quality (1-100)
+func NewJPEGEncoder(quality int) ImageEncoder {
+	return jpegEncoder{quality: quality}
+}
+
+func (jpegEncoder) Extension() string   { return "jpg" }
+func (jpegEncoder) ContentType() string { return "image/jpeg" }
+
+func (e jpegEncoder) Encode(ctx context.Context, w io.Writer, img image.Image) error {
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: e.quality})
+}
+
+type cwebpEncoder struct {
+	path    string
+	quality int
+}
+
+// NewCWebPEncoder creates a WebP encoder that runs the cwebp executable at path
+func NewCWebPEncoder(path string, quality int) ImageEncoder {
+	return cwebpEncoder{path: path, quality: quality}
+}
+
+func (cwebpEncoder) Extension() string   { return "webp" }
+func (cwebpEncoder) ContentType() string { return "image/webp" }
+
+// Encode hands cwebp a lossless PNG of the image through a temporary directory
+func (e cwebpEncoder) Encode(ctx context.Context, w io.Writer, img image.Image) error {
+	dir, err := os.MkdirTemp("", "triply-webp-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	input := filepath.Join(dir, "in.png")
+	output := filepath.Join(dir, "out.webp")
+	file, err := os.Create(input)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(file, img); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, e.path, "-quiet", "-metadata", "none", "-q", strconv.Itoa(e.quality), input, "-o", output)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cwebp failed: %w: %s", err, out)
+	}
+
+	result, err := os.Open(output)
+	if err != nil {
+		return err
+	}
+	defer result.Close()
+	_, err = io.Copy(w, result)
+	return err
+}
@@ -0,0 +1,425 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decoders for uploaded images
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"regexp"
+	"strconv"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/storage"
+	"triply-server/internal/utils"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"gorm.io/gorm"
+)
+
+// imageWidths are the standard widths every uploaded image is resized to. Images are
+// never upscaled, so a narrow upload stores its own width under the larger names.
+var imageWidths = []int{320, 640, 1280, 1920}
+
+// defaultImageWidth is the variant stored on the model
+const defaultImageWidth = 1280
+
+// maxImagePixels rejects images that would take too much memory to decode
+const maxImagePixels = 40_000_000
+
+// maxConcurrentImageUploads bounds how many uploads are decoded and resized at once, as
+// each holds a full decoded image in memory
+const maxConcurrentImageUploads = 2
+
+// imageUploadTypes are the accepted upload formats, as detected from the file content
+var imageUploadTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+	"image/gif":  true,
+}
+
+// imageFilePattern matches variant file names such as "1280.jpg"
+var imageFilePattern = regexp.MustCompile(`^(\d+)\.([a-z]+)$`)
+
+// imageIDPattern matches the IDs given to uploaded images
+var imageIDPattern = regexp.MustCompile(`^img-[a-z0-9]+$`)
+
+// imageURLPattern finds the image ID in a stored image URL
+var imageURLPattern = regexp.MustCompile(`/api/images/(img-[a-z0-9]+)/`)
+
+// ImageService defines the interface for uploading trip cover and destination hero images
+type ImageService interface {
+	UploadTripCover(ctx context.Context, ownerID, tripID string, upload *Upload) (*dto.UploadedImage, error)
+	UploadDestinationHero(ctx context.Context, destinationID string, upload *Upload) (*dto.UploadedImage, error)
+	// OpenImage opens a stored variant such as "1280.jpg"; the caller closes it
+	OpenImage(ctx context.Context, imageID, file string) (io.ReadCloser, string, error)
+}
+
+type imageService struct {
+	tripRepo        repository.TripRepository
+	destinationRepo repository.DestinationRepository
+	blobs           storage.BlobStore
+	encoders        []ImageEncoder
+	publicURL       string
+	maxUploadBytes  int64
+	events          realtime.Publisher
+	slots           chan struct{}
+}
+
+// NewImageService creates a new image service instance. Each upload is stored in every
+// encoder's format; publicURL is the server's external base URL, used in the stored image URLs.
+func NewImageService(tripRepo repository.TripRepository, destinationRepo repository.DestinationRepository, blobs storage.BlobStore, encoders []ImageEncoder, publicURL string, maxUploadBytes int64, events realtime.Publisher) ImageService {
+	return &imageService{
+		tripRepo:        tripRepo,
+		destinationRepo: destinationRepo,
+		blobs:           blobs,
+		encoders:        encoders,
+		publicURL:       publicURL,
+		maxUploadBytes:  maxUploadBytes,
+		events:          events,
+		slots:           make(chan struct{}, maxConcurrentImageUploads),
+	}
+}
+
+// UploadTripCover stores the image and makes it the trip's cover
+func (s *imageService) UploadTripCover(ctx context.Context, ownerID, tripID string, upload *Upload) (*dto.UploadedImage, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	uploaded, err := s.storeImage(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.tripRepo.UpdateCoverImage(ctx, tripID, ownerID, uploaded.URL)
+	if err != nil {
+		s.deleteImage(ctx, uploaded.ID)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+	// Trips cloned or imported from this one may still show the replaced cover
+	if inUse, err := s.tripRepo.CoverImageInUse(ctx, previous); err == nil && !inUse {
+		s.deleteImageAt(ctx, previous)
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	localizeTrip(trip)
+	attachReservations(trip)
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventTripUpdated, trip)
+	return uploaded, nil
+}
+
+// UploadDestinationHero stores the image and makes it the catalog destination's hero image
+func (s *imageService) UploadDestinationHero(ctx context.Context, destinationID string, upload *Upload) (*dto.UploadedImage, error) {
+	destinations, err := s.destinationRepo.FindByIDs(ctx, []string{destinationID})
+	if err != nil {
+		return nil, err
+	}
+	if len(destinations) == 0 {
+		return nil, utils.NewNotFoundError("Destination")
+	}
+
+	uploaded, err := s.storeImage(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.destinationRepo.UpdateHeroImage(ctx, destinationID, uploaded.URL); err != nil {
+		s.deleteImage(ctx, uploaded.ID)
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Destination")
+		}
+		return nil, err
+	}
+	if previous := destinations[0].HeroImage; previous != nil {
+		s.deleteImageAt(ctx, *previous)
+	}
+	return uploaded, nil
+}
+
+func (s *imageService) OpenImage(ctx context.Context, imageID, file string) (io.ReadCloser, string, error) {
+	match := imageFilePattern.FindStringSubmatch(file)
+	if !imageIDPattern.MatchString(imageID) || match == nil {
+		return nil, "", utils.NewNotFoundError("Image")
+	}
+	var contentType string
+	for _, encoder := range s.encoders {
+		if encoder.Extension() == match[2] {
+			contentType = encoder.ContentType()
+		}
+	}
+	if contentType == "" {
+		return nil, "", utils.NewNotFoundError("Image")
+	}
+
+	content, err := s.blobs.Open(ctx, imageKey(imageID, file))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, "", utils.NewNotFoundError("Image")
+		}
+		return nil, "", err
+	}
+	return content, contentType, nil
+}
+
+// storeImage decodes the upload, applies its EXIF orientation and stores it at every standard
+// width in every output format. Re-encoding the pixels drops EXIF and all other metadata.
+func (s *imageService) storeImage(ctx context.Context, upload *Upload) (*dto.UploadedImage, error) {
+	content, contentType, err := sniffUpload(upload, s.maxUploadBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !imageUploadTypes[contentType] {
+		return nil, utils.NewValidationError("images must be JPEG, PNG, WebP or GIF files")
+	}
+	data, err := io.ReadAll(io.LimitReader(content, s.maxUploadBytes))
+	if err != nil {
+		return nil, utils.NewValidationError("the file could not be read")
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, utils.NewValidationError("the image could not be decoded")
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, utils.NewValidationError("images can be at most 40 megapixels")
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, utils.NewValidationError("the image could not be decoded")
+	}
+	orientation := 1
+	if contentType == "image/jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	// Scale down to the widest variant before turning the image upright, so only the
+	// decoded image is ever held at full size
+	width, height := decoded.Bounds().Dx(), decoded.Bounds().Dy()
+	if orientation >= 5 {
+		width, height = height, width
+	}
+	if maxWidth := imageWidths[len(imageWidths)-1]; width > maxWidth {
+		width, height = maxWidth, max(1, (height*maxWidth+width/2)/width)
+	}
+	if orientation >= 5 {
+		width, height = height, width
+	}
+	source := orientImage(flattenImage(decoded, width, height), orientation)
+
+	uploaded := &dto.UploadedImage{ID: utils.GenerateID("img")}
+	stored, err := s.storeVariants(ctx, uploaded, source)
+	if err != nil {
+		// Don't leave a partial set of variants behind
+		for _, key := range stored {
+			_ = s.blobs.Delete(ctx, key)
+		}
+		return nil, err
+	}
+	uploaded.URL = s.imageURL(uploaded.ID, fmt.Sprintf("%d.%s", defaultImageWidth, s.encoders[0].Extension()))
+	return uploaded, nil
+}
+
+// storeVariants resizes and encodes the image at every standard width, adding the variants to
+// uploaded. It returns the keys it stored, including those stored before an error.
+func (s *imageService) storeVariants(ctx context.Context, uploaded *dto.UploadedImage, source *image.RGBA) ([]string, error) {
+	var stored []string
+	bounds := source.Bounds()
+	resized := make(map[int]image.Image)
+	for _, width := range imageWidths {
+		target := min(width, bounds.Dx())
+		img, ok := resized[target]
+		if !ok {
+			img = resizeImage(source, target)
+			resized[target] = img
+		}
+
+		for _, encoder := range s.encoders {
+			var buf bytes.Buffer
+			if err := encoder.Encode(ctx, &buf, img); err != nil {
+				return stored, err
+			}
+			file := strconv.Itoa(width) + "." + encoder.Extension()
+			if err := s.blobs.Put(ctx, imageKey(uploaded.ID, file), &buf, encoder.ContentType()); err != nil {
+				return stored, err
+			}
+			stored = append(stored, imageKey(uploaded.ID, file))
+			uploaded.Variants = append(uploaded.Variants, dto.ImageVariant{
+				Width:  img.Bounds().Dx(),
+				Height: img.Bounds().Dy(),
+				Format: encoder.Extension(),
+				URL:    s.imageURL(uploaded.ID, file),
+			})
+		}
+	}
+	return stored, nil
+}
+
+// deleteImage removes every variant of an uploaded image. It is best effort: a variant that
+// can't be removed only costs storage.
+func (s *imageService) deleteImage(ctx context.Context, imageID string) {
+	for _, width := range imageWidths {
+		for _, encoder := range s.encoders {
+			_ = s.blobs.Delete(ctx, imageKey(imageID, strconv.Itoa(width)+"."+encoder.Extension()))
+		}
+	}
+}
+
+// deleteImageAt removes the uploaded image a stored URL points to; other URLs are left alone
+func (s *imageService) deleteImageAt(ctx context.Context, url string) {
+	if match := imageURLPattern.FindStringSubmatch(url); match != nil {
+		s.deleteImage(ctx, match[1])
+	}
+}
+
+func (s *imageService) imageURL(imageID, file string) string {
+	return s.publicURL + "/api/images/" + imageID + "/" + file
+}
+
+func imageKey(imageID, file string) string {
+	return "images/" + imageID + "/" + file
+}
+
+// resizeImage scales the image to the width, keeping its aspect ratio
+func resizeImage(src *image.RGBA, width int) image.Image {
+	bounds := src.Bounds()
+	if width >= bounds.Dx() {
+		return src
+	}
+	height := max(1, (bounds.Dy()*width+bounds.Dx()/2)/bounds.Dx())
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Src, nil)
+	return dst
+}
+
+// flattenImage draws the image over white, as JPEG has no transparency, scaling it to the size
+func flattenImage(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	if width == bounds.Dx() && height == bounds.Dy() {
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Over)
+	} else {
+		draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+	}
+	return dst
+}
+
+// orientImage turns the image upright according to its EXIF orientation (1-8)
+func orientImage(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored
+				sx, sy = w-1-x, y
+			case 3: // rotated 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // needs a 90° clockwise turn
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // needs a 90° counter-clockwise turn
+				sx, sy = w-1-y, x
+			}
+			dst.SetRGBA(x, y, src.RGBAAt(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return dst
+}
+
+// jpegOrientation reads the EXIF orientation of a JPEG, or 1 (upright) when it has none
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // image data starts: no metadata follows
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation finds the orientation tag in the first IFD of a TIFF-encoded EXIF block
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= 1 && value <= 8 {
+				return value
+			}
+			return 1
+		}
+	}
+	return 1
+}
@@ -0,0 +1,497 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// maxDestinationMatchKm is how far an event's GEO may be from a destination to be mapped to it
+const maxDestinationMatchKm = 50
+
+// activityTypeKeywords infers an activity type from an event's summary
+var activityTypeKeywords = []struct {
+	activityType string
+	keywords     []string
+}{
+	{"transportation", []string{"flight", "airport", "train", "bus", "ferry", "transfer", "taxi", "car rental", "shinkansen"}},
+	{"accommodation", []string{"hotel", "check-in", "check in", "checkout", "check-out", "hostel", "airbnb", "ryokan", "stay at"}},
+	{"meal", []string{"breakfast", "lunch", "dinner", "restaurant", "cafe", "brunch"}},
+}
+
+// calendarImport accumulates the rows an .ics import writes
+type calendarImport struct {
+	ownerID  string
+	trip     *models.Trip
+	importID string
+	now      time.Time
+	changes  *repository.ImportChanges
+
+	catalog       []models.Destination
+	existingDays  map[string]*models.DayPlan // by YYYY-MM-DD
+	newDays       map[string]*models.DayPlan // by YYYY-MM-DD
+	dayDests      map[string][]string        // destination IDs already linked to each new day
+	tripDests     map[string]bool
+	nextOrder     map[string]int // next OrderWithinTime by day ID + bucket
+	activities    map[string]string
+	newActivities map[string]bool
+}
+
+// ImportCalendar turns the events of an .ics file into days and activities on a new or existing trip
+func (s *importService) ImportCalendar(ctx context.Context, ownerID string, req *dto.ImportCalendarRequest, data []byte) (*models.Trip, error) {
+	cal, err := utils.ParseICal(string(data))
+	if err != nil {
+		return nil, utils.NewValidationError("invalid calendar file: " + err.Error())
+	}
+
+	// Skip the all-day "Day N" events of our own calendar export
+	events := make([]utils.ICalEvent, 0, len(cal.Events))
+	for _, ev := range cal.Events {
+		if ev.AllDay && strings.HasSuffix(ev.UID, "@triply") {
+			continue
+		}
+		events = append(events, ev)
+	}
+	if len(events) == 0 {
+		return nil, utils.NewValidationError("calendar file contains no events")
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+
+	catalog, err := s.destinationRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	imp := &calendarImport{
+		ownerID:       ownerID,
+		importID:      utils.GenerateID("import"),
+		now:           now,
+		changes:       &repository.ImportChanges{},
+		catalog:       catalog,
+		existingDays:  make(map[string]*models.DayPlan),
+		newDays:       make(map[string]*models.DayPlan),
+		dayDests:      make(map[string][]string),
+		tripDests:     make(map[string]bool),
+		nextOrder:     make(map[string]int),
+		activities:    make(map[string]string),
+		newActivities: make(map[string]bool),
+	}
+
+	// Resolve the target trip (an existing trip of the owner, or a new private trip)
+	if req.TripID != "" {
+		if _, err := requireTripRole(ctx, s.tripRepo, req.TripID, ownerID, models.TripRoleEditor); err != nil {
+			return nil, err
+		}
+		imp.trip, err = s.tripRepo.FindByID(ctx, req.TripID, ownerID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, utils.NewNotFoundError("Trip")
+			}
+			return nil, err
+		}
+	} else {
+		name := strings.TrimSpace(req.TripName)
+		if name == "" {
+			name = strings.TrimSpace(cal.Name)
+		}
+		if name == "" {
+			name = "Imported trip"
+		}
+		imp.trip = &models.Trip{
+			ID:            utils.GenerateID("trip"),
+			UserID:        ownerID,
+			Name:          name,
+			TravelerCount: 1,
+			Adults:        1,
+			Visibility:    "private",
+			Status:        "active",
+			Version:       1,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		imp.changes.NewTrip = imp.trip
+	}
+	imp.changes.TripID = imp.trip.ID
+	imp.changes.UserID = ownerID
+
+	for i := range imp.trip.DayPlans {
+		day := &imp.trip.DayPlans[i]
+		imp.existingDays[utils.FormatDate(day.Date)] = day
+		for _, dpa := range day.DayPlanActivities {
+			key := day.ID + "|" + dpa.TimeOfDay
+			if dpa.OrderWithinTime+1 > imp.nextOrder[key] {
+				imp.nextOrder[key] = dpa.OrderWithinTime + 1
+			}
+		}
+	}
+	for _, td := range imp.trip.TripDestinations {
+		imp.tripDests[td.DestinationID] = true
+	}
+
+	for i := range events {
+		if err := s.importCalendarEvent(ctx, imp, &events[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	imp.finish()
+
+	if err := s.importRepo.ApplyImport(ctx, imp.changes); err != nil {
+		return nil, err
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, imp.trip.ID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+	localizeTrip(trip)
+	attachReservations(trip)
+	if req.TripID != "" {
+		publishTripEvent(ctx, s.events, trip.ID, ownerID, realtime.EventTripUpdated, trip)
+	}
+	return trip, nil
+}
+
+func (s *importService) importCalendarEvent(ctx context.Context, imp *calendarImport, ev *utils.ICalEvent) error {
+	dest := imp.resolveDestination(ev)
+	start, end := localEventTimes(ev, dest)
+	date := start.Format(utils.DateLayout)
+
+	// Find or create the day
+	day, existing := imp.existingDays[date]
+	if !existing {
+		day = imp.newDays[date]
+		if day == nil {
+			day = &models.DayPlan{
+				ID:        utils.GenerateID("day"),
+				TripID:    imp.trip.ID,
+				Date:      date,
+				CreatedAt: imp.now,
+				UpdatedAt: imp.now,
+			}
+			imp.newDays[date] = day
+		}
+	}
+
+	if dest != nil {
+		if !existing && !containsString(imp.dayDests[day.ID], dest.ID) {
+			day.DayPlanDestinations = append(day.DayPlanDestinations, models.DayPlanDestination{
+				ID:            utils.GenerateID("dpd"),
+				DayPlanID:     day.ID,
+				DestinationID: dest.ID,
+				OrderIndex:    len(imp.dayDests[day.ID]),
+				CreatedAt:     imp.now,
+			})
+			imp.dayDests[day.ID] = append(imp.dayDests[day.ID], dest.ID)
+		}
+		if !imp.tripDests[dest.ID] {
+			imp.changes.NewTripDestinations = append(imp.changes.NewTripDestinations, models.TripDestination{
+				ID:            utils.GenerateID("td"),
+				DestinationID: dest.ID,
+				OrderIndex:    len(imp.tripDests),
+				CreatedAt:     imp.now,
+			})
+			imp.tripDests[dest.ID] = true
+		}
+	}
+
+	activityID, reused, err := s.resolveCalendarActivity(ctx, imp, ev, start, end)
+	if err != nil {
+		return err
+	}
+
+	timeOfDay := "start"
+	if !ev.AllDay {
+		switch {
+		case start.Hour() >= 17:
+			timeOfDay = "end"
+		case start.Hour() >= 12:
+			timeOfDay = "mid"
+		}
+	}
+
+	key := day.ID + "|" + timeOfDay
+	dpa := models.DayPlanActivity{
+		ID:              utils.GenerateID("dpa"),
+		DayPlanID:       day.ID,
+		ActivityID:      activityID,
+		TimeOfDay:       timeOfDay,
+		OrderWithinTime: imp.nextOrder[key],
+		CreatedAt:       imp.now,
+		UpdatedAt:       imp.now,
+	}
+	imp.nextOrder[key]++
+	if !ev.AllDay {
+		dpa.CustomTime = &start
+	}
+	// A reused library activity keeps its own description; the event's goes into the notes
+	if reused && ev.Description != "" {
+		dpa.CustomNotes = stringPtr(ev.Description)
+	}
+
+	if existing {
+		imp.changes.NewActivities = append(imp.changes.NewActivities, dpa)
+	} else {
+		day.DayPlanActivities = append(day.DayPlanActivities, dpa)
+	}
+
+	imp.changes.ActivityImports = append(imp.changes.ActivityImports, models.ActivityImport{
+		ID:               utils.GenerateID("imp"),
+		ImportID:         imp.importID,
+		TargetTripID:     imp.trip.ID,
+		ActivityID:       activityID,
+		ImportedByUserID: imp.ownerID,
+		ImportedAt:       imp.now,
+	})
+	return nil
+}
+
+// resolveCalendarActivity reuses a library activity with the same title and location, or creates one
+func (s *importService) resolveCalendarActivity(ctx context.Context, imp *calendarImport, ev *utils.ICalEvent, start, end time.Time) (string, bool, error) {
+	title := strings.TrimSpace(ev.Summary)
+	if title == "" {
+		title = "Untitled event"
+	}
+	location := strings.TrimSpace(ev.Location)
+
+	key := strings.ToLower(title) + "|" + strings.ToLower(location)
+	if id, ok := imp.activities[key]; ok {
+		return id, !imp.newActivities[id], nil
+	}
+
+	existing, err := s.activityRepo.FindByTitle(ctx, title, location)
+	if err == nil {
+		imp.activities[key] = existing.ID
+		return existing.ID, true, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return "", false, err
+	}
+
+	activity := models.Activity{
+		ID:        utils.GenerateID("act"),
+		Title:     title,
+		Type:      inferActivityType(title),
+		Latitude:  ev.Latitude,
+		Longitude: ev.Longitude,
+		CreatedAt: imp.now,
+		UpdatedAt: imp.now,
+	}
+	if location != "" {
+		activity.Location = stringPtr(location)
+	}
+	if ev.Description != "" {
+		activity.Description = stringPtr(ev.Description)
+	}
+	if ev.URL != "" {
+		activity.URL = stringPtr(ev.URL)
+	}
+	if minutes := int(end.Sub(start).Minutes()); !ev.AllDay && minutes > 0 {
+		activity.DurationMinutes = &minutes
+	}
+	ownerID := imp.ownerID
+	activity.CreatedByUserID = &ownerID
+
+	imp.changes.NewLibraryActivities = append(imp.changes.NewLibraryActivities, activity)
+	imp.activities[key] = activity.ID
+	imp.newActivities[activity.ID] = true
+	return activity.ID, false, nil
+}
+
+// resolveDestination maps an event to a catalog destination by city name in its location or by
+// distance from its GEO, creating a destination from a "..., City, Country" location otherwise
+func (imp *calendarImport) resolveDestination(ev *utils.ICalEvent) *models.Destination {
+	location := strings.ToLower(ev.Location)
+
+	var best *models.Destination
+	if location != "" {
+		for i := range imp.catalog {
+			city := strings.ToLower(imp.catalog[i].City)
+			if city != "" && strings.Contains(location, city) && (best == nil || len(city) > len(best.City)) {
+				best = &imp.catalog[i]
+			}
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	if ev.Latitude != nil && ev.Longitude != nil {
+		bestKm := float64(maxDestinationMatchKm)
+		for i := range imp.catalog {
+			dest := &imp.catalog[i]
+			if dest.Latitude == nil || dest.Longitude == nil {
+				continue
+			}
+			if km := haversineKm(*ev.Latitude, *ev.Longitude, *dest.Latitude, *dest.Longitude); km <= bestKm {
+				best, bestKm = dest, km
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+
+	// "Venue, Street, City 123-4567, Country" -> City, Country
+	parts := strings.Split(ev.Location, ",")
+	if len(parts) < 2 {
+		return nil
+	}
+	city := stripPostalCode(parts[len(parts)-2])
+	country := strings.TrimSpace(parts[len(parts)-1])
+	if city == "" || country == "" {
+		return nil
+	}
+
+	dest := models.Destination{
+		ID:        utils.GenerateID("dest"),
+		City:      city,
+		Country:   country,
+		Latitude:  ev.Latitude,
+		Longitude: ev.Longitude,
+		CreatedAt: imp.now,
+		UpdatedAt: imp.now,
+	}
+	if !ev.Floating && !ev.AllDay && ev.Start.Location() != time.UTC {
+		dest.Timezone = stringPtr(ev.Start.Location().String())
+	}
+	imp.changes.NewDestinations = append(imp.changes.NewDestinations, dest)
+	imp.catalog = append(imp.catalog, dest)
+	return &imp.catalog[len(imp.catalog)-1]
+}
+
+// finish renumbers the days by date, widens the trip's date range and moves the new days into changes
+func (imp *calendarImport) finish() {
+	type dayRef struct {
+		day      *models.DayPlan
+		date     string
+		existing bool
+	}
+	var all []dayRef
+	for i := range imp.trip.DayPlans {
+		day := &imp.trip.DayPlans[i]
+		all = append(all, dayRef{day: day, date: utils.FormatDate(day.Date), existing: true})
+	}
+	for date, day := range imp.newDays {
+		all = append(all, dayRef{day: day, date: date})
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].date != all[j].date {
+			return all[i].date < all[j].date
+		}
+		return all[i].day.DayNumber < all[j].day.DayNumber
+	})
+
+	for i, ref := range all {
+		number := i + 1
+		if !ref.existing {
+			ref.day.DayNumber = number
+			imp.changes.NewDays = append(imp.changes.NewDays, *ref.day)
+			continue
+		}
+		if ref.day.DayNumber != number {
+			imp.changes.ShiftedDays = append(imp.changes.ShiftedDays, models.DayPlan{
+				ID:        ref.day.ID,
+				DayNumber: number,
+				Date:      ref.date,
+				UpdatedAt: imp.now,
+			})
+		}
+	}
+
+	if len(all) == 0 {
+		return
+	}
+	first, last := all[0].date, all[len(all)-1].date
+	if imp.changes.NewTrip != nil {
+		imp.trip.StartDate = first
+		imp.trip.EndDate = last
+		return
+	}
+	if start := utils.FormatDate(imp.trip.StartDate); first < start {
+		imp.changes.StartDate = first
+	}
+	if end := utils.FormatDate(imp.trip.EndDate); last > end {
+		imp.changes.EndDate = last
+	}
+}
+
+// localEventTimes returns the event's start and end in the destination's timezone when known
+func localEventTimes(ev *utils.ICalEvent, dest *models.Destination) (time.Time, time.Time) {
+	if ev.AllDay {
+		return ev.Start, ev.End
+	}
+
+	loc := time.UTC
+	if dest != nil && dest.Timezone != nil {
+		if l, err := time.LoadLocation(*dest.Timezone); err == nil {
+			loc = l
+		}
+	}
+
+	switch {
+	case ev.Floating:
+		s, e := ev.Start, ev.End
+		return time.Date(s.Year(), s.Month(), s.Day(), s.Hour(), s.Minute(), s.Second(), 0, loc),
+			time.Date(e.Year(), e.Month(), e.Day(), e.Hour(), e.Minute(), e.Second(), 0, loc)
+	case ev.Start.Location() == time.UTC:
+		return ev.Start.In(loc), ev.End.In(loc)
+	default:
+		return ev.Start, ev.End
+	}
+}
+
+func inferActivityType(title string) string {
+	lower := strings.ToLower(title)
+	for _, entry := range activityTypeKeywords {
+		for _, keyword := range entry.keywords {
+			if strings.Contains(lower, keyword) {
+				return entry.activityType
+			}
+		}
+	}
+	return "experience"
+}
+
+// stripPostalCode drops tokens containing digits, e.g. "Chuo City 104-0061" -> "Chuo City"
+func stripPostalCode(value string) string {
+	var words []string
+	for _, word := range strings.Fields(value) {
+		if !strings.ContainsAny(word, "0123456789") {
+			words = append(words, word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,63 +2,487 @@ package service
 
 import (
 	"context"
+	"sort"
 	"time"
 	"triply-server/internal/dto"
 	"triply-server/internal/models"
+	"triply-server/internal/realtime"
 	"triply-server/internal/repository"
 	"triply-server/internal/utils"
 
 	"gorm.io/gorm"
 )
 
+// Import modes supported by ImportTarget.Mode
+const (
+	ImportModeNewDay    = "new-day"    // add the selection as new days at the end of the trip
+	ImportModeAppendDay = "append-day" // append the selected activities to an existing day
+	ImportModeAppendLeg = "append-leg" // add the selection as new days after the target destination's days
+)
+
 // ImportService defines the interface for importing trip data
 type ImportService interface {
-	ImportTripParts(ctx context.Context, userID string, req *dto.ImportTripRequest) (*models.Trip, error)
+	ImportTripParts(ctx context.Context, userID string, req *dto.ImportTripRequest) (*dto.ImportTripResponse, error)
+	ImportCalendar(ctx context.Context, ownerID string, req *dto.ImportCalendarRequest, data []byte) (*models.Trip, error)
 }
 
 type importService struct {
-	publicTripRepo repository.PublicTripRepository
-	tripRepo       repository.TripRepository
+	publicTripRepo  repository.PublicTripRepository
+	tripRepo        repository.TripRepository
+	importRepo      repository.ImportRepository
+	activityRepo    repository.ActivityRepository
+	destinationRepo repository.DestinationRepository
+	events          realtime.Publisher
 }
 
 // NewImportService creates a new import service instance
-func NewImportService(publicTripRepo repository.PublicTripRepository, tripRepo repository.TripRepository) ImportService {
+func NewImportService(publicTripRepo repository.PublicTripRepository, tripRepo repository.TripRepository, importRepo repository.ImportRepository, activityRepo repository.ActivityRepository, destinationRepo repository.DestinationRepository, events realtime.Publisher) ImportService {
 	return &importService{
-		publicTripRepo: publicTripRepo,
-		tripRepo:       tripRepo,
+		publicTripRepo:  publicTripRepo,
+		tripRepo:        tripRepo,
+		importRepo:      importRepo,
+		activityRepo:    activityRepo,
+		destinationRepo: destinationRepo,
+		events:          events,
 	}
 }
 
-func (s *importService) ImportTripParts(ctx context.Context, userID string, req *dto.ImportTripRequest) (*models.Trip, error) {
-	// Get the public trip (source)
-	publicTrip, err := s.publicTripRepo.FindByID(ctx, req.SourceTripID)
+// importUnit is one source day together with the activities selected from it
+type importUnit struct {
+	day        *models.DayPlan
+	activities []models.DayPlanActivity
+	loc        *time.Location // the source day's timezone
+}
+
+func (s *importService) ImportTripParts(ctx context.Context, userID string, req *dto.ImportTripRequest) (*dto.ImportTripResponse, error) {
+	if req.SourceTripID == "" {
+		return nil, utils.NewValidationError("sourceTripId is required")
+	}
+	mode := req.Target.Mode
+	if mode == "" {
+		mode = ImportModeNewDay
+	}
+	if mode != ImportModeNewDay && mode != ImportModeAppendDay && mode != ImportModeAppendLeg {
+		return nil, utils.NewValidationError("target mode must be 'new-day', 'append-day' or 'append-leg'")
+	}
+
+	sourceTrip, err := s.findSourceTrip(ctx, req.SourceTripID, userID)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, utils.NewNotFoundError("Public trip")
-		}
 		return nil, err
 	}
 
-	// For MVP, create a new trip that's a copy of the public trip
-	// TODO: Implement selective day/activity import based on req.Selection and req.Target
+	units := selectImportUnits(sourceTrip, &req.Selection)
+	legs := selectImportLegs(sourceTrip, req.Selection.LegIDs)
+	if len(units) == 0 && len(legs) == 0 {
+		return nil, utils.NewValidationError("selection does not match any days, legs or activities in the source trip")
+	}
+
 	now := time.Now()
-	newTrip := &models.Trip{
-		ID:            utils.GenerateID("trip"),
-		UserID:        userID,
-		Name:          "Copy of " + publicTrip.Name,
-		TravelerCount: publicTrip.TravelerCount,
-		StartDate:     publicTrip.StartDate,
-		EndDate:       publicTrip.EndDate,
-		CoverImage:    publicTrip.CoverImage,
-		Visibility:    "private",
-		Status:        "active",
-		CreatedAt:     now,
-		UpdatedAt:     now,
-	}
-
-	if err := s.tripRepo.Create(ctx, newTrip); err != nil {
+	importID := utils.GenerateID("import")
+	changes := &repository.ImportChanges{}
+
+	// Resolve the target trip (an existing trip of the user, or a new private copy)
+	var targetTrip *models.Trip
+	if req.Target.TripID != "" {
+		if _, err := requireTripRole(ctx, s.tripRepo, req.Target.TripID, userID, models.TripRoleEditor); err != nil {
+			return nil, err
+		}
+		targetTrip, err = s.tripRepo.FindByID(ctx, req.Target.TripID, userID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, utils.NewNotFoundError("Trip")
+			}
+			return nil, err
+		}
+	} else {
+		if mode == ImportModeAppendDay {
+			return nil, utils.NewValidationError("append-day requires target.tripId")
+		}
+		targetTrip = &models.Trip{
+			ID:            utils.GenerateID("trip"),
+			UserID:        userID,
+			Name:          "Copy of " + sourceTrip.Name,
+			TravelerCount: sourceTrip.TravelerCount,
+			Adults:        sourceTrip.Adults,
+			ChildrenAges:  sourceTrip.ChildrenAges,
+			StartDate:     utils.FormatDate(sourceTrip.StartDate),
+			EndDate:       utils.FormatDate(sourceTrip.StartDate),
+			CoverImage:    sourceTrip.CoverImage,
+			Visibility:    "private",
+			Status:        "active",
+			TravelerType:  sourceTrip.TravelerType,
+			Version:       1,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+		changes.NewTrip = targetTrip
+	}
+	changes.TripID = targetTrip.ID
+	changes.UserID = userID
+
+	var copied []models.DayPlanActivity
+	switch {
+	case len(units) == 0:
+	case mode == ImportModeAppendDay:
+		copied, err = s.appendToDay(targetTrip, req.Target.DayID, units, changes, now)
+	default:
+		copied, err = s.insertDays(sourceTrip, targetTrip, mode, req.Target.DestinationID, units, changes, now)
+	}
+	if err != nil {
 		return nil, err
 	}
+	importLegs(targetTrip, legs, changes, now)
+
+	for _, dpa := range copied {
+		changes.ActivityImports = append(changes.ActivityImports, models.ActivityImport{
+			ID:               utils.GenerateID("imp"),
+			ImportID:         importID,
+			SourceTripID:     &sourceTrip.ID,
+			TargetTripID:     targetTrip.ID,
+			ActivityID:       dpa.ActivityID,
+			ImportedByUserID: userID,
+			ImportedAt:       now,
+		})
+	}
+
+	if err := s.importRepo.ApplyImport(ctx, changes); err != nil {
+		return nil, err
+	}
+
+	updatedTrip, err := s.tripRepo.FindByID(ctx, targetTrip.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	localizeTrip(updatedTrip)
+	attachReservations(updatedTrip)
+	if req.Target.TripID != "" {
+		publishTripEvent(ctx, s.events, updatedTrip.ID, userID, realtime.EventTripUpdated, updatedTrip)
+	}
+
+	return &dto.ImportTripResponse{
+		ImportID:    importID,
+		UpdatedTrip: *updatedTrip,
+	}, nil
+}
+
+// findSourceTrip loads a public trip, falling back to one of the user's own trips
+func (s *importService) findSourceTrip(ctx context.Context, tripID, userID string) (*models.Trip, error) {
+	trip, err := s.publicTripRepo.FindByID(ctx, tripID)
+	if err == nil {
+		return trip, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	trip, err = s.tripRepo.FindByID(ctx, tripID, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Source trip")
+		}
+		return nil, err
+	}
+	return trip, nil
+}
+
+// appendToDay adds every selected activity to an existing day of the target trip
+func (s *importService) appendToDay(target *models.Trip, dayID *string, units []importUnit, changes *repository.ImportChanges, now time.Time) ([]models.DayPlanActivity, error) {
+	if dayID == nil || *dayID == "" {
+		return nil, utils.NewValidationError("append-day requires target.dayId")
+	}
+
+	var targetDay *models.DayPlan
+	for i := range target.DayPlans {
+		if target.DayPlans[i].ID == *dayID {
+			targetDay = &target.DayPlans[i]
+			break
+		}
+	}
+	if targetDay == nil {
+		return nil, utils.NewNotFoundError("Day plan")
+	}
+
+	// Continue numbering after the existing activities in each time bucket
+	nextOrder := make(map[string]int)
+	for _, dpa := range targetDay.DayPlanActivities {
+		if dpa.OrderWithinTime+1 > nextOrder[dpa.TimeOfDay] {
+			nextOrder[dpa.TimeOfDay] = dpa.OrderWithinTime + 1
+		}
+	}
+
+	loc := dayLocation(target, targetDay)
+	for _, unit := range units {
+		for _, src := range unit.activities {
+			dpa := copyDayPlanActivity(&src, targetDay.ID, targetDay.Date, unit.loc, loc, now)
+			dpa.OrderWithinTime = nextOrder[dpa.TimeOfDay]
+			nextOrder[dpa.TimeOfDay]++
+			changes.NewActivities = append(changes.NewActivities, dpa)
+		}
+	}
+
+	return changes.NewActivities, nil
+}
+
+// insertDays copies each unit as a new day, either at the end of the trip (new-day)
+// or right after the days spent at the target destination (append-leg)
+func (s *importService) insertDays(source, target *models.Trip, mode, destinationID string, units []importUnit, changes *repository.ImportChanges, now time.Time) ([]models.DayPlanActivity, error) {
+	if mode == ImportModeAppendLeg && destinationID == "" {
+		return nil, utils.NewValidationError("append-leg requires target.destinationId")
+	}
+
+	days := make([]models.DayPlan, len(target.DayPlans))
+	copy(days, target.DayPlans)
+	sort.SliceStable(days, func(i, j int) bool { return days[i].DayNumber < days[j].DayNumber })
+
+	// Find the insertion point: index in days after which new days are inserted
+	insertAfter := len(days) - 1
+	if mode == ImportModeAppendLeg {
+		for i, day := range days {
+			for _, dpd := range day.DayPlanDestinations {
+				if dpd.DestinationID == destinationID {
+					insertAfter = i
+				}
+			}
+		}
+	}
+
+	nextDate := utils.FormatDate(target.StartDate)
+	nextNumber := 1
+	if insertAfter >= 0 {
+		nextDate = utils.AddDays(days[insertAfter].Date, 1)
+		nextNumber = days[insertAfter].DayNumber + 1
+	}
+
+	// Shift the days that come after the insertion point
+	for _, day := range days[insertAfter+1:] {
+		day.DayNumber += len(units)
+		day.Date = utils.AddDays(day.Date, len(units))
+		day.UpdatedAt = now
+		changes.ShiftedDays = append(changes.ShiftedDays, day)
+	}
+
+	var copied []models.DayPlanActivity
+	lastDate := nextDate
+	for i, unit := range units {
+		newDay := models.DayPlan{
+			ID:        utils.GenerateID("day"),
+			TripID:    target.ID,
+			Date:      utils.AddDays(nextDate, i),
+			DayNumber: nextNumber + i,
+			Notes:     unit.day.Notes,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		lastDate = newDay.Date
+
+		if destinationID != "" {
+			newDay.DayPlanDestinations = []models.DayPlanDestination{{
+				ID:            utils.GenerateID("dpd"),
+				DayPlanID:     newDay.ID,
+				DestinationID: destinationID,
+				OrderIndex:    0,
+				PartOfDay:     stringPtr("all-day"),
+				CreatedAt:     now,
+			}}
+		} else {
+			for _, src := range unit.day.DayPlanDestinations {
+				newDay.DayPlanDestinations = append(newDay.DayPlanDestinations, models.DayPlanDestination{
+					ID:            utils.GenerateID("dpd"),
+					DayPlanID:     newDay.ID,
+					DestinationID: src.DestinationID,
+					OrderIndex:    src.OrderIndex,
+					PartOfDay:     src.PartOfDay,
+					CreatedAt:     now,
+				})
+			}
+		}
+
+		// New days keep the source day's destinations, and so its timezone, unless they join the target destination
+		loc := unit.loc
+		if destinationID != "" {
+			loc = destinationLocation(destinationID, unit.loc, target, source)
+		}
+
+		orders := make(map[string]int)
+		for _, src := range unit.activities {
+			dpa := copyDayPlanActivity(&src, newDay.ID, newDay.Date, unit.loc, loc, now)
+			dpa.OrderWithinTime = orders[dpa.TimeOfDay]
+			orders[dpa.TimeOfDay]++
+			newDay.DayPlanActivities = append(newDay.DayPlanActivities, dpa)
+		}
+		copied = append(copied, newDay.DayPlanActivities...)
+
+		changes.NewDays = append(changes.NewDays, newDay)
+	}
+
+	// Make sure the target trip lists every destination used by the new days
+	known := make(map[string]bool)
+	nextIndex := 0
+	for _, td := range target.TripDestinations {
+		known[td.DestinationID] = true
+		if td.OrderIndex+1 > nextIndex {
+			nextIndex = td.OrderIndex + 1
+		}
+	}
+	for _, day := range changes.NewDays {
+		for _, dpd := range day.DayPlanDestinations {
+			if known[dpd.DestinationID] {
+				continue
+			}
+			known[dpd.DestinationID] = true
+			changes.NewTripDestinations = append(changes.NewTripDestinations, models.TripDestination{
+				ID:            utils.GenerateID("td"),
+				TripID:        target.ID,
+				DestinationID: dpd.DestinationID,
+				OrderIndex:    nextIndex,
+				CreatedAt:     now,
+			})
+			nextIndex++
+		}
+	}
+
+	// Extend the trip's date range to cover the new and shifted days
+	endDate := lastDate
+	if len(changes.ShiftedDays) > 0 {
+		endDate = changes.ShiftedDays[len(changes.ShiftedDays)-1].Date
+	}
+	if changes.NewTrip != nil {
+		changes.NewTrip.EndDate = endDate
+	} else if endDate > utils.FormatDate(target.EndDate) {
+		changes.EndDate = endDate
+	}
+
+	return copied, nil
+}
+
+// selectImportUnits groups the selected days and activities of the source trip by day,
+// keeping the source itinerary order
+func selectImportUnits(source *models.Trip, selection *dto.ImportSelection) []importUnit {
+	selectedDays := make(map[string]bool)
+	for _, id := range selection.DayIDs {
+		selectedDays[id] = true
+	}
+	selectedActivities := make(map[string]bool)
+	for _, id := range selection.ActivityIDs {
+		selectedActivities[id] = true
+	}
+
+	var units []importUnit
+	for i := range source.DayPlans {
+		day := &source.DayPlans[i]
+		if selectedDays[day.ID] {
+			units = append(units, importUnit{day: day, activities: day.DayPlanActivities, loc: dayLocation(source, day)})
+			continue
+		}
+
+		var activities []models.DayPlanActivity
+		for _, dpa := range day.DayPlanActivities {
+			if selectedActivities[dpa.ID] {
+				activities = append(activities, dpa)
+			}
+		}
+		if len(activities) > 0 {
+			units = append(units, importUnit{day: day, activities: activities, loc: dayLocation(source, day)})
+		}
+	}
+
+	return units
+}
+
+// selectImportLegs returns the source trip's legs with the given IDs
+func selectImportLegs(source *models.Trip, legIDs []string) []models.TripLeg {
+	selected := make(map[string]bool, len(legIDs))
+	for _, id := range legIDs {
+		selected[id] = true
+	}
+
+	var legs []models.TripLeg
+	for _, leg := range source.TripLegs {
+		if selected[leg.ID] {
+			legs = append(legs, leg)
+		}
+	}
+	return legs
+}
+
+// importLegs copies legs into the target trip without their booking references, adding
+// their destinations to the trip when it doesn't list them yet
+func importLegs(target *models.Trip, legs []models.TripLeg, changes *repository.ImportChanges, now time.Time) {
+	if len(legs) == 0 {
+		return
+	}
+
+	known := make(map[string]bool)
+	nextIndex := 0
+	for _, list := range [][]models.TripDestination{target.TripDestinations, changes.NewTripDestinations} {
+		for _, td := range list {
+			known[td.DestinationID] = true
+			if td.OrderIndex+1 > nextIndex {
+				nextIndex = td.OrderIndex + 1
+			}
+		}
+	}
+
+	for _, src := range legs {
+		for _, destinationID := range []string{src.FromDestinationID, src.ToDestinationID} {
+			if known[destinationID] {
+				continue
+			}
+			known[destinationID] = true
+			changes.NewTripDestinations = append(changes.NewTripDestinations, models.TripDestination{
+				ID:            utils.GenerateID("td"),
+				TripID:        target.ID,
+				DestinationID: destinationID,
+				OrderIndex:    nextIndex,
+				CreatedAt:     now,
+			})
+			nextIndex++
+		}
+
+		changes.NewLegs = append(changes.NewLegs, models.TripLeg{
+			ID:                utils.GenerateID("leg"),
+			TripID:            target.ID,
+			FromDestinationID: src.FromDestinationID,
+			ToDestinationID:   src.ToDestinationID,
+			Mode:              src.Mode,
+			Carrier:           src.Carrier,
+			DepartureTime:     src.DepartureTime,
+			DepartureTimezone: src.DepartureTimezone,
+			ArrivalTime:       src.ArrivalTime,
+			ArrivalTimezone:   src.ArrivalTimezone,
+			CostAmount:        src.CostAmount,
+			CostCurrency:      src.CostCurrency,
+			Notes:             src.Notes,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	}
+}
+
+// copyDayPlanActivity copies a day plan activity onto another day, moving any
+// custom time onto the new day's date at the same local clock time in the new day's zone
+func copyDayPlanActivity(src *models.DayPlanActivity, dayPlanID, date string, from, to *time.Location, now time.Time) models.DayPlanActivity {
+	dpa := models.DayPlanActivity{
+		ID:          utils.GenerateID("dpa"),
+		DayPlanID:   dayPlanID,
+		ActivityID:  src.ActivityID,
+		TimeOfDay:   src.TimeOfDay,
+		CustomTitle: src.CustomTitle,
+		CustomNotes: src.CustomNotes,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if src.CustomTime != nil {
+		if _, err := utils.ParseDate(date); err == nil {
+			moved := moveLocalTime(*src.CustomTime, from, date, to)
+			dpa.CustomTime = &moved
+		}
+	}
+
+	return dpa
+}
 
-	return newTrip, nil
+func stringPtr(s string) *string {
+	return &s
 }
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"sort"
+	"strconv"
+	"strings"
+	"triply-server/internal/models"
+	"triply-server/internal/utils"
+	"unicode"
+)
+
+// Printable itinerary formats
+const (
+	DocumentFormatHTML     = "html"
+	DocumentFormatMarkdown = "markdown"
+)
+
+// itineraryLabels holds the fixed strings of a printable itinerary
+type itineraryLabels struct {
+	Destinations, Day, Cost, TotalCost, Travelers, By, Skipped, Minutes string
+	Buckets                                                             [3]string
+}
+
+var itineraryLabelsByLang = map[string]itineraryLabels{
+	"en": {
+		Destinations: "Destinations", Day: "Day", Cost: "Estimated cost",
+		TotalCost: "Total estimated cost", Travelers: "Travelers", By: "By", Skipped: "Skipped", Minutes: "min",
+		Buckets: [3]string{"Morning", "Afternoon", "Evening"},
+	},
+	"he": {
+		Destinations: "יעדים", Day: "יום", Cost: "עלות משוערת",
+		TotalCost: "סה״כ עלות משוערת", Travelers: "מטיילים", By: "מאת", Skipped: "דולג", Minutes: "דק׳",
+		Buckets: [3]string{"בוקר", "צהריים", "ערב"},
+	},
+}
+
+// itineraryDocument is the format-independent view of a trip rendered by the printable exports
+type itineraryDocument struct {
+	Lang   string
+	Dir    string // ltr or rtl, detected from the trip name
+	Labels itineraryLabels
+
+	Title        string
+	Summary      string
+	CoverImage   string
+	Author       string
+	DateRange    string
+	Travelers    int
+	Destinations []string
+	Days         []itineraryDay
+	TotalCost    []string
+}
+
+type itineraryDay struct {
+	Heading      string
+	Date         string
+	Destinations string
+	Notes        string
+	Sections     []itinerarySection
+	Cost         []string
+}
+
+type itinerarySection struct {
+	Label string
+	Items []itineraryItem
+}
+
+type itineraryItem struct {
+	Time    string
+	Title   string
+	Details []string // location, duration and cost, when known
+	Notes   string
+	Skipped bool
+}
+
+// renderItinerary renders a trip as a printable document in the given format.
+// A non-nil convert shows every cost in its display currency.
+func renderItinerary(trip *models.Trip, author, format string, convert costConverter) (string, error) {
+	doc := buildItineraryDocument(trip, author, convert)
+	switch format {
+	case DocumentFormatHTML:
+		return renderItineraryHTML(doc)
+	case DocumentFormatMarkdown:
+		return renderItineraryMarkdown(doc), nil
+	default:
+		return "", utils.NewValidationError("format must be 'html' or 'markdown'")
+	}
+}
+
+func buildItineraryDocument(trip *models.Trip, author string, convert costConverter) *itineraryDocument {
+	if convert == nil {
+		convert = func(amount int, currency string) (int, string) { return amount, currency }
+	}
+
+	doc := &itineraryDocument{
+		Lang:       "en",
+		Dir:        textDirection(trip.Name),
+		Title:      trip.Name,
+		Summary:    derefString(trip.Summary),
+		CoverImage: trip.CoverImage,
+		Author:     author,
+		DateRange:  utils.FormatDate(trip.StartDate) + " – " + utils.FormatDate(trip.EndDate),
+		Travelers:  trip.TravelerCount,
+	}
+	if containsHebrew(trip.Name) {
+		doc.Lang = "he"
+	}
+	doc.Labels = itineraryLabelsByLang[doc.Lang]
+
+	for _, td := range trip.TripDestinations {
+		if td.Destination == nil {
+			continue
+		}
+		name := td.Destination.City + ", " + td.Destination.Country
+		if td.StartDate != nil && td.EndDate != nil {
+			name += " (" + utils.FormatDate(*td.StartDate) + " – " + utils.FormatDate(*td.EndDate) + ")"
+		}
+		doc.Destinations = append(doc.Destinations, name)
+	}
+
+	days := make([]*models.DayPlan, len(trip.DayPlans))
+	for i := range trip.DayPlans {
+		days[i] = &trip.DayPlans[i]
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].DayNumber < days[j].DayNumber })
+
+	totals := make(map[string]int)
+	for _, day := range days {
+		loc := dayLocation(trip, day)
+		d := itineraryDay{
+			Heading: fmt.Sprintf("%s %d", doc.Labels.Day, day.DayNumber),
+			Date:    utils.FormatDate(day.Date),
+			Notes:   derefString(day.Notes),
+		}
+
+		var cities []string
+		for _, dpd := range day.DayPlanDestinations {
+			if dpd.Destination != nil {
+				cities = append(cities, dpd.Destination.City)
+			}
+		}
+		d.Destinations = strings.Join(cities, " · ")
+
+		dayCosts := make(map[string]int)
+		for _, dpa := range orderedDayActivities(day) {
+			item := itineraryItem{
+				Title:   derefString(dpa.CustomTitle),
+				Notes:   derefString(dpa.CustomNotes),
+				Skipped: dpa.Skipped,
+			}
+			if dpa.CustomTime != nil {
+				item.Time = dpa.CustomTime.In(loc).Format("15:04")
+			}
+			if act := dpa.Activity; act != nil {
+				if item.Title == "" {
+					item.Title = act.Title
+				}
+				if location := derefString(act.Location); location != "" {
+					item.Details = append(item.Details, location)
+				}
+				if act.DurationMinutes != nil && *act.DurationMinutes > 0 {
+					item.Details = append(item.Details, strconv.Itoa(*act.DurationMinutes)+" "+doc.Labels.Minutes)
+				}
+				if act.EstimatedCostAmount != nil {
+					amount, currency := convert(*act.EstimatedCostAmount, derefString(act.EstimatedCostCurrency))
+					item.Details = append(item.Details, formatCost(amount, currency))
+					if !dpa.Skipped {
+						dayCosts[currency] += amount
+						totals[currency] += amount
+					}
+				}
+			}
+
+			rank := timeOfDayRank[dpa.TimeOfDay]
+			if len(d.Sections) == 0 || d.Sections[len(d.Sections)-1].Label != doc.Labels.Buckets[rank] {
+				d.Sections = append(d.Sections, itinerarySection{Label: doc.Labels.Buckets[rank]})
+			}
+			last := &d.Sections[len(d.Sections)-1]
+			last.Items = append(last.Items, item)
+		}
+		d.Cost = formatCosts(dayCosts)
+		doc.Days = append(doc.Days, d)
+	}
+	doc.TotalCost = formatCosts(totals)
+
+	return doc
+}
+
+var itineraryHTMLTemplate = template.Must(template.New("itinerary").Parse(`<!DOCTYPE html>
+<html lang="{{.Lang}}" dir="{{.Dir}}">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+  @page { margin: 18mm 15mm; }
+  body { font-family: "Segoe UI", Arial, "Noto Sans Hebrew", sans-serif; color: #1f2933; margin: 0 auto; max-width: 820px; padding: 24px; line-height: 1.5; }
+  h1 { margin: 16px 0 4px; font-size: 28px; }
+  h2 { margin: 0; font-size: 20px; }
+  h3 { margin: 12px 0 4px; font-size: 14px; text-transform: uppercase; letter-spacing: .04em; color: #52606d; }
+  .cover { width: 100%; max-height: 320px; object-fit: cover; border-radius: 8px; }
+  .meta, .muted { color: #616e7c; font-size: 14px; }
+  .day { border-top: 2px solid #e4e7eb; padding-top: 12px; margin-top: 20px; break-inside: avoid-page; }
+  .day-header { display: flex; justify-content: space-between; align-items: baseline; gap: 12px; }
+  ul { list-style: none; padding: 0; margin: 0; }
+  li { padding: 6px 0; border-bottom: 1px solid #f0f2f5; }
+  .time { display: inline-block; min-width: 3.5em; font-variant-numeric: tabular-nums; font-weight: 600; }
+  .skipped .title { text-decoration: line-through; color: #9aa5b1; }
+  .details { margin-inline-start: 3.5em; font-size: 13px; color: #616e7c; }
+  .notes { white-space: pre-line; background: #f5f7fa; border-inline-start: 3px solid #cbd2d9; padding: 6px 10px; margin: 8px 0; }
+  .cost { text-align: end; font-size: 14px; }
+  @media print { body { padding: 0; } a { color: inherit; text-decoration: none; } }
+</style>
+</head>
+<body>
+{{if .CoverImage}}<img class="cover" src="{{.CoverImage}}" alt="">{{end}}
+<h1 dir="auto">{{.Title}}</h1>
+<p class="meta"><bdi>{{.DateRange}}</bdi>{{if .Travelers}} · {{.Labels.Travelers}}: {{.Travelers}}{{end}}{{if .Author}} · {{.Labels.By}} <bdi>{{.Author}}</bdi>{{end}}</p>
+{{if .Summary}}<p dir="auto">{{.Summary}}</p>{{end}}
+{{if .Destinations}}
+<h3>{{.Labels.Destinations}}</h3>
+<ul>{{range .Destinations}}<li dir="auto">{{.}}</li>{{end}}</ul>
+{{end}}
+{{range .Days}}
+<section class="day">
+  <div class="day-header"><h2>{{.Heading}}{{if .Destinations}} · <bdi>{{.Destinations}}</bdi>{{end}}</h2><span class="muted"><bdi>{{.Date}}</bdi></span></div>
+  {{if .Notes}}<div class="notes" dir="auto">{{.Notes}}</div>{{end}}
+  {{range .Sections}}
+  <h3>{{.Label}}</h3>
+  <ul>
+    {{range .Items}}
+    <li{{if .Skipped}} class="skipped"{{end}}>
+      <span class="time">{{.Time}}</span> <span class="title" dir="auto">{{.Title}}</span>{{if .Skipped}} <span class="muted">({{$.Labels.Skipped}})</span>{{end}}
+      {{if .Details}}<div class="details">{{range $i, $d := .Details}}{{if $i}} · {{end}}<bdi>{{$d}}</bdi>{{end}}</div>{{end}}
+      {{if .Notes}}<div class="details notes" dir="auto">{{.Notes}}</div>{{end}}
+    </li>
+    {{end}}
+  </ul>
+  {{end}}
+  {{if .Cost}}<p class="cost">{{$.Labels.Cost}}: {{range $i, $c := .Cost}}{{if $i}}, {{end}}<bdi>{{$c}}</bdi>{{end}}</p>{{end}}
+</section>
+{{end}}
+{{if .TotalCost}}<p class="cost"><strong>{{.Labels.TotalCost}}: {{range $i, $c := .TotalCost}}{{if $i}}, {{end}}<bdi>{{$c}}</bdi>{{end}}</strong></p>{{end}}
+</body>
+</html>
+`))
+
+func renderItineraryHTML(doc *itineraryDocument) (string, error) {
+	var buf bytes.Buffer
+	if err := itineraryHTMLTemplate.Execute(&buf, doc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// renderItineraryMarkdown writes CommonMark. RTL documents are wrapped in a dir="rtl" block, and
+// user text is wrapped in Unicode isolates so mixed Hebrew/Latin runs keep their order.
+func renderItineraryMarkdown(doc *itineraryDocument) string {
+	rtl := doc.Dir == "rtl"
+	text := func(s string) string {
+		s = markdownEscape(s)
+		if rtl && s != "" {
+			return "\u2068" + s + "\u2069" // FSI ... PDI
+		}
+		return s
+	}
+
+	var b strings.Builder
+	if rtl {
+		b.WriteString("<div dir=\"rtl\">\n\n")
+	}
+
+	b.WriteString("# " + text(doc.Title) + "\n\n")
+	if doc.CoverImage != "" {
+		b.WriteString("![](" + doc.CoverImage + ")\n\n")
+	}
+	meta := text(doc.DateRange)
+	if doc.Travelers > 0 {
+		meta += fmt.Sprintf(" · %s: %d", doc.Labels.Travelers, doc.Travelers)
+	}
+	if doc.Author != "" {
+		meta += " · " + doc.Labels.By + " " + text(doc.Author)
+	}
+	b.WriteString(meta + "\n\n")
+	if doc.Summary != "" {
+		b.WriteString(text(doc.Summary) + "\n\n")
+	}
+
+	if len(doc.Destinations) > 0 {
+		b.WriteString("## " + doc.Labels.Destinations + "\n\n")
+		for _, dest := range doc.Destinations {
+			b.WriteString("- " + text(dest) + "\n")
+		}
+		b.WriteString("\n")
+	}
+
+	for _, day := range doc.Days {
+		heading := day.Heading
+		if day.Destinations != "" {
+			heading += " · " + text(day.Destinations)
+		}
+		b.WriteString("## " + heading + " — " + text(day.Date) + "\n\n")
+		if day.Notes != "" {
+			for _, line := range strings.Split(day.Notes, "\n") {
+				b.WriteString("> " + text(line) + "\n")
+			}
+			b.WriteString("\n")
+		}
+
+		for _, section := range day.Sections {
+			b.WriteString("### " + section.Label + "\n\n")
+			for _, item := range section.Items {
+				line := "- "
+				if item.Time != "" {
+					line += "**" + item.Time + "** "
+				}
+				title := text(item.Title)
+				if item.Skipped {
+					title = "~~" + title + "~~ (" + doc.Labels.Skipped + ")"
+				}
+				line += title
+
+				details := make([]string, len(item.Details))
+				for i, detail := range item.Details {
+					details[i] = text(detail)
+				}
+				if len(details) > 0 {
+					line += " — " + strings.Join(details, " · ")
+				}
+				b.WriteString(line + "\n")
+				if item.Notes != "" {
+					for _, noteLine := range strings.Split(item.Notes, "\n") {
+						b.WriteString("  > " + text(noteLine) + "\n")
+					}
+				}
+			}
+			b.WriteString("\n")
+		}
+
+		if len(day.Cost) > 0 {
+			b.WriteString("_" + doc.Labels.Cost + ": " + text(strings.Join(day.Cost, ", ")) + "_\n\n")
+		}
+	}
+
+	if len(doc.TotalCost) > 0 {
+		b.WriteString("**" + doc.Labels.TotalCost + ": " + text(strings.Join(doc.TotalCost, ", ")) + "**\n\n")
+	}
+
+	if rtl {
+		b.WriteString("</div>\n")
+	}
+	return b.String()
+}
+
+// textDirection returns "rtl" when the first strongly directional letter is Hebrew or Arabic
+func textDirection(s string) string {
+	for _, r := range s {
+		if unicode.In(r, unicode.Hebrew, unicode.Arabic) {
+			return "rtl"
+		}
+		if unicode.IsLetter(r) {
+			return "ltr"
+		}
+	}
+	return "ltr"
+}
+
+func containsHebrew(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Hebrew, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// markdownEscape escapes characters that would otherwise be read as Markdown syntax
+func markdownEscape(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`,
+		"#", `\#`, "<", "&lt;", ">", "&gt;", "|", `\|`, "~", `\~`,
+	)
+	return replacer.Replace(s)
+}
+
+// formatCosts formats per-currency sums in a stable order
+func formatCosts(costs map[string]int) []string {
+	currencies := make([]string, 0, len(costs))
+	for currency := range costs {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	formatted := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		formatted = append(formatted, formatCost(costs[currency], currency))
+	}
+	return formatted
+}
+
+// formatCost formats an amount with thousands separators, e.g. "12,500 JPY"
+func formatCost(amount int, currency string) string {
+	digits := strconv.Itoa(amount)
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+
+	var grouped strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteRune(',')
+		}
+		grouped.WriteRune(r)
+	}
+
+	result := grouped.String()
+	if negative {
+		result = "-" + result
+	}
+	if currency != "" {
+		result += " " + currency
+	}
+	return result
+}
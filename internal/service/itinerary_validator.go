@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// farFromDestinationKm is how far an activity can be from its day's destinations before it is flagged
+const farFromDestinationKm = 50
+
+// ItineraryValidator defines the interface for checking a trip's itinerary for conflicts
+type ItineraryValidator interface {
+	ValidateTrip(ctx context.Context, ownerID, tripID string) (*dto.ItineraryValidationResponse, error)
+}
+
+type itineraryValidator struct {
+	tripRepo repository.TripRepository
+}
+
+// NewItineraryValidator creates a new itinerary validator instance
+func NewItineraryValidator(tripRepo repository.TripRepository) ItineraryValidator {
+	return &itineraryValidator{tripRepo: tripRepo}
+}
+
+func (v *itineraryValidator) ValidateTrip(ctx context.Context, ownerID, tripID string) (*dto.ItineraryValidationResponse, error) {
+	trip, err := v.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+
+	warnings := validateItinerary(trip)
+	return &dto.ItineraryValidationResponse{
+		TripID:   tripID,
+		Valid:    len(warnings) == 0,
+		Warnings: warnings,
+	}, nil
+}
+
+// validateItinerary checks the day sequence, each day's schedule and the destination date ranges
+func validateItinerary(trip *models.Trip) []dto.ItineraryWarning {
+	warnings := []dto.ItineraryWarning{}
+	warnings = append(warnings, validateDaySequence(trip)...)
+	for i := range trip.DayPlans {
+		day := &trip.DayPlans[i]
+		warnings = append(warnings, validateDaySchedule(day)...)
+		warnings = append(warnings, validateDayDistances(trip, day)...)
+	}
+	warnings = append(warnings, validateDestinationRanges(trip)...)
+	return warnings
+}
+
+// validateDaySequence flags duplicate or missing day numbers, and dates that fall outside
+// the trip, skip days or run backwards
+func validateDaySequence(trip *models.Trip) []dto.ItineraryWarning {
+	var warnings []dto.ItineraryWarning
+
+	days := make([]*models.DayPlan, len(trip.DayPlans))
+	for i := range trip.DayPlans {
+		days[i] = &trip.DayPlans[i]
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].DayNumber < days[j].DayNumber })
+
+	tripStart, tripEnd := utils.FormatDate(trip.StartDate), utils.FormatDate(trip.EndDate)
+	for i, day := range days {
+		date := utils.FormatDate(day.Date)
+		if (tripStart != "" && date < tripStart) || (tripEnd != "" && date > tripEnd) {
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code:    dto.WarningDayOutsideTripDates,
+				Message: fmt.Sprintf("Day %d (%s) is outside the trip dates %s to %s", day.DayNumber, date, tripStart, tripEnd),
+				DayID:   day.ID,
+			})
+		}
+
+		if i == 0 {
+			if day.DayNumber != 1 {
+				warnings = append(warnings, dto.ItineraryWarning{
+					Code:    dto.WarningDayNumberGap,
+					Message: fmt.Sprintf("The first day is numbered %d", day.DayNumber),
+					DayID:   day.ID,
+				})
+			}
+			continue
+		}
+
+		previous := days[i-1]
+		switch {
+		case day.DayNumber == previous.DayNumber:
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code:    dto.WarningDuplicateDayNumber,
+				Message: fmt.Sprintf("Two days are numbered %d", day.DayNumber),
+				DayID:   day.ID,
+			})
+		case day.DayNumber > previous.DayNumber+1:
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code:    dto.WarningDayNumberGap,
+				Message: fmt.Sprintf("Days %d to %d are missing", previous.DayNumber+1, day.DayNumber-1),
+				DayID:   day.ID,
+			})
+		}
+
+		gap, ok := daysBetween(previous.Date, day.Date)
+		switch {
+		case !ok:
+		case gap <= 0:
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code:    dto.WarningDaysOutOfOrder,
+				Message: fmt.Sprintf("Day %d (%s) is not after day %d (%s)", day.DayNumber, date, previous.DayNumber, utils.FormatDate(previous.Date)),
+				DayID:   day.ID,
+			})
+		case gap > 1:
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code:    dto.WarningDateGap,
+				Message: fmt.Sprintf("No day is planned between %s and %s", utils.FormatDate(previous.Date), date),
+				DayID:   day.ID,
+			})
+		}
+	}
+	return warnings
+}
+
+// validateDaySchedule flags activities whose custom times overlap, taking each activity's duration into account
+func validateDaySchedule(day *models.DayPlan) []dto.ItineraryWarning {
+	type slot struct {
+		dpa        models.DayPlanActivity
+		start, end time.Time
+	}
+	var slots []slot
+	for _, dpa := range day.DayPlanActivities {
+		if dpa.CustomTime == nil || dpa.Skipped {
+			continue
+		}
+		end := *dpa.CustomTime
+		if dpa.Activity != nil && dpa.Activity.DurationMinutes != nil {
+			end = end.Add(time.Duration(*dpa.Activity.DurationMinutes) * time.Minute)
+		}
+		slots = append(slots, slot{dpa: dpa, start: *dpa.CustomTime, end: end})
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].start.Before(slots[j].start) })
+
+	var warnings []dto.ItineraryWarning
+	for i := range slots {
+		for j := i + 1; j < len(slots); j++ {
+			a, b := slots[i], slots[j]
+			if !b.start.Before(a.end) && !b.start.Equal(a.start) {
+				break
+			}
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code: dto.WarningOverlappingTimes,
+				Message: fmt.Sprintf("Day %d: %q at %s overlaps %q at %s", day.DayNumber,
+					activityTitle(a.dpa), a.start.Format("15:04"), activityTitle(b.dpa), b.start.Format("15:04")),
+				DayID:              day.ID,
+				DayPlanActivityIDs: []string{a.dpa.ID, b.dpa.ID},
+			})
+		}
+	}
+	return warnings
+}
+
+// validateDayDistances flags located activities far from every destination of their day. Days
+// without destinations are checked against the trip destinations whose dates cover them.
+func validateDayDistances(trip *models.Trip, day *models.DayPlan) []dto.ItineraryWarning {
+	var destinations []*models.Destination
+	for _, dpd := range day.DayPlanDestinations {
+		if dpd.Destination != nil {
+			destinations = append(destinations, dpd.Destination)
+		}
+	}
+	if len(day.DayPlanDestinations) == 0 {
+		date := utils.FormatDate(day.Date)
+		for _, td := range trip.TripDestinations {
+			if td.Destination == nil || td.StartDate == nil || td.EndDate == nil {
+				continue
+			}
+			if date >= utils.FormatDate(*td.StartDate) && date <= utils.FormatDate(*td.EndDate) {
+				destinations = append(destinations, td.Destination)
+			}
+		}
+	}
+
+	var located []*models.Destination
+	for _, dest := range destinations {
+		if dest.Latitude != nil && dest.Longitude != nil {
+			located = append(located, dest)
+		}
+	}
+	if len(located) == 0 {
+		return nil
+	}
+
+	var warnings []dto.ItineraryWarning
+	for _, dpa := range day.DayPlanActivities {
+		a := dpa.Activity
+		if a == nil || a.Latitude == nil || a.Longitude == nil || dpa.Skipped {
+			continue
+		}
+		var nearest *models.Destination
+		nearestKm := 0.0
+		for _, dest := range located {
+			km := haversineKm(*a.Latitude, *a.Longitude, *dest.Latitude, *dest.Longitude)
+			if nearest == nil || km < nearestKm {
+				nearest, nearestKm = dest, km
+			}
+		}
+		if nearestKm > farFromDestinationKm {
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code:               dto.WarningFarFromDestination,
+				Message:            fmt.Sprintf("Day %d: %q is %.0f km from %s", day.DayNumber, activityTitle(dpa), nearestKm, nearest.City),
+				DayID:              day.ID,
+				DayPlanActivityIDs: []string{dpa.ID},
+			})
+		}
+	}
+	return warnings
+}
+
+// validateDestinationRanges flags trip destination date ranges that are reversed, fall outside
+// the trip, overlap or leave days uncovered. Consecutive stays may share their travel day.
+func validateDestinationRanges(trip *models.Trip) []dto.ItineraryWarning {
+	var warnings []dto.ItineraryWarning
+	var ranged []models.TripDestination
+	tripStart, tripEnd := utils.FormatDate(trip.StartDate), utils.FormatDate(trip.EndDate)
+	for _, td := range trip.TripDestinations {
+		if td.StartDate == nil || td.EndDate == nil {
+			continue
+		}
+		start, end := utils.FormatDate(*td.StartDate), utils.FormatDate(*td.EndDate)
+		name := tripDestinationName(td)
+		if start > end {
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code:               dto.WarningDestinationRangeInvalid,
+				Message:            fmt.Sprintf("%s starts on %s, after it ends on %s", name, start, end),
+				TripDestinationIDs: []string{td.ID},
+			})
+			continue
+		}
+		if start < tripStart || end > tripEnd {
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code:               dto.WarningDestinationOutsideTrip,
+				Message:            fmt.Sprintf("%s (%s to %s) is outside the trip dates %s to %s", name, start, end, tripStart, tripEnd),
+				TripDestinationIDs: []string{td.ID},
+			})
+		}
+		ranged = append(ranged, td)
+	}
+
+	sort.SliceStable(ranged, func(i, j int) bool {
+		return utils.FormatDate(*ranged[i].StartDate) < utils.FormatDate(*ranged[j].StartDate)
+	})
+	// Compare each range against the one reaching furthest so far, as an earlier long stay
+	// can cover a later one entirely
+	latest := 0
+	for i := 1; i < len(ranged); i++ {
+		previous, td := ranged[latest], ranged[i]
+		if utils.FormatDate(*td.EndDate) > utils.FormatDate(*previous.EndDate) {
+			latest = i
+		}
+		gap, ok := daysBetween(*previous.EndDate, *td.StartDate)
+		switch {
+		case !ok:
+		case gap < 0:
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code: dto.WarningDestinationRangesOverlap,
+				Message: fmt.Sprintf("%s (from %s) starts before %s ends (%s)", tripDestinationName(td),
+					utils.FormatDate(*td.StartDate), tripDestinationName(previous), utils.FormatDate(*previous.EndDate)),
+				TripDestinationIDs: []string{previous.ID, td.ID},
+			})
+		case gap > 1:
+			warnings = append(warnings, dto.ItineraryWarning{
+				Code: dto.WarningDestinationRangeGap,
+				Message: fmt.Sprintf("No destination covers %s to %s, between %s and %s",
+					utils.AddDays(*previous.EndDate, 1), utils.AddDays(*td.StartDate, -1), tripDestinationName(previous), tripDestinationName(td)),
+				TripDestinationIDs: []string{previous.ID, td.ID},
+			})
+		}
+	}
+	return warnings
+}
+
+// daysBetween counts the calendar days from one date to another
+func daysBetween(from, to string) (int, bool) {
+	start, err := time.Parse(utils.DateLayout, utils.FormatDate(from))
+	if err != nil {
+		return 0, false
+	}
+	end, err := time.Parse(utils.DateLayout, utils.FormatDate(to))
+	if err != nil {
+		return 0, false
+	}
+	return int(end.Sub(start).Hours() / 24), true
+}
+
+func tripDestinationName(td models.TripDestination) string {
+	if td.Destination != nil {
+		return td.Destination.City
+	}
+	return "Destination " + td.DestinationID
+}
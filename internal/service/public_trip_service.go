@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"time"
 	"triply-server/internal/dto"
 	"triply-server/internal/models"
@@ -14,22 +16,25 @@ import (
 // PublicTripService defines the interface for public trip operations
 type PublicTripService interface {
 	ListPublicTrips(ctx context.Context, req *dto.ListPublicTripsRequest, userID *string) (*dto.ListPublicTripsResponse, error)
-	GetPublicTrip(ctx context.Context, tripID string, userID *string) (*dto.PublicTripDetail, error)
+	GetPublicTrip(ctx context.Context, tripID string, userID *string, currency string) (*dto.PublicTripDetail, error)
 	ToggleVisibility(ctx context.Context, userID, tripID, visibility string) (*dto.PublicTripDetail, error)
+	ExportPublicTrip(ctx context.Context, tripID, format, currency string) (string, error)
 }
 
 type publicTripService struct {
-	publicTripRepo repository.PublicTripRepository
-	tripRepo       repository.TripRepository
-	tripLikeRepo   repository.TripLikeRepository
+	publicTripRepo  repository.PublicTripRepository
+	tripRepo        repository.TripRepository
+	tripLikeRepo    repository.TripLikeRepository
+	currencyService CurrencyService
 }
 
 // NewPublicTripService creates a new public trip service instance
-func NewPublicTripService(publicTripRepo repository.PublicTripRepository, tripRepo repository.TripRepository, tripLikeRepo repository.TripLikeRepository) PublicTripService {
+func NewPublicTripService(publicTripRepo repository.PublicTripRepository, tripRepo repository.TripRepository, tripLikeRepo repository.TripLikeRepository, currencyService CurrencyService) PublicTripService {
 	return &publicTripService{
-		publicTripRepo: publicTripRepo,
-		tripRepo:       tripRepo,
-		tripLikeRepo:   tripLikeRepo,
+		publicTripRepo:  publicTripRepo,
+		tripRepo:        tripRepo,
+		tripLikeRepo:    tripLikeRepo,
+		currencyService: currencyService,
 	}
 }
 
@@ -109,7 +114,13 @@ func (s *publicTripService) ListPublicTrips(ctx context.Context, req *dto.ListPu
 	}, nil
 }
 
-func (s *publicTripService) GetPublicTrip(ctx context.Context, tripID string, userID *string) (*dto.PublicTripDetail, error) {
+// GetPublicTrip returns the public trip's details, with its estimated costs in the currency if one is given
+func (s *publicTripService) GetPublicTrip(ctx context.Context, tripID string, userID *string, currency string) (*dto.PublicTripDetail, error) {
+	convert, missing, err := s.costConverter(ctx, currency)
+	if err != nil {
+		return nil, err
+	}
+
 	publicTrip, err := s.publicTripRepo.FindByID(ctx, tripID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -119,6 +130,11 @@ func (s *publicTripService) GetPublicTrip(ctx context.Context, tripID string, us
 	}
 
 	detail := s.toPublicTripDetail(publicTrip)
+	detail.Costs = estimatePublicTripCosts(detail.Itinerary, convert)
+	if convert != nil {
+		detail.Costs.Currency = strings.ToUpper(currency)
+		detail.Costs.UnconvertedCurrencies = missingCurrencies(missing)
+	}
 
 	// If user is authenticated, check if they liked this trip
 	if userID != nil {
@@ -137,6 +153,11 @@ func (s *publicTripService) ToggleVisibility(ctx context.Context, userID, tripID
 		return nil, utils.NewValidationError("visibility must be 'public' or 'private'")
 	}
 
+	// Only the owner decides whether the trip is published
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleOwner); err != nil {
+		return nil, err
+	}
+
 	// Toggle visibility
 	if err := s.publicTripRepo.ToggleVisibility(ctx, tripID, userID, visibility); err != nil {
 		return nil, err
@@ -151,6 +172,84 @@ func (s *publicTripService) ToggleVisibility(ctx context.Context, userID, tripID
 	return s.toPublicTripDetail(trip), nil
 }
 
+// ExportPublicTrip renders a public trip as a read-only printable HTML or Markdown itinerary,
+// showing costs in the currency if one is given
+func (s *publicTripService) ExportPublicTrip(ctx context.Context, tripID, format, currency string) (string, error) {
+	convert, _, err := s.costConverter(ctx, currency)
+	if err != nil {
+		return "", err
+	}
+
+	publicTrip, err := s.publicTripRepo.FindByID(ctx, tripID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", utils.NewNotFoundError("Public trip")
+		}
+		return "", err
+	}
+
+	// Render only what the public detail exposes
+	detail := s.toPublicTripDetail(publicTrip)
+	publicTrip.DayPlans = detail.Itinerary
+
+	return renderItinerary(publicTrip, detail.Author.Name, format, convert)
+}
+
+// costConverter builds a converter into the requested display currency at the latest rates.
+// It returns nil when no currency is requested.
+func (s *publicTripService) costConverter(ctx context.Context, currency string) (costConverter, map[string]bool, error) {
+	currency, err := normalizeCurrencyQuery(currency)
+	if err != nil || currency == "" {
+		return nil, nil, err
+	}
+
+	rates, err := s.currencyService.RatesOn(ctx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	missing := map[string]bool{}
+	return rates.costConverter(currency, missing), missing, nil
+}
+
+// estimatePublicTripCosts sums the estimated costs of the itinerary's activities per day and
+// in total, skipping skipped activities as the printable itinerary does
+func estimatePublicTripCosts(days []models.DayPlan, convert costConverter) *dto.PublicTripCosts {
+	if convert == nil {
+		convert = func(amount int, currency string) (int, string) { return amount, currency }
+	}
+
+	costs := &dto.PublicTripCosts{Days: make([]dto.PublicDayCost, 0, len(days))}
+	totals := make(map[string]int)
+	for _, day := range days {
+		dayCosts := make(map[string]int)
+		for _, dpa := range day.DayPlanActivities {
+			if dpa.Skipped || dpa.Activity == nil || dpa.Activity.EstimatedCostAmount == nil {
+				continue
+			}
+			amount, currency := convert(*dpa.Activity.EstimatedCostAmount, strings.ToUpper(derefString(dpa.Activity.EstimatedCostCurrency)))
+			dayCosts[currency] += amount
+			totals[currency] += amount
+		}
+		costs.Days = append(costs.Days, dto.PublicDayCost{
+			DayID:     day.ID,
+			DayNumber: day.DayNumber,
+			Total:     costAmounts(dayCosts),
+		})
+	}
+	costs.Total = costAmounts(totals)
+	return costs
+}
+
+// costAmounts lists per-currency sums ordered by currency
+func costAmounts(costs map[string]int) []dto.CostAmount {
+	amounts := make([]dto.CostAmount, 0, len(costs))
+	for currency, amount := range costs {
+		amounts = append(amounts, dto.CostAmount{Currency: currency, Amount: amount})
+	}
+	sort.Slice(amounts, func(i, j int) bool { return amounts[i].Currency < amounts[j].Currency })
+	return amounts
+}
+
 // Helper methods to convert models to DTOs
 func (s *publicTripService) toPublicTripSummary(trip *models.Trip) dto.PublicTripSummary {
 	// Extract origin cities from destinations
@@ -248,6 +347,7 @@ func (s *publicTripService) formatDate(dateStr string) string {
 
 func (s *publicTripService) toPublicTripDetail(trip *models.Trip) *dto.PublicTripDetail {
 	summary := s.toPublicTripSummary(trip)
+	localizeTrip(trip)
 
 	// Build author info
 	author := dto.Author{
@@ -269,9 +369,25 @@ func (s *publicTripService) toPublicTripDetail(trip *models.Trip) *dto.PublicTri
 		Likes:     trip.Likes,
 	}
 
+	// Reservations and booking references stay private to the trip's members
+	trip.Reservations = nil
+	for i := range trip.DayPlans {
+		activities := trip.DayPlans[i].DayPlanActivities
+		for j := range activities {
+			activities[j].Reservations = nil
+		}
+	}
+	legs := make([]models.TripLeg, len(trip.TripLegs))
+	for i, leg := range trip.TripLegs {
+		leg.BookingReference = nil
+		leg.Reservations = nil
+		legs[i] = leg
+	}
+
 	return &dto.PublicTripDetail{
 		PublicTripSummary: summary,
 		Itinerary:         trip.DayPlans,
+		Legs:              legs,
 		Author:            author,
 		Metadata:          metadata,
 	}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// dayAvailableMinutes is the time a day offers for activities and travel (09:00 to 21:00)
+const dayAvailableMinutes = 12 * 60
+
+// walkingLimitMinutes is the longest leg suggested on foot
+const walkingLimitMinutes = 20
+
+// travelEstimateTimeout bounds the time a response waits for the routing provider
+const travelEstimateTimeout = 5 * time.Second
+
+// travelEstimateWorkers is the number of legs estimated at the same time
+const travelEstimateWorkers = 4
+
+// RouteService defines the interface for planning the route through a day's activities
+type RouteService interface {
+	OptimizeDay(ctx context.Context, ownerID, tripID, dayID string) (*dto.RouteOptimizationResponse, error)
+	GetDayTravel(ctx context.Context, ownerID, tripID, dayID string) (*dto.DayTravel, error)
+	// DayTravel computes the legs of an already loaded day
+	DayTravel(ctx context.Context, day *models.DayPlan) *dto.DayTravel
+	DaysTravel(ctx context.Context, days []models.DayPlan) []dto.DayTravel
+}
+
+type routeService struct {
+	tripRepo repository.TripRepository
+	matrix   DistanceMatrix
+	routing  RoutingProvider
+	fallback RoutingProvider
+}
+
+// NewRouteService creates a new route service instance. A nil matrix measures
+// straight-line distances, and a nil routing provider estimates travel offline.
+func NewRouteService(tripRepo repository.TripRepository, matrix DistanceMatrix, routing RoutingProvider) RouteService {
+	if matrix == nil {
+		matrix = NewHaversineMatrix()
+	}
+	fallback := NewStraightLineRouting()
+	if routing == nil {
+		routing = fallback
+	}
+	return &routeService{tripRepo: tripRepo, matrix: matrix, routing: routing, fallback: fallback}
+}
+
+// routeStop is an activity being placed on the route
+type routeStop struct {
+	dpa   models.DayPlanActivity
+	point int  // index into the distance matrix, -1 without coordinates
+	fixed bool // has a custom time
+}
+
+// OptimizeDay proposes an order of the day's activities that minimizes the distance
+// travelled between them. Activities stay in their TimeOfDay bucket and activities with a
+// CustomTime keep their chronological order; only the others are moved around them.
+func (s *routeService) OptimizeDay(ctx context.Context, ownerID, tripID, dayID string) (*dto.RouteOptimizationResponse, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+	day := findTripDay(trip, dayID)
+	if day == nil {
+		return nil, utils.NewNotFoundError("Day plan")
+	}
+
+	// Index the activities that take part in the route: located and not skipped
+	current := orderedDayActivities(day)
+	var points []GeoPoint
+	stops := make([]*routeStop, len(current))
+	for i, dpa := range current {
+		stop := &routeStop{dpa: dpa, point: -1, fixed: dpa.CustomTime != nil}
+		if a := dpa.Activity; a != nil && a.Latitude != nil && a.Longitude != nil && !dpa.Skipped {
+			stop.point = len(points)
+			points = append(points, GeoPoint{Latitude: *a.Latitude, Longitude: *a.Longitude})
+		}
+		stops[i] = stop
+	}
+
+	distances, err := s.matrix.Distances(ctx, points)
+	if err != nil {
+		return nil, err
+	}
+
+	route := optimizeRoute(stops, distances)
+
+	response := &dto.RouteOptimizationResponse{
+		DayID:                   dayID,
+		CurrentDistanceMeters:   int(math.Round(routeDistance([][]*routeStop{stops}, distances))),
+		OptimizedDistanceMeters: int(math.Round(routeDistance(route, distances))),
+		Unlocated:               []string{},
+		Order: dto.ActivityOrderRequest{
+			TripID: tripID,
+			DayID:  dayID,
+		},
+	}
+	response.SavedMeters = response.CurrentDistanceMeters - response.OptimizedDistanceMeters
+
+	previous := -1
+	for _, bucket := range route {
+		for order, stop := range bucket {
+			dpa := stop.dpa
+			routeStop := dto.RouteStop{
+				ID:              dpa.ID,
+				ActivityID:      dpa.ActivityID,
+				Title:           activityTitle(dpa),
+				TimeOfDay:       dpa.TimeOfDay,
+				OrderWithinTime: order,
+				CustomTime:      dpa.CustomTime,
+				Fixed:           stop.fixed,
+			}
+			if dpa.Activity != nil {
+				routeStop.Latitude = dpa.Activity.Latitude
+				routeStop.Longitude = dpa.Activity.Longitude
+			}
+			if stop.point >= 0 {
+				if previous >= 0 {
+					meters := int(math.Round(distances[previous][stop.point]))
+					routeStop.DistanceFromPreviousMeters = &meters
+				}
+				previous = stop.point
+			} else if routeStop.Latitude == nil || routeStop.Longitude == nil {
+				response.Unlocated = append(response.Unlocated, dpa.ID)
+			}
+
+			response.Stops = append(response.Stops, routeStop)
+			response.Order.Activities = append(response.Order.Activities, dto.ActivityOrderItem{
+				ID:              dpa.ID,
+				TimeOfDay:       dpa.TimeOfDay,
+				OrderWithinTime: order,
+			})
+		}
+	}
+	return response, nil
+}
+
+func (s *routeService) GetDayTravel(ctx context.Context, ownerID, tripID, dayID string) (*dto.DayTravel, error) {
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+	day := findTripDay(trip, dayID)
+	if day == nil {
+		return nil, utils.NewNotFoundError("Day plan")
+	}
+	return s.DayTravel(ctx, day), nil
+}
+
+// DayTravel estimates the legs between consecutive located activities of the day, skipping
+// skipped ones, and warns when their durations plus travel do not fit the day. A leg the
+// routing provider cannot estimate falls back to the straight-line estimate.
+func (s *routeService) DayTravel(ctx context.Context, day *models.DayPlan) *dto.DayTravel {
+	travels := s.DaysTravel(ctx, []models.DayPlan{*day})
+	return &travels[0]
+}
+
+// pendingLeg is a leg of travels[day].Legs[index] waiting for its estimate
+type pendingLeg struct {
+	day, index int
+	from, to   GeoPoint
+}
+
+// DaysTravel is DayTravel for several days, whose legs are estimated together under one deadline
+func (s *routeService) DaysTravel(ctx context.Context, days []models.DayPlan) []dto.DayTravel {
+	travels := make([]dto.DayTravel, len(days))
+	var pending []pendingLeg
+	for i := range days {
+		travel := &travels[i]
+		*travel = dto.DayTravel{
+			DayID:            days[i].ID,
+			Legs:             []dto.TravelLeg{},
+			AvailableMinutes: dayAvailableMinutes,
+		}
+
+		var previous *models.DayPlanActivity
+		var from GeoPoint
+		for _, dpa := range orderedDayActivities(&days[i]) {
+			if dpa.Skipped || dpa.Activity == nil {
+				continue
+			}
+			if dpa.Activity.DurationMinutes != nil {
+				travel.ActivityMinutes += *dpa.Activity.DurationMinutes
+			}
+			if dpa.Activity.Latitude == nil || dpa.Activity.Longitude == nil {
+				continue
+			}
+
+			dpa := dpa
+			to := GeoPoint{Latitude: *dpa.Activity.Latitude, Longitude: *dpa.Activity.Longitude}
+			if previous != nil {
+				travel.Legs = append(travel.Legs, dto.TravelLeg{FromID: previous.ID, ToID: dpa.ID})
+				pending = append(pending, pendingLeg{day: i, index: len(travel.Legs) - 1, from: from, to: to})
+			}
+			previous, from = &dpa, to
+		}
+	}
+
+	s.estimateLegs(ctx, travels, pending)
+
+	for i := range travels {
+		travel := &travels[i]
+		for _, leg := range travel.Legs {
+			for _, estimate := range leg.Estimates {
+				if estimate.Mode == leg.SuggestedMode {
+					travel.TravelMinutes += estimate.DurationMinutes
+				}
+			}
+		}
+		if total := travel.ActivityMinutes + travel.TravelMinutes; total > travel.AvailableMinutes {
+			travel.OverCapacity = true
+			travel.Warning = fmt.Sprintf("The day's activities and travel take %s, more than the %s available",
+				formatMinutes(total), formatMinutes(travel.AvailableMinutes))
+		}
+	}
+	return travels
+}
+
+// estimateLegs fills in the pending legs, a few at a time. Legs the routing provider has not
+// estimated by the deadline get the straight-line estimate, so a slow provider cannot stall a response.
+func (s *routeService) estimateLegs(ctx context.Context, travels []dto.DayTravel, pending []pendingLeg) {
+	ctx, cancel := context.WithTimeout(ctx, travelEstimateTimeout)
+	defer cancel()
+
+	slots := make(chan struct{}, travelEstimateWorkers)
+	var wg sync.WaitGroup
+	for _, p := range pending {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(p pendingLeg) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			leg := s.estimateLeg(ctx, p.from, p.to)
+			target := &travels[p.day].Legs[p.index]
+			leg.FromID, leg.ToID = target.FromID, target.ToID
+			*target = leg
+		}(p)
+	}
+	wg.Wait()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		log.Printf("Travel estimates with %s took longer than %s; used %s estimates for the rest", s.routing.Name(), travelEstimateTimeout, s.fallback.Name())
+	}
+}
+
+func (s *routeService) estimateLeg(ctx context.Context, from, to GeoPoint) dto.TravelLeg {
+	leg := dto.TravelLeg{
+		StraightDistanceMeters: int(math.Round(haversineKm(from.Latitude, from.Longitude, to.Latitude, to.Longitude) * 1000)),
+		Provider:               s.routing.Name(),
+	}
+	estimates, err := s.routing.Estimate(ctx, from, to)
+	if err != nil || len(estimates) == 0 {
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to estimate travel with %s, falling back to %s: %v", s.routing.Name(), s.fallback.Name(), err)
+		}
+		estimates, _ = s.fallback.Estimate(ctx, from, to)
+		leg.Provider = s.fallback.Name()
+	}
+	leg.Estimates = estimates
+	leg.SuggestedMode = suggestedTravelMode(estimates)
+	return leg
+}
+
+// suggestedTravelMode walks short hops and otherwise prefers transit over driving
+func suggestedTravelMode(estimates []dto.TravelEstimate) string {
+	byMode := make(map[string]dto.TravelEstimate, len(estimates))
+	for _, estimate := range estimates {
+		byMode[estimate.Mode] = estimate
+	}
+	if walk, ok := byMode[TravelModeWalk]; ok && walk.DurationMinutes <= walkingLimitMinutes {
+		return TravelModeWalk
+	}
+	for _, mode := range []string{TravelModeTransit, TravelModeDrive, TravelModeWalk} {
+		if _, ok := byMode[mode]; ok {
+			return mode
+		}
+	}
+	return ""
+}
+
+func formatMinutes(minutes int) string {
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// optimizeRoute orders the stops bucket by bucket. Fixed-time stops are laid out first in
+// time order; the other located stops are added by cheapest insertion and then improved by
+// moving single stops and reversing runs between fixed stops until nothing shortens the
+// day. Stops that are not on the route end their bucket in their current order.
+func optimizeRoute(stops []*routeStop, distances [][]float64) [][]*routeStop {
+	var route, trailing [][]*routeStop
+	var flexible [][]*routeStop
+	bucketOf := map[int]int{}
+	for _, stop := range stops {
+		rank := timeOfDayRank[stop.dpa.TimeOfDay]
+		b, ok := bucketOf[rank]
+		if !ok {
+			b = len(route)
+			bucketOf[rank] = b
+			route = append(route, nil)
+			trailing = append(trailing, nil)
+			flexible = append(flexible, nil)
+		}
+		switch {
+		case stop.fixed:
+			route[b] = append(route[b], stop)
+		case stop.point < 0:
+			trailing[b] = append(trailing[b], stop)
+		default:
+			flexible[b] = append(flexible[b], stop)
+		}
+	}
+	for b := range route {
+		sort.SliceStable(route[b], func(i, j int) bool {
+			return route[b][i].dpa.CustomTime.Before(*route[b][j].dpa.CustomTime)
+		})
+	}
+
+	// Cheapest insertion
+	for b := range route {
+		for _, stop := range flexible[b] {
+			route[b] = insertAt(route[b], len(route[b]), stop)
+			best, bestCost := len(route[b])-1, routeDistance(route, distances)
+			route[b] = removeAt(route[b], best)
+			for pos := 0; pos < len(route[b]); pos++ {
+				route[b] = insertAt(route[b], pos, stop)
+				if cost := routeDistance(route, distances); cost < bestCost-1e-9 {
+					best, bestCost = pos, cost
+				}
+				route[b] = removeAt(route[b], pos)
+			}
+			route[b] = insertAt(route[b], best, stop)
+		}
+	}
+
+	// Local search: relocate single stops and reverse runs of movable stops
+	cost := routeDistance(route, distances)
+	for round := 0; round < 100; round++ {
+		improved := false
+		for b := range route {
+			for i := 0; i < len(route[b]); i++ {
+				stop := route[b][i]
+				if stop.fixed {
+					continue
+				}
+				route[b] = removeAt(route[b], i)
+				best, bestCost := i, cost
+				for pos := 0; pos <= len(route[b]); pos++ {
+					if pos == i {
+						continue
+					}
+					route[b] = insertAt(route[b], pos, stop)
+					if c := routeDistance(route, distances); c < bestCost-1e-9 {
+						best, bestCost = pos, c
+					}
+					route[b] = removeAt(route[b], pos)
+				}
+				route[b] = insertAt(route[b], best, stop)
+				if best != i {
+					cost, improved = bestCost, true
+				}
+			}
+
+			for i := 0; i < len(route[b]); i++ {
+				if route[b][i].fixed {
+					continue
+				}
+				for j := i + 1; j < len(route[b]) && !route[b][j].fixed; j++ {
+					reverse(route[b][i : j+1])
+					if c := routeDistance(route, distances); c < cost-1e-9 {
+						cost, improved = c, true
+					} else {
+						reverse(route[b][i : j+1])
+					}
+				}
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	for b := range route {
+		route[b] = append(route[b], trailing[b]...)
+	}
+	return route
+}
+
+// routeDistance sums the distances between consecutive stops with coordinates
+func routeDistance(route [][]*routeStop, distances [][]float64) float64 {
+	total := 0.0
+	previous := -1
+	for _, bucket := range route {
+		for _, stop := range bucket {
+			if stop.point < 0 {
+				continue
+			}
+			if previous >= 0 {
+				total += distances[previous][stop.point]
+			}
+			previous = stop.point
+		}
+	}
+	return total
+}
+
+func insertAt(stops []*routeStop, i int, stop *routeStop) []*routeStop {
+	stops = append(stops, nil)
+	copy(stops[i+1:], stops[i:])
+	stops[i] = stop
+	return stops
+}
+
+func removeAt(stops []*routeStop, i int) []*routeStop {
+	return append(stops[:i], stops[i+1:]...)
+}
+
+func reverse(stops []*routeStop) {
+	for i, j := 0, len(stops)-1; i < j; i, j = i+1, j-1 {
+		stops[i], stops[j] = stops[j], stops[i]
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+	"triply-server/internal/dto"
+)
+
+// Travel modes
+const (
+	TravelModeWalk    = "walk"
+	TravelModeTransit = "transit"
+	TravelModeDrive   = "drive"
+)
+
+// RoutingProvider defines the interface for estimating travel between two places. The
+// default implementation works offline from straight-line distances; the Google
+// implementation asks the Directions API.
+type RoutingProvider interface {
+	// Name identifies the provider in responses
+	Name() string
+	// Estimate returns the distance and duration of the trip for every mode that can make it
+	Estimate(ctx context.Context, from, to GeoPoint) ([]dto.TravelEstimate, error)
+}
+
+// straightLineMode turns a straight-line distance into a travel estimate: the distance is
+// stretched by a detour factor and covered at an average speed, after a fixed overhead
+type straightLineMode struct {
+	mode         string
+	detourFactor float64
+	speedKmh     float64
+	overheadMin  float64 // waiting for transit, parking, ...
+	maxKm        float64 // longer trips are not offered in this mode
+}
+
+var straightLineModes = []straightLineMode{
+	{mode: TravelModeWalk, detourFactor: 1.3, speedKmh: 4.8, maxKm: 10},
+	{mode: TravelModeTransit, detourFactor: 1.4, speedKmh: 22, overheadMin: 8},
+	{mode: TravelModeDrive, detourFactor: 1.3, speedKmh: 35, overheadMin: 5},
+}
+
+type straightLineRouting struct{}
+
+// NewStraightLineRouting creates a routing provider that estimates travel offline from
+// great-circle distances and typical speeds
+func NewStraightLineRouting() RoutingProvider {
+	return straightLineRouting{}
+}
+
+func (straightLineRouting) Name() string {
+	return "straight-line"
+}
+
+func (straightLineRouting) Estimate(ctx context.Context, from, to GeoPoint) ([]dto.TravelEstimate, error) {
+	km := haversineKm(from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+	estimates := make([]dto.TravelEstimate, 0, len(straightLineModes))
+	for _, m := range straightLineModes {
+		if m.maxKm > 0 && km > m.maxKm {
+			continue
+		}
+		routeKm := km * m.detourFactor
+		estimates = append(estimates, dto.TravelEstimate{
+			Mode:            m.mode,
+			DistanceMeters:  int(math.Round(routeKm * 1000)),
+			DurationMinutes: int(math.Ceil(routeKm/m.speedKmh*60 + m.overheadMin)),
+		})
+	}
+	return estimates, nil
+}
+
+// googleDirectionsModes maps travel modes to Directions API modes
+var googleDirectionsModes = map[string]string{
+	TravelModeWalk:    "walking",
+	TravelModeTransit: "transit",
+	TravelModeDrive:   "driving",
+}
+
+const googleDirectionsURL = "https://maps.googleapis.com/maps/api/directions/json"
+
+type googleRouting struct {
+	apiKey string
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string][]dto.TravelEstimate
+}
+
+// NewGoogleRouting creates a routing provider backed by the Google Directions API.
+// Estimates are cached in memory, so repeated day responses do not repeat requests.
+func NewGoogleRouting(apiKey string) RoutingProvider {
+	return &googleRouting{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 10 * time.Second},
+		cache:  make(map[string][]dto.TravelEstimate),
+	}
+}
+
+func (g *googleRouting) Name() string {
+	return "google"
+}
+
+func (g *googleRouting) Estimate(ctx context.Context, from, to GeoPoint) ([]dto.TravelEstimate, error) {
+	key := fmt.Sprintf("%.5f,%.5f|%.5f,%.5f", from.Latitude, from.Longitude, to.Latitude, to.Longitude)
+	g.mu.Lock()
+	cached, ok := g.cache[key]
+	g.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	var estimates []dto.TravelEstimate
+	for _, mode := range []string{TravelModeWalk, TravelModeTransit, TravelModeDrive} {
+		estimate, err := g.directions(ctx, from, to, mode)
+		if err != nil {
+			return nil, err
+		}
+		if estimate != nil {
+			estimates = append(estimates, *estimate)
+		}
+	}
+
+	g.mu.Lock()
+	if len(g.cache) >= 10000 {
+		g.cache = make(map[string][]dto.TravelEstimate)
+	}
+	g.cache[key] = estimates
+	g.mu.Unlock()
+	return estimates, nil
+}
+
+// directions requests one mode, returning nil when the API finds no route in that mode
+func (g *googleRouting) directions(ctx context.Context, from, to GeoPoint, mode string) (*dto.TravelEstimate, error) {
+	query := url.Values{}
+	query.Set("origin", formatGeoPoint(from))
+	query.Set("destination", formatGeoPoint(to))
+	query.Set("mode", googleDirectionsModes[mode])
+	query.Set("key", g.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, googleDirectionsURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("directions request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("directions request failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Status string `json:"status"`
+		Routes []struct {
+			Legs []struct {
+				Distance struct {
+					Value int `json:"value"` // meters
+				} `json:"distance"`
+				Duration struct {
+					Value int `json:"value"` // seconds
+				} `json:"duration"`
+			} `json:"legs"`
+		} `json:"routes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid directions response: %w", err)
+	}
+
+	switch body.Status {
+	case "OK":
+	case "ZERO_RESULTS", "NOT_FOUND":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("directions request failed: %s", body.Status)
+	}
+	if len(body.Routes) == 0 || len(body.Routes[0].Legs) == 0 {
+		return nil, nil
+	}
+
+	estimate := &dto.TravelEstimate{Mode: mode}
+	for _, leg := range body.Routes[0].Legs {
+		estimate.DistanceMeters += leg.Distance.Value
+		estimate.DurationMinutes += leg.Duration.Value
+	}
+	estimate.DurationMinutes = int(math.Ceil(float64(estimate.DurationMinutes) / 60))
+	return estimate, nil
+}
+
+func formatGeoPoint(p GeoPoint) string {
+	return strconv.FormatFloat(p.Latitude, 'f', 6, 64) + "," + strconv.FormatFloat(p.Longitude, 'f', 6, 64)
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"triply-server/internal/models"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// tripRoleRank orders trip roles so a required role is satisfied by any role at or above it
+var tripRoleRank = map[string]int{
+	models.TripRoleViewer: 1,
+	models.TripRoleEditor: 2,
+	models.TripRoleOwner:  3,
+}
+
+// requireTripRole returns the user's role on the trip, or not found if the user cannot
+// see the trip and forbidden if they can but their role is below required
+func requireTripRole(ctx context.Context, tripRepo repository.TripRepository, tripID, userID, required string) (string, error) {
+	role, err := tripRepo.FindRole(ctx, tripID, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", utils.NewNotFoundError("Trip")
+		}
+		return "", err
+	}
+	if tripRoleRank[role] < tripRoleRank[required] {
+		return "", utils.NewForbiddenError("This action requires the " + required + " role on the trip")
+	}
+	return role, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/storage"
+	"triply-server/internal/utils"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// attachmentURLTTL is how long a signed download link stays valid
+const attachmentURLTTL = 15 * time.Minute
+
+// attachmentTypes are the accepted attachment formats, as detected from the file content
+var attachmentTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/webp":      true,
+	"image/gif":       true,
+}
+
+// Upload is a file received from a client
+type Upload struct {
+	FileName string
+	Size     int64
+	Content  io.Reader
+}
+
+// TripAttachmentService defines the interface for the files attached to a trip and its activities
+type TripAttachmentService interface {
+	ListAttachments(ctx context.Context, ownerID, tripID string, dayPlanActivityID *string) ([]models.TripAttachment, error)
+	GetAttachment(ctx context.Context, ownerID, tripID, attachmentID string) (*models.TripAttachment, error)
+	UploadAttachment(ctx context.Context, ownerID, tripID string, dayPlanActivityID *string, upload *Upload) (*models.TripAttachment, error)
+	DeleteAttachment(ctx context.Context, ownerID, tripID, attachmentID string) error
+	// OpenDownload checks a signed download link and that its user can still view the trip,
+	// then opens the file; the caller closes it
+	OpenDownload(ctx context.Context, tripID, attachmentID, userID string, expires int64, signature string) (*models.TripAttachment, io.ReadCloser, error)
+}
+
+type tripAttachmentService struct {
+	tripRepo       repository.TripRepository
+	attachmentRepo repository.TripAttachmentRepository
+	blobs          storage.BlobStore
+	signingSecret  string
+	maxUploadBytes int64
+	events         realtime.Publisher
+}
+
+// NewTripAttachmentService creates a new trip attachment service instance. Download links
+// are signed with signingSecret; uploads above maxUploadBytes are rejected.
+func NewTripAttachmentService(tripRepo repository.TripRepository, attachmentRepo repository.TripAttachmentRepository, blobs storage.BlobStore, signingSecret string, maxUploadBytes int64, events realtime.Publisher) TripAttachmentService {
+	return &tripAttachmentService{
+		tripRepo:       tripRepo,
+		attachmentRepo: attachmentRepo,
+		blobs:          blobs,
+		signingSecret:  signingSecret,
+		maxUploadBytes: maxUploadBytes,
+		events:         events,
+	}
+}
+
+func (s *tripAttachmentService) ListAttachments(ctx context.Context, ownerID, tripID string, dayPlanActivityID *string) ([]models.TripAttachment, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleViewer); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.FindByTripID(ctx, tripID, emptyToNil(dayPlanActivityID))
+	if err != nil {
+		return nil, err
+	}
+	for i := range attachments {
+		s.signDownload(&attachments[i], ownerID)
+	}
+	return attachments, nil
+}
+
+// GetAttachment returns the attachment with a fresh download link
+func (s *tripAttachmentService) GetAttachment(ctx context.Context, ownerID, tripID, attachmentID string) (*models.TripAttachment, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleViewer); err != nil {
+		return nil, err
+	}
+	attachment, err := s.attachmentRepo.FindByID(ctx, tripID, attachmentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Attachment")
+		}
+		return nil, err
+	}
+	s.signDownload(attachment, ownerID)
+	return attachment, nil
+}
+
+func (s *tripAttachmentService) UploadAttachment(ctx context.Context, ownerID, tripID string, dayPlanActivityID *string, upload *Upload) (*models.TripAttachment, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	dayPlanActivityID = emptyToNil(dayPlanActivityID)
+	if dayPlanActivityID != nil {
+		trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, utils.NewNotFoundError("Trip")
+			}
+			return nil, err
+		}
+		if findTripActivity(trip, *dayPlanActivityID) == nil {
+			return nil, utils.NewValidationError("dayPlanActivityId must be an activity of the trip")
+		}
+	}
+
+	content, contentType, err := sniffUpload(upload, s.maxUploadBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !attachmentTypes[contentType] {
+		return nil, utils.NewValidationError("attachments must be PDF, JPEG, PNG, WebP or GIF files")
+	}
+
+	attachment := &models.TripAttachment{
+		ID:                utils.GenerateID("att"),
+		TripID:            tripID,
+		DayPlanActivityID: dayPlanActivityID,
+		FileName:          uploadFileName(upload.FileName, "attachment"),
+		ContentType:       contentType,
+		SizeBytes:         upload.Size,
+		UploadedByUserID:  ownerID,
+		CreatedAt:         time.Now(),
+	}
+	attachment.StorageKey = "trips/" + tripID + "/attachments/" + attachment.ID
+
+	if err := s.blobs.Put(ctx, attachment.StorageKey, content, contentType); err != nil {
+		return nil, err
+	}
+	if err := s.attachmentRepo.Create(ctx, attachment); err != nil {
+		_ = s.blobs.Delete(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	// Members fetch their own links, so the event carries none
+	published := *attachment
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventAttachmentAdded, dto.AttachmentChangeEvent{
+		AttachmentID: attachment.ID,
+		Attachment:   &published,
+	})
+
+	s.signDownload(attachment, ownerID)
+	return attachment, nil
+}
+
+func (s *tripAttachmentService) DeleteAttachment(ctx context.Context, ownerID, tripID, attachmentID string) error {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return err
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(ctx, tripID, attachmentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Attachment")
+		}
+		return err
+	}
+	if err := s.attachmentRepo.Delete(ctx, tripID, attachmentID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Attachment")
+		}
+		return err
+	}
+	if err := s.blobs.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventAttachmentRemoved, dto.AttachmentChangeEvent{
+		AttachmentID: attachmentID,
+	})
+	return nil
+}
+
+func (s *tripAttachmentService) OpenDownload(ctx context.Context, tripID, attachmentID, userID string, expires int64, signature string) (*models.TripAttachment, io.ReadCloser, error) {
+	if !utils.VerifyExpiring(downloadScope(tripID, attachmentID, userID), s.signingSecret, expires, signature, time.Now()) {
+		return nil, nil, utils.NewForbiddenError("The download link is invalid or has expired")
+	}
+	// Links stop working as soon as their user loses access to the trip
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleViewer); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(ctx, tripID, attachmentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, utils.NewNotFoundError("Attachment")
+		}
+		return nil, nil, err
+	}
+	content, err := s.blobs.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, nil, utils.NewNotFoundError("Attachment")
+		}
+		return nil, nil, err
+	}
+	return attachment, content, nil
+}
+
+// signDownload fills in a download link for the attachment, relative to the server's base URL.
+// The link is bound to the member it is handed to and works while they can view the trip.
+func (s *tripAttachmentService) signDownload(attachment *models.TripAttachment, userID string) {
+	expires := time.Now().Add(attachmentURLTTL).Truncate(time.Second)
+	query := url.Values{}
+	query.Set("user", userID)
+	query.Set("expires", strconv.FormatInt(expires.Unix(), 10))
+	query.Set("signature", utils.SignExpiring(downloadScope(attachment.TripID, attachment.ID, userID), s.signingSecret, expires))
+
+	attachment.DownloadURL = fmt.Sprintf("/api/trips/%s/attachments/%s/download?%s", attachment.TripID, attachment.ID, query.Encode())
+	attachment.DownloadExpiresAt = &expires
+}
+
+// downloadScope is the signed part of a download link
+func downloadScope(tripID, attachmentID, userID string) string {
+	return tripID + "/" + attachmentID + "/" + userID
+}
+
+// sniffUpload checks the upload's size and detects its content type from its first bytes,
+// returning a reader over the whole content
+func sniffUpload(upload *Upload, maxBytes int64) (io.Reader, string, error) {
+	if upload.Size <= 0 {
+		return nil, "", utils.NewValidationError("the file is empty")
+	}
+	if upload.Size > maxBytes {
+		return nil, "", utils.NewValidationError(fmt.Sprintf("files can be at most %d MB", maxBytes>>20))
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(upload.Content, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, "", utils.NewValidationError("the file could not be read")
+	}
+	contentType := http.DetectContentType(head[:n])
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return io.MultiReader(bytes.NewReader(head[:n]), upload.Content), contentType, nil
+}
+
+// uploadFileName keeps the base name of an uploaded file, without control characters
+func uploadFileName(name, fallback string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return fallback
+	}
+	if len(name) > 255 {
+		// Keep the end, with the extension, starting on a whole character
+		start := len(name) - 255
+		for start < len(name) && !utf8.RuneStart(name[start]) {
+			start++
+		}
+		name = name[start:]
+	}
+	return name
+}
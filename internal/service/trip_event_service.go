@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+)
+
+// TripEventService defines the interface for subscribing to live trip changes
+type TripEventService interface {
+	Subscribe(ctx context.Context, userID, tripID string) (realtime.Subscription, error)
+}
+
+type tripEventService struct {
+	tripRepo repository.TripRepository
+	hub      realtime.Hub
+}
+
+// NewTripEventService creates a new trip event service instance
+func NewTripEventService(tripRepo repository.TripRepository, hub realtime.Hub) TripEventService {
+	return &tripEventService{
+		tripRepo: tripRepo,
+		hub:      hub,
+	}
+}
+
+// Subscribe starts streaming the trip's changes to anyone who can view the trip
+func (s *tripEventService) Subscribe(ctx context.Context, userID, tripID string) (realtime.Subscription, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleViewer); err != nil {
+		return nil, err
+	}
+	return s.hub.Subscribe(tripID, userID), nil
+}
+
+// publishTripEvent notifies the trip's subscribers of a change. Delivery is best effort:
+// a failure is logged and never fails the change itself.
+func publishTripEvent(ctx context.Context, events realtime.Publisher, tripID, actorID, eventType string, data interface{}) {
+	event := realtime.Event{
+		ID:      utils.GenerateID("evt"),
+		Type:    eventType,
+		TripID:  tripID,
+		ActorID: actorID,
+		Data:    data,
+		At:      time.Now().UTC(),
+	}
+	if err := events.Publish(ctx, event); err != nil {
+		log.Printf("Failed to publish %s event for trip %s: %v", eventType, tripID, err)
+	}
+}
+
+// dayNumbers maps renumbered days to their new day numbers
+func dayNumbers(days []models.DayPlan) map[string]int {
+	if len(days) == 0 {
+		return nil
+	}
+	numbers := make(map[string]int, len(days))
+	for _, day := range days {
+		numbers[day.ID] = day.DayNumber
+	}
+	return numbers
+}
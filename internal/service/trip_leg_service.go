@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+var validLegModes = map[string]bool{
+	models.LegModeFlight: true,
+	models.LegModeTrain:  true,
+	models.LegModeBus:    true,
+	models.LegModeCar:    true,
+	models.LegModeFerry:  true,
+}
+
+// TripLegService defines the interface for the transport legs between a trip's destinations
+type TripLegService interface {
+	ListLegs(ctx context.Context, ownerID, tripID string) ([]models.TripLeg, error)
+	CreateLeg(ctx context.Context, ownerID, tripID string, req *dto.CreateTripLegRequest) (*models.TripLeg, error)
+	UpdateLeg(ctx context.Context, ownerID, tripID, legID string, req *dto.UpdateTripLegRequest) (*models.TripLeg, error)
+	DeleteLeg(ctx context.Context, ownerID, tripID, legID string) error
+}
+
+type tripLegService struct {
+	tripRepo repository.TripRepository
+	legRepo  repository.TripLegRepository
+	events   realtime.Publisher
+}
+
+// NewTripLegService creates a new trip leg service instance
+func NewTripLegService(tripRepo repository.TripRepository, legRepo repository.TripLegRepository, events realtime.Publisher) TripLegService {
+	return &tripLegService{
+		tripRepo: tripRepo,
+		legRepo:  legRepo,
+		events:   events,
+	}
+}
+
+func (s *tripLegService) ListLegs(ctx context.Context, ownerID, tripID string) ([]models.TripLeg, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleViewer); err != nil {
+		return nil, err
+	}
+	legs, err := s.legRepo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	localizeLegs(legs)
+	return legs, nil
+}
+
+func (s *tripLegService) CreateLeg(ctx context.Context, ownerID, tripID string, req *dto.CreateTripLegRequest) (*models.TripLeg, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	leg := &models.TripLeg{
+		ID:        utils.GenerateID("leg"),
+		TripID:    tripID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	changes := &dto.UpdateTripLegRequest{
+		FromDestinationID: &req.FromDestinationID,
+		ToDestinationID:   &req.ToDestinationID,
+		Mode:              &req.Mode,
+		Carrier:           req.Carrier,
+		BookingReference:  req.BookingReference,
+		DepartureTime:     req.DepartureTime,
+		DepartureTimezone: req.DepartureTimezone,
+		ArrivalTime:       req.ArrivalTime,
+		ArrivalTimezone:   req.ArrivalTimezone,
+		CostAmount:        req.CostAmount,
+		CostCurrency:      req.CostCurrency,
+		Notes:             req.Notes,
+	}
+	if err := s.applyLegChanges(ctx, ownerID, leg, changes); err != nil {
+		return nil, err
+	}
+
+	if err := s.legRepo.Create(ctx, leg); err != nil {
+		return nil, err
+	}
+	return s.publishLeg(ctx, ownerID, tripID, leg.ID, realtime.EventLegAdded)
+}
+
+func (s *tripLegService) UpdateLeg(ctx context.Context, ownerID, tripID, legID string, req *dto.UpdateTripLegRequest) (*models.TripLeg, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	leg, err := s.legRepo.FindByID(ctx, tripID, legID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Leg")
+		}
+		return nil, err
+	}
+
+	if err := s.applyLegChanges(ctx, ownerID, leg, req); err != nil {
+		return nil, err
+	}
+	leg.UpdatedAt = time.Now()
+
+	if err := s.legRepo.Update(ctx, leg); err != nil {
+		return nil, err
+	}
+	return s.publishLeg(ctx, ownerID, tripID, legID, realtime.EventLegUpdated)
+}
+
+func (s *tripLegService) DeleteLeg(ctx context.Context, ownerID, tripID, legID string) error {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return err
+	}
+
+	if err := s.legRepo.Delete(ctx, tripID, legID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Leg")
+		}
+		return err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventLegRemoved, dto.LegChangeEvent{
+		LegID: legID,
+	})
+	return nil
+}
+
+// applyLegChanges validates the changes and applies them to the leg. Changing a zone without
+// a new time keeps the time's local clock reading, so a corrected zone doesn't shift the departure.
+func (s *tripLegService) applyLegChanges(ctx context.Context, ownerID string, leg *models.TripLeg, req *dto.UpdateTripLegRequest) error {
+	// Only new endpoints are checked against the trip, so reordering or removing its
+	// destinations doesn't lock the legs already planned
+	from, to := leg.FromDestination, leg.ToDestination
+	if req.FromDestinationID != nil || req.ToDestinationID != nil || from == nil || to == nil {
+		trip, err := s.tripRepo.FindByID(ctx, leg.TripID, ownerID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return utils.NewNotFoundError("Trip")
+			}
+			return err
+		}
+
+		if req.FromDestinationID != nil {
+			leg.FromDestinationID = *req.FromDestinationID
+		}
+		if req.ToDestinationID != nil {
+			leg.ToDestinationID = *req.ToDestinationID
+		}
+		from, to, err = legDestinations(destinationSequence(trip), leg.FromDestinationID, leg.ToDestinationID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.Mode != nil {
+		leg.Mode = strings.ToLower(strings.TrimSpace(*req.Mode))
+	}
+	if !validLegModes[leg.Mode] {
+		return utils.NewValidationError("mode must be one of flight, train, bus, car, ferry")
+	}
+	if req.Carrier != nil {
+		leg.Carrier = emptyToNil(req.Carrier)
+	}
+	if req.BookingReference != nil {
+		leg.BookingReference = emptyToNil(req.BookingReference)
+	}
+	if req.Notes != nil {
+		leg.Notes = emptyToNil(req.Notes)
+	}
+
+	departure, err := resolveZonedTime("departure", leg.DepartureTime, leg.DepartureTimezone, req.DepartureTime, req.DepartureTimezone, from.Timezone)
+	if err != nil {
+		return err
+	}
+	arrival, err := resolveZonedTime("arrival", leg.ArrivalTime, leg.ArrivalTimezone, req.ArrivalTime, req.ArrivalTimezone, to.Timezone)
+	if err != nil {
+		return err
+	}
+	leg.DepartureTime, leg.DepartureTimezone = departure.time, departure.zone
+	leg.ArrivalTime, leg.ArrivalTimezone = arrival.time, arrival.zone
+	if leg.DepartureTime != nil && leg.ArrivalTime != nil && leg.ArrivalTime.Before(*leg.DepartureTime) {
+		return utils.NewValidationError("arrivalTime must not be before departureTime")
+	}
+
+	if req.CostAmount != nil {
+		if *req.CostAmount < 0 {
+			return utils.NewValidationError("costAmount must not be negative")
+		}
+		leg.CostAmount = req.CostAmount
+	}
+	if req.CostCurrency != nil {
+		leg.CostCurrency = emptyToNil(req.CostCurrency)
+		if leg.CostCurrency != nil {
+			currency := strings.ToUpper(strings.TrimSpace(*leg.CostCurrency))
+			if !isCurrencyCode(currency) {
+				return utils.NewValidationError("costCurrency must be a 3-letter currency code")
+			}
+			leg.CostCurrency = &currency
+		}
+	}
+	if leg.CostAmount != nil && leg.CostCurrency == nil {
+		return utils.NewValidationError("costCurrency is required with costAmount")
+	}
+	return nil
+}
+
+// destinationSequence lists the trip's destinations in itinerary order: its trip destinations
+// when it has them, otherwise the destinations of its days in day order
+func destinationSequence(trip *models.Trip) []*models.Destination {
+	var sequence []*models.Destination
+	for _, td := range trip.TripDestinations {
+		if td.Destination != nil {
+			sequence = append(sequence, td.Destination)
+		}
+	}
+	if len(sequence) > 0 {
+		return sequence
+	}
+
+	seen := make(map[string]bool)
+	for _, day := range trip.DayPlans {
+		for _, dpd := range day.DayPlanDestinations {
+			if dpd.Destination == nil || seen[dpd.DestinationID] {
+				continue
+			}
+			seen[dpd.DestinationID] = true
+			sequence = append(sequence, dpd.Destination)
+		}
+	}
+	return sequence
+}
+
+// legDestinations finds the leg's endpoints in the trip's destination sequence, checking that
+// the second directly follows the first
+func legDestinations(sequence []*models.Destination, fromID, toID string) (*models.Destination, *models.Destination, error) {
+	if fromID == "" || toID == "" {
+		return nil, nil, utils.NewValidationError("fromDestinationId and toDestinationId are required")
+	}
+	if fromID == toID {
+		return nil, nil, utils.NewValidationError("a leg must connect two different destinations")
+	}
+	fromIndex, toIndex := -1, -1
+	for i, dest := range sequence {
+		switch dest.ID {
+		case fromID:
+			fromIndex = i
+		case toID:
+			toIndex = i
+		}
+	}
+	if fromIndex < 0 || toIndex < 0 {
+		return nil, nil, utils.NewValidationError("a leg must connect two of the trip's destinations")
+	}
+	if toIndex != fromIndex+1 {
+		return nil, nil, utils.NewValidationError("a leg must connect a destination to the next destination of the trip")
+	}
+	return sequence[fromIndex], sequence[toIndex], nil
+}
+
+func (s *tripLegService) publishLeg(ctx context.Context, ownerID, tripID, legID, eventType string) (*models.TripLeg, error) {
+	leg, err := s.legRepo.FindByID(ctx, tripID, legID)
+	if err != nil {
+		return nil, err
+	}
+	localizeLeg(leg)
+	publishTripEvent(ctx, s.events, tripID, ownerID, eventType, dto.LegChangeEvent{
+		LegID: legID,
+		Leg:   leg,
+	})
+	return leg, nil
+}
@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// invitationTTL is how long an invitation can be accepted after it is created
+const invitationTTL = 14 * 24 * time.Hour
+
+// TripMemberService defines the interface for trip collaboration operations
+type TripMemberService interface {
+	ListMembers(ctx context.Context, userID, tripID string) ([]dto.TripMemberResponse, error)
+	UpdateMemberRole(ctx context.Context, userID, tripID, memberUserID, role string) (*dto.TripMemberResponse, error)
+	RemoveMember(ctx context.Context, userID, tripID, memberUserID string) error
+
+	CreateInvitation(ctx context.Context, userID, tripID string, req *dto.CreateTripInvitationRequest) (*models.TripInvitation, error)
+	ListInvitations(ctx context.Context, userID, tripID string) ([]models.TripInvitation, error)
+	RevokeInvitation(ctx context.Context, userID, tripID, invitationID string) error
+
+	GetInvitation(ctx context.Context, token string) (*models.TripInvitation, error)
+	ListMyInvitations(ctx context.Context, userID string) ([]models.TripInvitation, error)
+	AcceptInvitation(ctx context.Context, userID, token string) (*models.Trip, error)
+	DeclineInvitation(ctx context.Context, userID, token string) error
+}
+
+type tripMemberService struct {
+	tripRepo       repository.TripRepository
+	memberRepo     repository.TripMemberRepository
+	invitationRepo repository.TripInvitationRepository
+	userRepo       repository.UserRepository
+	hub            realtime.Hub
+}
+
+// NewTripMemberService creates a new trip member service instance
+func NewTripMemberService(
+	tripRepo repository.TripRepository,
+	memberRepo repository.TripMemberRepository,
+	invitationRepo repository.TripInvitationRepository,
+	userRepo repository.UserRepository,
+	hub realtime.Hub,
+) TripMemberService {
+	return &tripMemberService{
+		tripRepo:       tripRepo,
+		memberRepo:     memberRepo,
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		hub:            hub,
+	}
+}
+
+// ListMembers returns the owner followed by the invited members
+func (s *tripMemberService) ListMembers(ctx context.Context, userID, tripID string) ([]dto.TripMemberResponse, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleViewer); err != nil {
+		return nil, err
+	}
+	trip, err := s.tripRepo.FindBasicByID(ctx, tripID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := s.userRepo.FindByID(ctx, trip.UserID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	members, err := s.memberRepo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.TripMemberResponse, 0, len(members)+1)
+	result = append(result, memberResponse(trip.UserID, owner, models.TripRoleOwner, trip.CreatedAt))
+	for _, member := range members {
+		result = append(result, memberResponse(member.UserID, member.User, member.Role, member.CreatedAt))
+	}
+	return result, nil
+}
+
+func (s *tripMemberService) UpdateMemberRole(ctx context.Context, userID, tripID, memberUserID, role string) (*dto.TripMemberResponse, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleOwner); err != nil {
+		return nil, err
+	}
+	if err := validateMemberRole(role); err != nil {
+		return nil, err
+	}
+	if memberUserID == userID {
+		return nil, utils.NewValidationError("The owner's role cannot be changed")
+	}
+
+	if err := s.memberRepo.UpdateRole(ctx, tripID, memberUserID, role); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Member")
+		}
+		return nil, err
+	}
+
+	member, err := s.memberRepo.FindByTripAndUser(ctx, tripID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+	response := memberResponse(member.UserID, member.User, member.Role, member.CreatedAt)
+	return &response, nil
+}
+
+// RemoveMember lets the owner remove anyone, and any member remove themselves (leave the trip)
+func (s *tripMemberService) RemoveMember(ctx context.Context, userID, tripID, memberUserID string) error {
+	role, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleViewer)
+	if err != nil {
+		return err
+	}
+	if role != models.TripRoleOwner && memberUserID != userID {
+		return utils.NewForbiddenError("Only the owner can remove other members")
+	}
+	if role == models.TripRoleOwner && memberUserID == userID {
+		return utils.NewValidationError("The owner cannot leave their own trip")
+	}
+
+	if err := s.memberRepo.Delete(ctx, tripID, memberUserID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Member")
+		}
+		return err
+	}
+	// Stop live updates the former member may still be receiving
+	s.hub.Disconnect(tripID, memberUserID)
+	return nil
+}
+
+// CreateInvitation creates an email invitation, or a shareable link invitation when no email is given.
+// Invitations are not mailed; invitees see email invitations in their invitation list once they sign in.
+func (s *tripMemberService) CreateInvitation(ctx context.Context, userID, tripID string, req *dto.CreateTripInvitationRequest) (*models.TripInvitation, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleOwner); err != nil {
+		return nil, err
+	}
+	if err := validateMemberRole(req.Role); err != nil {
+		return nil, err
+	}
+
+	var email *string
+	if req.Email != nil && strings.TrimSpace(*req.Email) != "" {
+		normalized := normalizeEmail(*req.Email)
+		if !strings.Contains(normalized, "@") {
+			return nil, utils.NewValidationError("email is not a valid email address")
+		}
+		if owner, err := s.userRepo.FindByID(ctx, userID); err == nil && normalizeEmail(owner.Email) == normalized {
+			return nil, utils.NewValidationError("You cannot invite yourself")
+		}
+		email = &normalized
+	}
+
+	now := time.Now()
+	invitation := &models.TripInvitation{
+		ID:              utils.GenerateID("inv"),
+		TripID:          tripID,
+		Email:           email,
+		Token:           utils.GenerateToken(24),
+		Role:            req.Role,
+		Status:          models.InvitationStatusPending,
+		InvitedByUserID: userID,
+		ExpiresAt:       now.Add(invitationTTL),
+		CreatedAt:       now,
+	}
+	if err := s.invitationRepo.Create(ctx, invitation); err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+func (s *tripMemberService) ListInvitations(ctx context.Context, userID, tripID string) ([]models.TripInvitation, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleOwner); err != nil {
+		return nil, err
+	}
+	return s.invitationRepo.FindByTripID(ctx, tripID)
+}
+
+// RevokeInvitation stops a pending invitation from being accepted. Members who already
+// joined through it keep their access.
+func (s *tripMemberService) RevokeInvitation(ctx context.Context, userID, tripID, invitationID string) error {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleOwner); err != nil {
+		return err
+	}
+
+	invitation, err := s.invitationRepo.FindByID(ctx, tripID, invitationID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Invitation")
+		}
+		return err
+	}
+	if invitation.Status != models.InvitationStatusPending {
+		return utils.NewValidationError("Only pending invitations can be revoked")
+	}
+
+	now := time.Now()
+	invitation.Status = models.InvitationStatusRevoked
+	invitation.RespondedByUserID = &userID
+	invitation.RespondedAt = &now
+	return s.invitationRepo.Update(ctx, invitation)
+}
+
+// GetInvitation looks up an invitation by token so the invitee can see what they are joining
+func (s *tripMemberService) GetInvitation(ctx context.Context, token string) (*models.TripInvitation, error) {
+	invitation, err := s.invitationRepo.FindByToken(ctx, token)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Invitation")
+		}
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// ListMyInvitations returns the pending invitations addressed to the user's email
+func (s *tripMemberService) ListMyInvitations(ctx context.Context, userID string) ([]models.TripInvitation, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("User")
+		}
+		return nil, err
+	}
+	return s.invitationRepo.FindPendingByEmail(ctx, normalizeEmail(user.Email))
+}
+
+// AcceptInvitation adds the user to the trip with the invitation's role. Email invitations
+// must be accepted by the addressee and can be used once; link invitations stay usable
+// until they expire or are revoked. Accepting never lowers an existing member's role.
+func (s *tripMemberService) AcceptInvitation(ctx context.Context, userID, token string) (*models.Trip, error) {
+	invitation, err := s.findUsableInvitation(ctx, userID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.tripRepo.FindRole(ctx, invitation.TripID, userID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if role == models.TripRoleOwner {
+		return nil, utils.NewValidationError("You already own this trip")
+	}
+	if tripRoleRank[role] < tripRoleRank[invitation.Role] {
+		role = invitation.Role
+	}
+
+	now := time.Now()
+	member := &models.TripMember{
+		ID:              utils.GenerateID("member"),
+		TripID:          invitation.TripID,
+		UserID:          userID,
+		Role:            role,
+		InvitedByUserID: &invitation.InvitedByUserID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if !invitation.IsLink() {
+		invitation.Status = models.InvitationStatusAccepted
+		invitation.RespondedByUserID = &userID
+		invitation.RespondedAt = &now
+	}
+	if err := s.invitationRepo.Accept(ctx, invitation, member); err != nil {
+		return nil, err
+	}
+
+	return s.tripRepo.FindByID(ctx, invitation.TripID, userID)
+}
+
+// DeclineInvitation marks an email invitation as declined. Declining a link invitation
+// has nothing to record, since others may still use the link.
+func (s *tripMemberService) DeclineInvitation(ctx context.Context, userID, token string) error {
+	invitation, err := s.findUsableInvitation(ctx, userID, token)
+	if err != nil {
+		return err
+	}
+	if invitation.IsLink() {
+		return nil
+	}
+
+	now := time.Now()
+	invitation.Status = models.InvitationStatusDeclined
+	invitation.RespondedByUserID = &userID
+	invitation.RespondedAt = &now
+	return s.invitationRepo.Update(ctx, invitation)
+}
+
+// findUsableInvitation loads a pending, unexpired invitation the user is allowed to respond to
+func (s *tripMemberService) findUsableInvitation(ctx context.Context, userID, token string) (*models.TripInvitation, error) {
+	invitation, err := s.GetInvitation(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Status != models.InvitationStatusPending {
+		return nil, utils.NewAppError("INVITATION_UNAVAILABLE", "Invitation has already been "+invitation.Status, 410)
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, utils.NewAppError("INVITATION_UNAVAILABLE", "Invitation has expired", 410)
+	}
+
+	if !invitation.IsLink() {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, utils.NewNotFoundError("User")
+			}
+			return nil, err
+		}
+		if normalizeEmail(user.Email) != *invitation.Email {
+			return nil, utils.NewForbiddenError("This invitation was sent to a different email address")
+		}
+	}
+	return invitation, nil
+}
+
+func validateMemberRole(role string) error {
+	if role != models.TripRoleEditor && role != models.TripRoleViewer {
+		return utils.NewValidationError("role must be 'editor' or 'viewer'")
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// memberResponse describes a trip member; user may be nil (e.g. a trip owned by a shadow user)
+func memberResponse(userID string, user *models.User, role string, joinedAt time.Time) dto.TripMemberResponse {
+	response := dto.TripMemberResponse{
+		UserID:   userID,
+		Role:     role,
+		JoinedAt: joinedAt.Format(time.RFC3339),
+	}
+	if user != nil {
+		response.Name = user.Name
+		response.DisplayName = user.DisplayName
+		response.Email = user.Email
+		response.AvatarURL = user.AvatarURL
+	}
+	return response
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// maxReservationAttachments caps the attachment links of one reservation
+const maxReservationAttachments = 20
+
+var validReservationTypes = map[string]bool{
+	models.ReservationTypeHotel:      true,
+	models.ReservationTypeFlight:     true,
+	models.ReservationTypeRestaurant: true,
+	models.ReservationTypeTicket:     true,
+}
+
+// TripReservationService defines the interface for the bookings of a trip's activities and legs
+type TripReservationService interface {
+	ListReservations(ctx context.Context, ownerID, tripID string) ([]models.TripReservation, error)
+	CreateReservation(ctx context.Context, ownerID, tripID string, req *dto.CreateTripReservationRequest) (*models.TripReservation, error)
+	UpdateReservation(ctx context.Context, ownerID, tripID, reservationID string, req *dto.UpdateTripReservationRequest) (*models.TripReservation, error)
+	DeleteReservation(ctx context.Context, ownerID, tripID, reservationID string) error
+}
+
+type tripReservationService struct {
+	tripRepo        repository.TripRepository
+	reservationRepo repository.TripReservationRepository
+	events          realtime.Publisher
+}
+
+// NewTripReservationService creates a new trip reservation service instance
+func NewTripReservationService(tripRepo repository.TripRepository, reservationRepo repository.TripReservationRepository, events realtime.Publisher) TripReservationService {
+	return &tripReservationService{
+		tripRepo:        tripRepo,
+		reservationRepo: reservationRepo,
+		events:          events,
+	}
+}
+
+func (s *tripReservationService) ListReservations(ctx context.Context, ownerID, tripID string) ([]models.TripReservation, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleViewer); err != nil {
+		return nil, err
+	}
+	reservations, err := s.reservationRepo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reservations {
+		localizeReservation(&reservations[i])
+	}
+	return reservations, nil
+}
+
+func (s *tripReservationService) CreateReservation(ctx context.Context, ownerID, tripID string, req *dto.CreateTripReservationRequest) (*models.TripReservation, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reservation := &models.TripReservation{
+		ID:              utils.GenerateID("res"),
+		TripID:          tripID,
+		Attachments:     models.ReservationAttachments{},
+		CreatedByUserID: ownerID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	changes := &dto.UpdateTripReservationRequest{
+		DayPlanActivityID:    req.DayPlanActivityID,
+		TripLegID:            req.TripLegID,
+		Type:                 &req.Type,
+		Provider:             req.Provider,
+		ConfirmationNumber:   req.ConfirmationNumber,
+		StartTime:            req.StartTime,
+		StartTimezone:        req.StartTimezone,
+		EndTime:              req.EndTime,
+		EndTimezone:          req.EndTimezone,
+		CancellationDeadline: req.CancellationDeadline,
+		Notes:                req.Notes,
+	}
+	if req.Attachments != nil {
+		changes.Attachments = &req.Attachments
+	}
+	if err := s.applyReservationChanges(ctx, ownerID, reservation, changes); err != nil {
+		return nil, err
+	}
+
+	if err := s.reservationRepo.Create(ctx, reservation); err != nil {
+		return nil, err
+	}
+	return s.publishReservation(ctx, ownerID, reservation, realtime.EventReservationAdded), nil
+}
+
+func (s *tripReservationService) UpdateReservation(ctx context.Context, ownerID, tripID, reservationID string, req *dto.UpdateTripReservationRequest) (*models.TripReservation, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.reservationRepo.FindByID(ctx, tripID, reservationID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Reservation")
+		}
+		return nil, err
+	}
+
+	if err := s.applyReservationChanges(ctx, ownerID, reservation, req); err != nil {
+		return nil, err
+	}
+	reservation.UpdatedAt = time.Now()
+
+	if err := s.reservationRepo.Update(ctx, reservation); err != nil {
+		return nil, err
+	}
+	return s.publishReservation(ctx, ownerID, reservation, realtime.EventReservationUpdated), nil
+}
+
+func (s *tripReservationService) DeleteReservation(ctx context.Context, ownerID, tripID, reservationID string) error {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return err
+	}
+
+	if err := s.reservationRepo.Delete(ctx, tripID, reservationID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Reservation")
+		}
+		return err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventReservationRemoved, dto.ReservationChangeEvent{
+		ReservationID: reservationID,
+	})
+	return nil
+}
+
+// applyReservationChanges validates the changes and applies them to the reservation
+func (s *tripReservationService) applyReservationChanges(ctx context.Context, ownerID string, reservation *models.TripReservation, req *dto.UpdateTripReservationRequest) error {
+	trip, err := s.tripRepo.FindByID(ctx, reservation.TripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Trip")
+		}
+		return err
+	}
+
+	// Only a new link is checked, so a reservation outlives its activity being removed
+	activityID, legID := emptyToNil(req.DayPlanActivityID), emptyToNil(req.TripLegID)
+	switch {
+	case activityID != nil && legID != nil:
+		return utils.NewValidationError("a reservation links to either dayPlanActivityId or tripLegId, not both")
+	case activityID != nil:
+		if findTripActivity(trip, *activityID) == nil {
+			return utils.NewValidationError("dayPlanActivityId must be an activity of the trip")
+		}
+		reservation.DayPlanActivityID, reservation.TripLegID = activityID, nil
+	case legID != nil:
+		if findTripLeg(trip, *legID) == nil {
+			return utils.NewValidationError("tripLegId must be a leg of the trip")
+		}
+		reservation.DayPlanActivityID, reservation.TripLegID = nil, legID
+	}
+	if reservation.DayPlanActivityID == nil && reservation.TripLegID == nil {
+		return utils.NewValidationError("dayPlanActivityId or tripLegId is required")
+	}
+
+	if req.Type != nil {
+		reservation.Type = strings.ToLower(strings.TrimSpace(*req.Type))
+	}
+	if !validReservationTypes[reservation.Type] {
+		return utils.NewValidationError("type must be one of hotel, flight, restaurant, ticket")
+	}
+	if req.Provider != nil {
+		reservation.Provider = emptyToNil(req.Provider)
+	}
+	if req.ConfirmationNumber != nil {
+		reservation.ConfirmationNumber = emptyToNil(req.ConfirmationNumber)
+	}
+	if req.Notes != nil {
+		reservation.Notes = emptyToNil(req.Notes)
+	}
+	if req.Attachments != nil {
+		attachments, err := validateReservationAttachments(*req.Attachments)
+		if err != nil {
+			return err
+		}
+		reservation.Attachments = attachments
+	}
+
+	startZone, endZone := reservationZones(trip, reservation)
+	start, err := resolveZonedTime("start", reservation.StartTime, reservation.StartTimezone, req.StartTime, req.StartTimezone, startZone)
+	if err != nil {
+		return err
+	}
+	end, err := resolveZonedTime("end", reservation.EndTime, reservation.EndTimezone, req.EndTime, req.EndTimezone, endZone)
+	if err != nil {
+		return err
+	}
+	// The deadline follows the start zone, keeping its local clock time when that zone changes
+	deadline, err := resolveZonedTime("cancellationDeadline", reservation.CancellationDeadline, reservation.StartTimezone, req.CancellationDeadline, req.StartTimezone, startZone)
+	if err != nil {
+		return err
+	}
+	reservation.StartTime, reservation.StartTimezone = start.time, start.zone
+	reservation.EndTime, reservation.EndTimezone = end.time, end.zone
+	reservation.CancellationDeadline = deadline.time
+	if reservation.StartTime != nil && reservation.EndTime != nil && reservation.EndTime.Before(*reservation.StartTime) {
+		return utils.NewValidationError("endTime must not be before startTime")
+	}
+	return nil
+}
+
+// reservationZones returns the default start and end zones of a reservation: the timezone of
+// its activity's day, or its leg's departure and arrival zones
+func reservationZones(trip *models.Trip, reservation *models.TripReservation) (*string, *string) {
+	if reservation.TripLegID != nil {
+		if leg := findTripLeg(trip, *reservation.TripLegID); leg != nil {
+			return leg.DepartureTimezone, leg.ArrivalTimezone
+		}
+		return nil, nil
+	}
+	if reservation.DayPlanActivityID == nil {
+		return nil, nil
+	}
+	dpa := findTripActivity(trip, *reservation.DayPlanActivityID)
+	if dpa == nil {
+		return nil, nil
+	}
+	day := findTripDay(trip, dpa.DayPlanID)
+	if day == nil {
+		return nil, nil
+	}
+	loc := dayLocation(trip, day)
+	if loc == time.UTC {
+		return nil, nil
+	}
+	zone := loc.String()
+	return &zone, &zone
+}
+
+// validateReservationAttachments checks that every attachment links to an http(s) URL,
+// naming unnamed ones after the link
+func validateReservationAttachments(attachments []models.ReservationAttachment) (models.ReservationAttachments, error) {
+	if len(attachments) > maxReservationAttachments {
+		return nil, utils.NewValidationError("a reservation can have at most 20 attachments")
+	}
+	result := make(models.ReservationAttachments, 0, len(attachments))
+	for _, attachment := range attachments {
+		link := strings.TrimSpace(attachment.URL)
+		parsed, err := url.Parse(link)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, utils.NewValidationError("attachment url must be an http or https link")
+		}
+		name := strings.TrimSpace(attachment.Name)
+		if name == "" {
+			name = link
+		}
+		result = append(result, models.ReservationAttachment{Name: name, URL: link})
+	}
+	return result, nil
+}
+
+func findTripLeg(trip *models.Trip, legID string) *models.TripLeg {
+	for i := range trip.TripLegs {
+		if trip.TripLegs[i].ID == legID {
+			return &trip.TripLegs[i]
+		}
+	}
+	return nil
+}
+
+// localizeReservation fills in the reservation's start, end and cancellation deadline in their zones
+func localizeReservation(reservation *models.TripReservation) {
+	reservation.Start, reservation.End, reservation.Cancellation = nil, nil, nil
+	if reservation.StartTime != nil {
+		reservation.Start = activitySchedule(*reservation.StartTime, zoneLocation(reservation.StartTimezone))
+	}
+	if reservation.EndTime != nil {
+		reservation.End = activitySchedule(*reservation.EndTime, zoneLocation(reservation.EndTimezone))
+	}
+	if reservation.CancellationDeadline != nil {
+		reservation.Cancellation = activitySchedule(*reservation.CancellationDeadline, zoneLocation(reservation.StartTimezone))
+	}
+}
+
+// attachReservations nests the trip's reservations under the activities and legs they are for.
+// Only members see them: public trip responses never carry reservations.
+func attachReservations(trip *models.Trip) {
+	byActivity := make(map[string][]models.TripReservation)
+	byLeg := make(map[string][]models.TripReservation)
+	for _, reservation := range trip.Reservations {
+		localizeReservation(&reservation)
+		switch {
+		case reservation.DayPlanActivityID != nil:
+			byActivity[*reservation.DayPlanActivityID] = append(byActivity[*reservation.DayPlanActivityID], reservation)
+		case reservation.TripLegID != nil:
+			byLeg[*reservation.TripLegID] = append(byLeg[*reservation.TripLegID], reservation)
+		}
+	}
+
+	for i := range trip.DayPlans {
+		activities := trip.DayPlans[i].DayPlanActivities
+		for j := range activities {
+			activities[j].Reservations = byActivity[activities[j].ID]
+		}
+	}
+	for i := range trip.TripLegs {
+		trip.TripLegs[i].Reservations = byLeg[trip.TripLegs[i].ID]
+	}
+}
+
+func (s *tripReservationService) publishReservation(ctx context.Context, ownerID string, reservation *models.TripReservation, eventType string) *models.TripReservation {
+	localizeReservation(reservation)
+	publishTripEvent(ctx, s.events, reservation.TripID, ownerID, eventType, dto.ReservationChangeEvent{
+		ReservationID: reservation.ID,
+		Reservation:   reservation,
+	})
+	return reservation
+}
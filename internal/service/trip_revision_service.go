@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"time"
+	"triply-server/internal/dto"
+	"triply-server/internal/models"
+	"triply-server/internal/realtime"
+	"triply-server/internal/repository"
+	"triply-server/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// CurrentRevision refers to the live trip when diffing revisions
+const CurrentRevision = "current"
+
+// TripRevisionService defines the interface for trip revision history operations
+type TripRevisionService interface {
+	ListRevisions(ctx context.Context, ownerID, tripID string) ([]dto.TripRevisionSummary, error)
+	GetRevision(ctx context.Context, ownerID, tripID, revisionID string) (*models.TripRevision, error)
+	DiffRevisions(ctx context.Context, ownerID, tripID, fromID, toID string) (*dto.TripRevisionDiff, error)
+	RestoreRevision(ctx context.Context, ownerID, tripID, revisionID string) (*models.Trip, error)
+}
+
+type tripRevisionService struct {
+	tripRepo     repository.TripRepository
+	revisionRepo repository.TripRevisionRepository
+	events       realtime.Publisher
+}
+
+// NewTripRevisionService creates a new trip revision service instance
+func NewTripRevisionService(tripRepo repository.TripRepository, revisionRepo repository.TripRevisionRepository, events realtime.Publisher) TripRevisionService {
+	return &tripRevisionService{
+		tripRepo:     tripRepo,
+		revisionRepo: revisionRepo,
+		events:       events,
+	}
+}
+
+func (s *tripRevisionService) ListRevisions(ctx context.Context, ownerID, tripID string) ([]dto.TripRevisionSummary, error) {
+	if err := s.ensureAccess(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisionRepo.FindByTripID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]dto.TripRevisionSummary, len(revisions))
+	for i, rev := range revisions {
+		summaries[i] = dto.TripRevisionSummary{
+			ID:              rev.ID,
+			Version:         rev.Version,
+			CreatedByUserID: rev.CreatedByUserID,
+			CreatedAt:       rev.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return summaries, nil
+}
+
+func (s *tripRevisionService) GetRevision(ctx context.Context, ownerID, tripID, revisionID string) (*models.TripRevision, error) {
+	if err := s.ensureAccess(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+	return s.findRevision(ctx, tripID, revisionID)
+}
+
+func (s *tripRevisionService) DiffRevisions(ctx context.Context, ownerID, tripID, fromID, toID string) (*dto.TripRevisionDiff, error) {
+	if fromID == "" {
+		return nil, utils.NewValidationError("from is required")
+	}
+	if toID == "" {
+		toID = CurrentRevision
+	}
+
+	if err := s.ensureAccess(ctx, ownerID, tripID); err != nil {
+		return nil, err
+	}
+
+	from, err := s.loadSnapshot(ctx, ownerID, tripID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.loadSnapshot(ctx, ownerID, tripID, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := diffSnapshots(from, to)
+	diff.From = fromID
+	diff.To = toID
+	return diff, nil
+}
+
+func (s *tripRevisionService) RestoreRevision(ctx context.Context, ownerID, tripID, revisionID string) (*models.Trip, error) {
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, ownerID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
+	current, err := s.tripRepo.FindBasicByID(ctx, tripID, ownerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Trip")
+		}
+		return nil, err
+	}
+
+	revision, err := s.findRevision(ctx, tripID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Restore the itinerary and trip details, but keep counters and sharing state as they are now
+	restored := revision.Snapshot.Trip
+	restored.ID = current.ID
+	restored.UserID = current.UserID
+	restored.Slug = current.Slug
+	restored.Visibility = current.Visibility
+	restored.Likes = current.Likes
+	restored.CloneCount = current.CloneCount
+	restored.CreatedAt = current.CreatedAt
+	restored.UpdatedAt = time.Now()
+	restored.Version = 0 // unconditional; the replaced state is itself recorded as a revision
+	restored.TripDestinations = revision.Snapshot.TripDestinations
+
+	for i := range restored.DayPlans {
+		for j := range restored.DayPlans[i].DayPlanActivities {
+			restored.DayPlans[i].DayPlanActivities[j].Activity = nil
+		}
+		for j := range restored.DayPlans[i].DayPlanDestinations {
+			restored.DayPlans[i].DayPlanDestinations[j].Destination = nil
+		}
+	}
+	for i := range restored.TripDestinations {
+		restored.TripDestinations[i].Destination = nil
+	}
+
+	if err := s.tripRepo.Update(ctx, &restored, ownerID); err != nil {
+		return nil, err
+	}
+
+	trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	publishTripEvent(ctx, s.events, tripID, ownerID, realtime.EventTripRestored, trip)
+	return trip, nil
+}
+
+// ensureAccess returns not found unless ownerID owns the trip or is a member
+func (s *tripRevisionService) ensureAccess(ctx context.Context, ownerID, tripID string) error {
+	if _, err := s.tripRepo.FindBasicByID(ctx, tripID, ownerID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return utils.NewNotFoundError("Trip")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *tripRevisionService) findRevision(ctx context.Context, tripID, revisionID string) (*models.TripRevision, error) {
+	revision, err := s.revisionRepo.FindByID(ctx, tripID, revisionID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, utils.NewNotFoundError("Revision")
+		}
+		return nil, err
+	}
+	return revision, nil
+}
+
+// loadSnapshot returns a stored revision's snapshot, or the live trip for CurrentRevision
+func (s *tripRevisionService) loadSnapshot(ctx context.Context, ownerID, tripID, revisionID string) (*models.TripSnapshot, error) {
+	if revisionID == CurrentRevision {
+		trip, err := s.tripRepo.FindByID(ctx, tripID, ownerID)
+		if err != nil {
+			return nil, err
+		}
+		tripDestinations := trip.TripDestinations
+		trip.TripDestinations = nil
+		return &models.TripSnapshot{Trip: *trip, TripDestinations: tripDestinations}, nil
+	}
+
+	revision, err := s.findRevision(ctx, tripID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	return &revision.Snapshot, nil
+}
+
+// diffSnapshots compares two trip trees, matching days, activities and destinations by ID
+func diffSnapshots(from, to *models.TripSnapshot) *dto.TripRevisionDiff {
+	diff := &dto.TripRevisionDiff{
+		FromVersion:         from.Trip.Version,
+		ToVersion:           to.Trip.Version,
+		TripChanges:         []dto.FieldChange{},
+		DaysAdded:           []dto.DayChange{},
+		DaysRemoved:         []dto.DayChange{},
+		DaysChanged:         []dto.DayChange{},
+		ActivitiesAdded:     []dto.ActivityChange{},
+		ActivitiesRemoved:   []dto.ActivityChange{},
+		ActivitiesChanged:   []dto.ActivityChange{},
+		DestinationsAdded:   []dto.DestinationChange{},
+		DestinationsRemoved: []dto.DestinationChange{},
+	}
+
+	// Trip details
+	ft, tt := &from.Trip, &to.Trip
+	addChange(&diff.TripChanges, "name", ft.Name, tt.Name)
+	addChange(&diff.TripChanges, "startDate", utils.FormatDate(ft.StartDate), utils.FormatDate(tt.StartDate))
+	addChange(&diff.TripChanges, "endDate", utils.FormatDate(ft.EndDate), utils.FormatDate(tt.EndDate))
+	addChange(&diff.TripChanges, "coverImage", ft.CoverImage, tt.CoverImage)
+	addChange(&diff.TripChanges, "travelerCount", ft.TravelerCount, tt.TravelerCount)
+	addChange(&diff.TripChanges, "adults", ft.Adults, tt.Adults)
+	addChange(&diff.TripChanges, "childrenAges", ft.ChildrenAges, tt.ChildrenAges)
+	addChange(&diff.TripChanges, "status", ft.Status, tt.Status)
+	addChange(&diff.TripChanges, "summary", derefString(ft.Summary), derefString(tt.Summary))
+	addChange(&diff.TripChanges, "travelerType", ft.TravelerType, tt.TravelerType)
+
+	// Days
+	fromDays := make(map[string]*models.DayPlan)
+	for i := range ft.DayPlans {
+		fromDays[ft.DayPlans[i].ID] = &ft.DayPlans[i]
+	}
+	toDays := make(map[string]*models.DayPlan)
+	for i := range tt.DayPlans {
+		day := &tt.DayPlans[i]
+		toDays[day.ID] = day
+
+		old, ok := fromDays[day.ID]
+		if !ok {
+			diff.DaysAdded = append(diff.DaysAdded, dayChange(day))
+			continue
+		}
+
+		change := dayChange(day)
+		addChange(&change.Changes, "date", utils.FormatDate(old.Date), utils.FormatDate(day.Date))
+		addChange(&change.Changes, "dayNumber", old.DayNumber, day.DayNumber)
+		addChange(&change.Changes, "notes", derefString(old.Notes), derefString(day.Notes))
+		if len(change.Changes) > 0 {
+			diff.DaysChanged = append(diff.DaysChanged, change)
+		}
+	}
+	for i := range ft.DayPlans {
+		if _, ok := toDays[ft.DayPlans[i].ID]; !ok {
+			diff.DaysRemoved = append(diff.DaysRemoved, dayChange(&ft.DayPlans[i]))
+		}
+	}
+
+	// Activities (across all days, so moves between days show up as changes)
+	fromActivities := make(map[string]*models.DayPlanActivity)
+	for i := range ft.DayPlans {
+		for j := range ft.DayPlans[i].DayPlanActivities {
+			dpa := &ft.DayPlans[i].DayPlanActivities[j]
+			fromActivities[dpa.ID] = dpa
+		}
+	}
+	toActivities := make(map[string]bool)
+	for i := range tt.DayPlans {
+		for j := range tt.DayPlans[i].DayPlanActivities {
+			dpa := &tt.DayPlans[i].DayPlanActivities[j]
+			toActivities[dpa.ID] = true
+
+			old, ok := fromActivities[dpa.ID]
+			if !ok {
+				diff.ActivitiesAdded = append(diff.ActivitiesAdded, activityChange(dpa))
+				continue
+			}
+
+			change := activityChange(dpa)
+			addChange(&change.Changes, "dayId", old.DayPlanID, dpa.DayPlanID)
+			addChange(&change.Changes, "timeOfDay", old.TimeOfDay, dpa.TimeOfDay)
+			addChange(&change.Changes, "orderWithinTime", old.OrderWithinTime, dpa.OrderWithinTime)
+			addChange(&change.Changes, "customTitle", derefString(old.CustomTitle), derefString(dpa.CustomTitle))
+			addChange(&change.Changes, "customNotes", derefString(old.CustomNotes), derefString(dpa.CustomNotes))
+			addChange(&change.Changes, "customTime", formatOptionalTime(old.CustomTime), formatOptionalTime(dpa.CustomTime))
+			addChange(&change.Changes, "completed", old.Completed, dpa.Completed)
+			addChange(&change.Changes, "skipped", old.Skipped, dpa.Skipped)
+			if len(change.Changes) > 0 {
+				diff.ActivitiesChanged = append(diff.ActivitiesChanged, change)
+			}
+		}
+	}
+	for id, dpa := range fromActivities {
+		if !toActivities[id] {
+			diff.ActivitiesRemoved = append(diff.ActivitiesRemoved, activityChange(dpa))
+		}
+	}
+
+	// Trip destinations
+	fromDests := make(map[string]bool)
+	for _, td := range from.TripDestinations {
+		fromDests[td.DestinationID] = true
+	}
+	toDests := make(map[string]bool)
+	for _, td := range to.TripDestinations {
+		toDests[td.DestinationID] = true
+		if !fromDests[td.DestinationID] {
+			diff.DestinationsAdded = append(diff.DestinationsAdded, destinationChange(&td))
+		}
+	}
+	for _, td := range from.TripDestinations {
+		if !toDests[td.DestinationID] {
+			diff.DestinationsRemoved = append(diff.DestinationsRemoved, destinationChange(&td))
+		}
+	}
+
+	return diff
+}
+
+func addChange(changes *[]dto.FieldChange, field string, from, to interface{}) {
+	if from != to {
+		*changes = append(*changes, dto.FieldChange{Field: field, From: from, To: to})
+	}
+}
+
+func dayChange(day *models.DayPlan) dto.DayChange {
+	return dto.DayChange{
+		DayID:     day.ID,
+		DayNumber: day.DayNumber,
+		Date:      utils.FormatDate(day.Date),
+	}
+}
+
+func activityChange(dpa *models.DayPlanActivity) dto.ActivityChange {
+	title := derefString(dpa.CustomTitle)
+	if title == "" && dpa.Activity != nil {
+		title = dpa.Activity.Title
+	}
+	return dto.ActivityChange{
+		DayPlanActivityID: dpa.ID,
+		DayID:             dpa.DayPlanID,
+		ActivityID:        dpa.ActivityID,
+		Title:             title,
+	}
+}
+
+func destinationChange(td *models.TripDestination) dto.DestinationChange {
+	change := dto.DestinationChange{DestinationID: td.DestinationID}
+	if td.Destination != nil {
+		change.City = td.Destination.City
+	}
+	return change
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
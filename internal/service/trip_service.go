@@ -4,7 +4,9 @@ import (
 	"context"
 	"time"
 	"triply-server/internal/models"
+	"triply-server/internal/realtime"
 	"triply-server/internal/repository"
+	"triply-server/internal/storage"
 	"triply-server/internal/utils"
 
 	"gorm.io/gorm"
@@ -17,7 +19,7 @@ type TripService interface {
 	CreateTrip(ctx context.Context, trip *models.Trip) (*models.Trip, error)
 	UpdateTrip(ctx context.Context, trip *models.Trip) (*models.Trip, error)
 	DeleteTrip(ctx context.Context, tripID, userID string) error
-	ClonePublicTrip(ctx context.Context, publicTripID, userID, newTripName string) (*models.Trip, error)
+	ClonePublicTrip(ctx context.Context, publicTripID, userID, newTripName string, includeChecklist bool) (*models.Trip, error)
 	MigrateShadowTrips(ctx context.Context, shadowUserID, userID string) error
 	GetShadowUserTrips(ctx context.Context, shadowUserID string) ([]models.Trip, error)
 	CreateShadowTrip(ctx context.Context, trip *models.Trip, shadowUserID string) (*models.Trip, error)
@@ -28,18 +30,35 @@ type TripService interface {
 type tripService struct {
 	tripRepo       repository.TripRepository
 	publicTripRepo repository.PublicTripRepository
+	checklistRepo  repository.ChecklistRepository
+	attachmentRepo repository.TripAttachmentRepository
+	blobs          storage.BlobStore
+	events         realtime.Hub
 }
 
-// NewTripService creates a new trip service instance
-func NewTripService(tripRepo repository.TripRepository, publicTripRepo repository.PublicTripRepository) TripService {
+// NewTripService creates a new trip service instance. Deleting a trip removes its attachment
+// files from blobs.
+func NewTripService(tripRepo repository.TripRepository, publicTripRepo repository.PublicTripRepository, checklistRepo repository.ChecklistRepository, attachmentRepo repository.TripAttachmentRepository, blobs storage.BlobStore, events realtime.Hub) TripService {
 	return &tripService{
 		tripRepo:       tripRepo,
 		publicTripRepo: publicTripRepo,
+		checklistRepo:  checklistRepo,
+		attachmentRepo: attachmentRepo,
+		blobs:          blobs,
+		events:         events,
 	}
 }
 
 func (s *tripService) ListTrips(ctx context.Context, userID string) ([]models.Trip, error) {
-	return s.tripRepo.FindByUserID(ctx, userID)
+	trips, err := s.tripRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range trips {
+		localizeTrip(&trips[i])
+		attachReservations(&trips[i])
+	}
+	return trips, nil
 }
 
 func (s *tripService) GetTrip(ctx context.Context, tripID, userID string) (*models.Trip, error) {
@@ -50,6 +69,8 @@ func (s *tripService) GetTrip(ctx context.Context, tripID, userID string) (*mode
 		}
 		return nil, err
 	}
+	localizeTrip(trip)
+	attachReservations(trip)
 	return trip, nil
 }
 
@@ -63,6 +84,7 @@ func (s *tripService) CreateTrip(ctx context.Context, trip *models.Trip) (*model
 
 	trip.CreatedAt = now
 	trip.UpdatedAt = now
+	trip.Version = 1
 
 	// Set default visibility
 	if trip.Visibility == "" {
@@ -74,6 +96,10 @@ func (s *tripService) CreateTrip(ctx context.Context, trip *models.Trip) (*model
 		trip.Status = "active"
 	}
 
+	// Legs and reservations are added through their own endpoints, which validate them
+	trip.TripLegs = nil
+	trip.Reservations = nil
+
 	// Generate IDs for day plans if provided
 	for i := range trip.DayPlans {
 		if trip.DayPlans[i].ID == "" {
@@ -88,10 +114,17 @@ func (s *tripService) CreateTrip(ctx context.Context, trip *models.Trip) (*model
 		return nil, err
 	}
 
-	return trip, nil
+	return s.GetTrip(ctx, trip.ID, trip.UserID)
 }
 
+// UpdateTrip saves the trip on behalf of trip.UserID, who must own it or be an editor.
+// On success trip.UserID is reset to the trip's owner.
 func (s *tripService) UpdateTrip(ctx context.Context, trip *models.Trip) (*models.Trip, error) {
+	userID := trip.UserID
+	if _, err := requireTripRole(ctx, s.tripRepo, trip.ID, userID, models.TripRoleEditor); err != nil {
+		return nil, err
+	}
+
 	trip.UpdatedAt = time.Now()
 
 	// Generate IDs for day plans if provided
@@ -104,18 +137,50 @@ func (s *tripService) UpdateTrip(ctx context.Context, trip *models.Trip) (*model
 		trip.DayPlans[i].UpdatedAt = now
 	}
 
-	if err := s.tripRepo.Update(ctx, trip); err != nil {
+	// trip.Version carries the client's expected version (0 = no precondition)
+	if err := s.tripRepo.Update(ctx, trip, userID); err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, utils.NewNotFoundError("Trip")
 		}
+		if err == repository.ErrVersionConflict {
+			current, findErr := s.GetTrip(ctx, trip.ID, userID)
+			if findErr != nil {
+				return nil, findErr
+			}
+			return nil, utils.NewConflictError("Trip was modified by another client", current)
+		}
 		return nil, err
 	}
 
-	return trip, nil
+	// Respond with the stored trip, which has the legs, schedules and reservations the request lacks
+	updated, err := s.GetTrip(ctx, trip.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	publishTripEvent(ctx, s.events, trip.ID, userID, realtime.EventTripUpdated, updated)
+	return updated, nil
 }
 
 func (s *tripService) DeleteTrip(ctx context.Context, tripID, userID string) error {
-	return s.tripRepo.Delete(ctx, tripID, userID)
+	if _, err := requireTripRole(ctx, s.tripRepo, tripID, userID, models.TripRoleOwner); err != nil {
+		return err
+	}
+	// The attachment rows go with the trip, so collect their files first
+	attachments, err := s.attachmentRepo.FindByTripID(ctx, tripID, nil)
+	if err != nil {
+		return err
+	}
+	if err := s.tripRepo.Delete(ctx, tripID, userID); err != nil {
+		return err
+	}
+	for _, attachment := range attachments {
+		_ = s.blobs.Delete(ctx, attachment.StorageKey)
+	}
+
+	// trip.deleted is the last event its streams carry
+	publishTripEvent(ctx, s.events, tripID, userID, realtime.EventTripDeleted, nil)
+	s.events.Disconnect(tripID, "")
+	return nil
 }
 
 func (s *tripService) MigrateShadowTrips(ctx context.Context, shadowUserID, userID string) error {
@@ -123,7 +188,8 @@ func (s *tripService) MigrateShadowTrips(ctx context.Context, shadowUserID, user
 }
 
 func (s *tripService) GetShadowUserTrips(ctx context.Context, shadowUserID string) ([]models.Trip, error) {
-	return s.tripRepo.FindByShadowUserID(ctx, shadowUserID)
+	// Shadow trips are stored with the shadow user ID as the user_id
+	return s.ListTrips(ctx, shadowUserID)
 }
 
 func (s *tripService) CreateShadowTrip(ctx context.Context, trip *models.Trip, shadowUserID string) (*models.Trip, error) {
@@ -138,10 +204,11 @@ func (s *tripService) UpdateShadowTrip(ctx context.Context, trip *models.Trip, s
 }
 
 func (s *tripService) DeleteShadowTrip(ctx context.Context, tripID, shadowUserID string) error {
-	return s.tripRepo.Delete(ctx, tripID, shadowUserID)
+	// The shadow user owns its trips, so the owner check applies as for any other user
+	return s.DeleteTrip(ctx, tripID, shadowUserID)
 }
 
-func (s *tripService) ClonePublicTrip(ctx context.Context, publicTripID, userID, newTripName string) (*models.Trip, error) {
+func (s *tripService) ClonePublicTrip(ctx context.Context, publicTripID, userID, newTripName string, includeChecklist bool) (*models.Trip, error) {
 	// 1. Fetch the original public trip with all nested data
 	originalTrip, err := s.publicTripRepo.FindByID(ctx, publicTripID)
 	if err != nil {
@@ -171,6 +238,7 @@ func (s *tripService) ClonePublicTrip(ctx context.Context, publicTripID, userID,
 		Visibility:    "private", // Always start as private
 		Status:        "active",  // Start as active status
 		TravelerType:  originalTrip.TravelerType,
+		Version:       1,
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
@@ -231,12 +299,42 @@ func (s *tripService) ClonePublicTrip(ctx context.Context, publicTripID, userID,
 		}
 	}
 
-	// 6. Save the cloned trip (this will cascade to all nested entities)
+	// 6. Clone the legs between destinations, without the author's bookings
+	for _, originalLeg := range originalTrip.TripLegs {
+		clonedTrip.TripLegs = append(clonedTrip.TripLegs, models.TripLeg{
+			ID:                utils.GenerateID("leg"),
+			TripID:            clonedTrip.ID,
+			FromDestinationID: originalLeg.FromDestinationID,
+			ToDestinationID:   originalLeg.ToDestinationID,
+			Mode:              originalLeg.Mode,
+			Carrier:           originalLeg.Carrier,
+			DepartureTime:     originalLeg.DepartureTime,
+			DepartureTimezone: originalLeg.DepartureTimezone,
+			ArrivalTime:       originalLeg.ArrivalTime,
+			ArrivalTimezone:   originalLeg.ArrivalTimezone,
+			CostAmount:        originalLeg.CostAmount,
+			CostCurrency:      originalLeg.CostCurrency,
+			Notes:             originalLeg.Notes,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		})
+	}
+
+	// 7. Save the cloned trip (this will cascade to all nested entities)
 	if err := s.tripRepo.Create(ctx, clonedTrip); err != nil {
 		return nil, err
 	}
 
-	// 7. Increment the clone count on the original trip (async, don't block on error)
+	// 8. Optionally copy the author's checklists, unpacked and unassigned. A clone without
+	// them is removed again, so a retry doesn't leave a second copy behind.
+	if includeChecklist {
+		if err := s.cloneChecklists(ctx, originalTrip.ID, clonedTrip.ID, userID, now); err != nil {
+			_ = s.tripRepo.Delete(ctx, clonedTrip.ID, userID)
+			return nil, err
+		}
+	}
+
+	// 9. Increment the clone count on the original trip (async, don't block on error)
 	go func() {
 		if err := s.publicTripRepo.IncrementCloneCount(context.Background(), publicTripID); err != nil {
 			// Log error but don't fail the clone operation
@@ -246,3 +344,41 @@ func (s *tripService) ClonePublicTrip(ctx context.Context, publicTripID, userID,
 
 	return clonedTrip, nil
 }
+
+// cloneChecklists copies a trip's checklists to another trip, resetting packed state and assignees
+func (s *tripService) cloneChecklists(ctx context.Context, fromTripID, toTripID, userID string, now time.Time) error {
+	originals, err := s.checklistRepo.FindByTripID(ctx, fromTripID)
+	if err != nil || len(originals) == 0 {
+		return err
+	}
+
+	checklists := make([]*models.TripChecklist, len(originals))
+	for i, original := range originals {
+		checklist := &models.TripChecklist{
+			ID:              utils.GenerateID("chk"),
+			TripID:          toTripID,
+			Title:           original.Title,
+			Kind:            original.Kind,
+			OrderIndex:      original.OrderIndex,
+			CreatedByUserID: userID,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+			Items:           make([]models.ChecklistItem, len(original.Items)),
+		}
+		for j, item := range original.Items {
+			checklist.Items[j] = models.ChecklistItem{
+				ID:          utils.GenerateID("chi"),
+				ChecklistID: checklist.ID,
+				Title:       item.Title,
+				Category:    item.Category,
+				Quantity:    item.Quantity,
+				Notes:       item.Notes,
+				OrderIndex:  item.OrderIndex,
+				CreatedAt:   now,
+				UpdatedAt:   now,
+			}
+		}
+		checklists[i] = checklist
+	}
+	return s.checklistRepo.Create(ctx, checklists...)
+}
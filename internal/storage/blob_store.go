@@ -0,0 +1,22 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned when no blob is stored under a key
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore keeps uploaded files. Keys are slash-separated paths such as
+// "trips/trip-1/attachments/att-2". The LocalStore writes to a directory; an
+// S3-compatible store can replace it without changes to the callers.
+type BlobStore interface {
+	// Put stores the content under key, replacing any blob already there
+	Put(ctx context.Context, key string, content io.Reader, contentType string) error
+	// Open reads the blob stored under key; the caller closes it
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key; deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,33 @@
+package utils
+
+import "time"
+
+// DateLayout is the canonical YYYY-MM-DD layout used for trip and day dates
+const DateLayout = "2006-01-02"
+
+// ParseDate parses a trip or day date (RFC3339 as returned by Postgres, or YYYY-MM-DD)
+func ParseDate(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+	return time.Parse(DateLayout, value)
+}
+
+// FormatDate converts a date string to YYYY-MM-DD, returning it as-is if it can't be parsed
+func FormatDate(value string) string {
+	t, err := ParseDate(value)
+	if err != nil {
+		return value
+	}
+	return t.Format(DateLayout)
+}
+
+// AddDays shifts a date string by the given number of days and returns it as YYYY-MM-DD
+func AddDays(value string, days int) string {
+	t, err := ParseDate(value)
+	if err != nil {
+		return value
+	}
+	return t.AddDate(0, 0, days).Format(DateLayout)
+}
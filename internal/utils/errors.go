@@ -58,3 +58,22 @@ func NewInternalError(message string) *AppError {
 		Status:  500,
 	}
 }
+
+// NewConflictError creates a conflict error carrying the current server state
+func NewConflictError(message string, details interface{}) *AppError {
+	return &AppError{
+		Code:    "CONFLICT",
+		Message: message,
+		Details: details,
+		Status:  409,
+	}
+}
+
+// NewForbiddenError creates a forbidden error for authenticated callers lacking permission
+func NewForbiddenError(message string) *AppError {
+	return &AppError{
+		Code:    "FORBIDDEN",
+		Message: message,
+		Status:  403,
+	}
+}
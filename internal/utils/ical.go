@@ -0,0 +1,311 @@
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ICalDateTimeLayout is the UTC date-time layout used for DTSTART/DTEND values
+const ICalDateTimeLayout = "20060102T150405Z"
+
+// ICalDateLayout is the layout used for all-day (VALUE=DATE) values
+const ICalDateLayout = "20060102"
+
+// ICalWriter builds an RFC 5545 document with CRLF line endings and folded long lines
+type ICalWriter struct {
+	b strings.Builder
+}
+
+// Line writes a property line such as "SUMMARY:..." (value must already be escaped)
+func (w *ICalWriter) Line(name, value string) {
+	line := name + ":" + value
+
+	// Fold at 75 octets (continuation lines start with a space) without splitting a UTF-8 sequence
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !isUTF8Start(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+// Text writes a text property, escaping its value; empty values are skipped
+func (w *ICalWriter) Text(name, value string) {
+	if value == "" {
+		return
+	}
+	w.Line(name, EscapeICalText(value))
+}
+
+// Time writes a UTC date-time property; zero times are skipped
+func (w *ICalWriter) Time(name string, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	w.Line(name, t.UTC().Format(ICalDateTimeLayout))
+}
+
+// String returns the document built so far
+func (w *ICalWriter) String() string {
+	return w.b.String()
+}
+
+// EscapeICalText escapes a TEXT value per RFC 5545 section 3.3.11
+func EscapeICalText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+func isUTF8Start(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// ICalCalendar is the subset of a parsed iCalendar document used for imports
+type ICalCalendar struct {
+	Name   string // X-WR-CALNAME, if present
+	Events []ICalEvent
+}
+
+// ICalEvent is a parsed VEVENT
+type ICalEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+
+	Start  time.Time
+	End    time.Time
+	AllDay bool
+
+	// Floating is set when the times carry no timezone (no Z suffix and no usable TZID).
+	// Start/End then hold the wall-clock time in UTC and should be re-read in a local zone.
+	Floating bool
+
+	Latitude  *float64
+	Longitude *float64
+}
+
+// ParseICal parses the VEVENTs of an RFC 5545 document. Events without a DTSTART are skipped.
+func ParseICal(data string) (*ICalCalendar, error) {
+	if !strings.Contains(strings.ToUpper(data), "BEGIN:VCALENDAR") {
+		return nil, fmt.Errorf("not an iCalendar document")
+	}
+
+	// Unfold continuation lines
+	data = strings.NewReplacer("\r\n ", "", "\r\n\t", "", "\n ", "", "\n\t", "").Replace(data)
+
+	cal := &ICalCalendar{}
+	var event *ICalEvent
+	var components []string
+	var duration time.Duration
+	hasDuration := false
+
+	for _, raw := range strings.Split(data, "\n") {
+		line := strings.TrimRight(raw, "\r")
+		if line == "" {
+			continue
+		}
+		name, params, value, ok := splitICalLine(line)
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(value))
+			if strings.EqualFold(value, "VEVENT") {
+				event = &ICalEvent{}
+				hasDuration = false
+			}
+			continue
+		case "END":
+			if len(components) > 0 {
+				components = components[:len(components)-1]
+			}
+			if strings.EqualFold(value, "VEVENT") && event != nil {
+				if !event.Start.IsZero() {
+					if event.End.IsZero() {
+						switch {
+						case hasDuration:
+							event.End = event.Start.Add(duration)
+						case event.AllDay:
+							event.End = event.Start.AddDate(0, 0, 1)
+						default:
+							event.End = event.Start
+						}
+					}
+					cal.Events = append(cal.Events, *event)
+				}
+				event = nil
+			}
+			continue
+		}
+
+		if len(components) == 0 {
+			continue
+		}
+		current := components[len(components)-1]
+		if current == "VCALENDAR" && name == "X-WR-CALNAME" {
+			cal.Name = UnescapeICalText(value)
+			continue
+		}
+		// Ignore properties of nested components such as VALARM
+		if current != "VEVENT" || event == nil {
+			continue
+		}
+
+		switch name {
+		case "UID":
+			event.UID = value
+		case "SUMMARY":
+			event.Summary = UnescapeICalText(value)
+		case "DESCRIPTION":
+			event.Description = UnescapeICalText(value)
+		case "LOCATION":
+			event.Location = UnescapeICalText(value)
+		case "URL":
+			event.URL = value
+		case "GEO":
+			parts := strings.SplitN(value, ";", 2)
+			if len(parts) == 2 {
+				lat, latErr := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+				lon, lonErr := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+				if latErr == nil && lonErr == nil {
+					event.Latitude = &lat
+					event.Longitude = &lon
+				}
+			}
+		case "DTSTART":
+			t, allDay, floating, err := parseICalTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTSTART %q: %w", value, err)
+			}
+			event.Start, event.AllDay, event.Floating = t, allDay, floating
+		case "DTEND":
+			t, _, _, err := parseICalTime(value, params)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTEND %q: %w", value, err)
+			}
+			event.End = t
+		case "DURATION":
+			if d, err := parseICalDuration(value); err == nil {
+				duration = d
+				hasDuration = true
+			}
+		}
+	}
+
+	return cal, nil
+}
+
+// UnescapeICalText reverses EscapeICalText
+func UnescapeICalText(value string) string {
+	replacer := strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	)
+	return replacer.Replace(value)
+}
+
+// splitICalLine splits "NAME;PARAM=x;PARAM2=\"a:b\":value" into its parts
+func splitICalLine(line string) (string, map[string]string, string, bool) {
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		} else if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", false
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string)
+	for _, p := range parts[1:] {
+		if kv := strings.SplitN(p, "=", 2); len(kv) == 2 {
+			params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+		}
+	}
+	return strings.ToUpper(parts[0]), params, line[colon+1:], true
+}
+
+func parseICalTime(value string, params map[string]string) (t time.Time, allDay, floating bool, err error) {
+	if params["VALUE"] == "DATE" || len(value) == len(ICalDateLayout) {
+		t, err = time.Parse(ICalDateLayout, value)
+		return t, true, false, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err = time.Parse(ICalDateTimeLayout, value)
+		return t, false, false, err
+	}
+
+	const localLayout = "20060102T150405"
+	if tzid := params["TZID"]; tzid != "" {
+		if loc, locErr := time.LoadLocation(tzid); locErr == nil {
+			t, err = time.ParseInLocation(localLayout, value, loc)
+			return t, false, false, err
+		}
+	}
+	t, err = time.Parse(localLayout, value)
+	return t, false, true, err
+}
+
+// parseICalDuration parses durations such as PT1H30M, P1D or P2W
+func parseICalDuration(value string) (time.Duration, error) {
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "+"), "P")
+	var total time.Duration
+	inTime := false
+	num := ""
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			num += string(r)
+		case r == 'T':
+			inTime = true
+		default:
+			n, err := strconv.Atoi(num)
+			if err != nil {
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+			num = ""
+			switch {
+			case r == 'W':
+				total += time.Duration(n) * 7 * 24 * time.Hour
+			case r == 'D':
+				total += time.Duration(n) * 24 * time.Hour
+			case r == 'H' && inTime:
+				total += time.Duration(n) * time.Hour
+			case r == 'M' && inTime:
+				total += time.Duration(n) * time.Minute
+			case r == 'S' && inTime:
+				total += time.Duration(n) * time.Second
+			default:
+				return 0, fmt.Errorf("invalid duration %q", value)
+			}
+		}
+	}
+	return total, nil
+}
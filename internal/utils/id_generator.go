@@ -3,6 +3,7 @@ package utils
 import (
 	"crypto/rand"
 	"encoding/base32"
+	"encoding/hex"
 	"strconv"
 	"strings"
 	"time"
@@ -26,3 +27,10 @@ func EnsureTripIDs(trip interface{}) {
 	// This will be implemented with actual trip entity
 	// For now, this is a placeholder
 }
+
+// GenerateToken returns a random, URL-safe secret of the given number of bytes (hex encoded)
+func GenerateToken(size int) string {
+	randomBytes := make([]byte, size)
+	rand.Read(randomBytes)
+	return hex.EncodeToString(randomBytes)
+}
@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// SignExpiring signs a value until the expiry time, for links that grant temporary
+// access without a session. The signature is hex encoded.
+func SignExpiring(value, secret string, expires time.Time) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(value + "\n" + strconv.FormatInt(expires.Unix(), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyExpiring checks a signature made by SignExpiring and that its expiry
+// (Unix seconds) has not passed
+func VerifyExpiring(value, secret string, expires int64, signature string, now time.Time) bool {
+	if now.Unix() > expires {
+		return false
+	}
+	expected := SignExpiring(value, secret, time.Unix(expires, 0))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// DeriveKey derives a key for one purpose from a master secret, so signatures made
+// for one purpose can't be replayed for another
+func DeriveKey(secret, purpose string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return hex.EncodeToString(mac.Sum(nil))
+}